@@ -25,6 +25,19 @@ type Hook struct {
 	// Webhook invocation to arrive at desired state
 	Webhook *Webhook `json:"webhook,omitempty"`
 
+	// GRPC invocation to arrive at desired state
+	GRPC *GRPC `json:"grpc,omitempty"`
+
+	// Exec invocation of a local command to arrive at desired
+	// state
+	Exec *Exec `json:"exec,omitempty"`
+
+	// Jsonnet evaluation within metac to arrive at desired state
+	Jsonnet *Jsonnet `json:"jsonnet,omitempty"`
+
+	// Starlark evaluation within metac to arrive at desired state
+	Starlark *Starlark `json:"starlark,omitempty"`
+
 	// Inline invocation to arrive at desired state
 	Inline *Inline `json:"inline,omitempty"`
 }
@@ -37,6 +50,224 @@ type Webhook struct {
 
 	Path    *string           `json:"path,omitempty"`
 	Service *ServiceReference `json:"service,omitempty"`
+
+	// CABundle is a PEM encoded CA bundle used to verify the
+	// webhook server's certificate. System trust roots are used
+	// if this is not set.
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// ServerName overrides the host name used to verify the
+	// webhook server's certificate
+	ServerName *string `json:"serverName,omitempty"`
+
+	// SecretRef refers to the Secret that holds the credentials
+	// used by metac to authenticate itself with the webhook server
+	//
+	// NOTE:
+	//	Secret may hold a client certificate & key against the keys
+	// 'tls.crt' & 'tls.key' and / or a bearer token against the key
+	// 'token'
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+
+	// RetryPolicy decides if & how a failed invocation of this
+	// webhook is retried before reporting the failure
+	RetryPolicy *WebhookRetryPolicy `json:"retryPolicy,omitempty"`
+
+	// CircuitBreaker short-circuits the invocations of this
+	// webhook after it fails consecutively
+	CircuitBreaker *WebhookCircuitBreaker `json:"circuitBreaker,omitempty"`
+}
+
+// WebhookRetryPolicy defines the retries of a failed webhook
+// invocation
+type WebhookRetryPolicy struct {
+	// MaxAttempts is the maximum number of invocations including
+	// the first one. Defaults to 1 i.e. no retries.
+	MaxAttempts *int32 `json:"maxAttempts,omitempty"`
+
+	// Backoff is the time to wait before the first retry. This
+	// doubles with every subsequent retry. Defaults to 500ms.
+	Backoff *metav1.Duration `json:"backoff,omitempty"`
+
+	// MaxBackoff is the maximum time to wait between retries.
+	// Defaults to 10s.
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// RetryableStatusCodes are the HTTP response status codes that
+	// are retried. Defaults to 429, 502, 503 & 504.
+	//
+	// NOTE:
+	//	Invocations that fail without any response e.g. connection
+	// refused or timeouts are always retried
+	RetryableStatusCodes []int32 `json:"retryableStatusCodes,omitempty"`
+}
+
+// WebhookCircuitBreaker defines when the invocations of a webhook
+// are short-circuited
+//
+// NOTE:
+//	Circuit breaker is shared by all the hooks with the same URL
+type WebhookCircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failed
+	// invocations after which the circuit opens. Defaults to 5.
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+
+	// OpenDuration is the time for which the circuit stays open
+	// before a trial invocation is allowed. Defaults to 30s.
+	OpenDuration *metav1.Duration `json:"openDuration,omitempty"`
+}
+
+// SecretReference refers to a Secret by its name & namespace
+type SecretReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+}
+
+// GRPC refers to the logic that gets invoked as a gRPC service
+// to arrive at the desired state
+//
+// NOTE:
+//	The gRPC server is expected to implement the services that
+// are published in hooks/grpchook/hook.proto
+type GRPC struct {
+	// Address of the gRPC server in host:port format. This
+	// overrides Service if set.
+	Address *string `json:"address,omitempty"`
+
+	// Service refers to the Kubernetes Service of the gRPC server
+	Service *ServiceReference `json:"service,omitempty"`
+
+	// Method is the full name of the gRPC method that gets invoked
+	// e.g. '/metac.hooks.v1.GenericHook/Sync'. This defaults to
+	// the method that matches the controller & the hook.
+	Method *string `json:"method,omitempty"`
+
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+
+	// TLS when set to true connects to the gRPC server over TLS.
+	// This defaults to true if CABundle or ServerName is set.
+	TLS *bool `json:"tls,omitempty"`
+
+	// CABundle is a PEM encoded CA bundle used to verify the
+	// gRPC server's certificate. System trust roots are used
+	// if this is not set.
+	CABundle []byte `json:"caBundle,omitempty"`
+
+	// ServerName overrides the host name used to verify the
+	// gRPC server's certificate
+	ServerName *string `json:"serverName,omitempty"`
+
+	// SecretRef refers to the Secret that holds the credentials
+	// used by metac to authenticate itself with the gRPC server
+	//
+	// NOTE:
+	//	Secret may hold a client certificate & key against the keys
+	// 'tls.crt' & 'tls.key' and / or a bearer token against the key
+	// 'token'
+	SecretRef *SecretReference `json:"secretRef,omitempty"`
+
+	// MaxMessageSize is the maximum size in bytes of the request
+	// & the response messages. Defaults to 64MiB.
+	MaxMessageSize *int32 `json:"maxMessageSize,omitempty"`
+
+	// Compression compresses the request & the response messages
+	// if set. Supported value is 'gzip'.
+	Compression *string `json:"compression,omitempty"`
+}
+
+// Exec refers to the logic that gets invoked as a local command
+// to arrive at the desired state. The hook request is written to
+// the command's stdin as JSON & the hook response is read from its
+// stdout as JSON.
+//
+// NOTE:
+//	This is supported when metac runs in config mode only i.e. when
+// the hooks are defined via config files
+type Exec struct {
+	// Command is the name or the path of the executable
+	Command string `json:"command"`
+
+	// Args are the arguments passed to the command
+	Args []string `json:"args,omitempty"`
+
+	// Env are the environment variables set for the command in
+	// addition to the ones of metac
+	Env []ExecEnvVar `json:"env,omitempty"`
+
+	// WorkingDir is the working directory of the command. This
+	// defaults to the working directory of metac.
+	WorkingDir *string `json:"workingDir,omitempty"`
+
+	// Timeout after which the command is killed. Defaults to 10s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ExecEnvVar is an environment variable set for an exec hook
+type ExecEnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+}
+
+// Jsonnet refers to the logic that gets evaluated as a Jsonnet
+// program within metac to arrive at the desired state. The hook
+// request is passed to this program as the top level argument
+// named 'request' & the program's output is the hook response.
+type Jsonnet struct {
+	// Source is the Jsonnet program. This overrides ConfigMapRef
+	// if set.
+	Source *string `json:"source,omitempty"`
+
+	// ConfigMapRef refers to the ConfigMap key that holds the
+	// Jsonnet program
+	//
+	// NOTE:
+	//	ConfigMap is watched & read from a cache so that an
+	// updated program is picked up without restarting metac
+	ConfigMapRef *ConfigMapKeyReference `json:"configMapRef,omitempty"`
+
+	// Timeout after which the evaluation of the program is abandoned.
+	// Defaults to 10s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// ConfigMapKeyReference refers to a key of a ConfigMap by the
+// ConfigMap's name & namespace
+type ConfigMapKeyReference struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+}
+
+// Starlark refers to the logic that gets evaluated as a Starlark
+// script within metac to arrive at the desired state. The script
+// defines a function per hook e.g. 'sync(request)' & 'finalize(request)'
+// that returns the hook response.
+type Starlark struct {
+	// Source is the Starlark script. This overrides ConfigMapRef
+	// if set.
+	Source *string `json:"source,omitempty"`
+
+	// ConfigMapRef refers to the ConfigMap key that holds the
+	// Starlark script
+	//
+	// NOTE:
+	//	ConfigMap is watched & read from a cache so that an
+	// updated script is picked up without restarting metac
+	ConfigMapRef *ConfigMapKeyReference `json:"configMapRef,omitempty"`
+
+	// Function is the name of the script's function that gets
+	// invoked. This defaults to the function that matches the
+	// hook e.g. 'sync' or 'finalize'.
+	Function *string `json:"function,omitempty"`
+
+	// MaxExecutionSteps is the maximum number of Starlark
+	// computation steps of a single invocation. Defaults to 10000000.
+	MaxExecutionSteps *int64 `json:"maxExecutionSteps,omitempty"`
+
+	// MaxMemory is the maximum estimated size in bytes of any
+	// string or collection built by a single invocation. Defaults
+	// to 64MiB.
+	MaxMemory *int64 `json:"maxMemory,omitempty"`
 }
 
 // Inline refers to the logic that gets invoked as inline
@@ -49,6 +49,18 @@ type CompositeControllerSpec struct {
 
 	ResyncPeriodSeconds *int32 `json:"resyncPeriodSeconds,omitempty"`
 	GenerateSelector    *bool  `json:"generateSelector,omitempty"`
+
+	// WorkerCount is the number of workers reconciling the parents
+	// in parallel. Defaults to the worker count of metac.
+	WorkerCount *int32 `json:"workerCount,omitempty"`
+
+	// RateLimiter configures the rate at which the parents are
+	// requeued
+	RateLimiter *WorkqueueRateLimiter `json:"rateLimiter,omitempty"`
+
+	// ClientRateLimit configures a dedicated kubernetes client for
+	// this controller
+	ClientRateLimit *ClientRateLimit `json:"clientRateLimit,omitempty"`
 }
 
 // ResourceRule helps in identifying the type of the API resource
@@ -62,6 +74,60 @@ type ResourceRule struct {
 	Resource string `json:"resource"`
 }
 
+// RelatedResourceRule selects the objects of a resource that are
+// related to a parent. These rules are returned by the customize
+// hook.
+//
+// NOTE:
+//	Related objects of a namespaced parent are selected from the
+// parent's namespace only. Namespace if set must match the
+// parent's namespace in this case.
+type RelatedResourceRule struct {
+	ResourceRule `json:",inline"`
+
+	// Include the objects if label selector matches
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Include the objects of this namespace only
+	Namespace string `json:"namespace,omitempty"`
+
+	// Include the objects with these names only
+	Names []string `json:"names,omitempty"`
+}
+
+// WorkqueueRateLimiter configures the rate at which the keys of a
+// controller are requeued. A failed key is requeued after an
+// exponential per key backoff that is further limited by an overall
+// token bucket.
+type WorkqueueRateLimiter struct {
+	// BaseDelay is the backoff of the first retry of a failed key.
+	// Defaults to 5ms.
+	BaseDelay *metav1.Duration `json:"baseDelay,omitempty"`
+
+	// MaxDelay caps the backoff of a failed key. Defaults to 1000s.
+	MaxDelay *metav1.Duration `json:"maxDelay,omitempty"`
+
+	// QPS is the refill rate of the overall token bucket. Defaults
+	// to 10.
+	QPS *int32 `json:"qps,omitempty"`
+
+	// Burst is the size of the overall token bucket. Defaults to 100.
+	Burst *int32 `json:"burst,omitempty"`
+}
+
+// ClientRateLimit configures a dedicated kubernetes client for a
+// controller. The client shared by all the controllers is used if
+// this is not set.
+type ClientRateLimit struct {
+	// QPS is the maximum queries per second to the kubernetes API
+	// server. Defaults to the QPS of the shared client.
+	QPS *int32 `json:"qps,omitempty"`
+
+	// Burst is the maximum burst of queries to the kubernetes API
+	// server. Defaults to the burst of the shared client.
+	Burst *int32 `json:"burst,omitempty"`
+}
+
 type CompositeControllerParentResourceRule struct {
 	ResourceRule    `json:",inline"`
 	RevisionHistory *CompositeControllerRevisionHistory `json:"revisionHistory,omitempty"`
@@ -126,6 +192,10 @@ type CompositeControllerHooks struct {
 	Sync     *Hook `json:"sync,omitempty"`
 	Finalize *Hook `json:"finalize,omitempty"`
 
+	// Customize hook if set decides the related resources that
+	// are sent to sync & finalize hooks of a parent
+	Customize *Hook `json:"customize,omitempty"`
+
 	PreUpdateChild  *Hook `json:"preUpdateChild,omitempty"`
 	PostUpdateChild *Hook `json:"postUpdateChild,omitempty"`
 }
@@ -196,6 +266,18 @@ type DecoratorControllerSpec struct {
 	Hooks *DecoratorControllerHooks `json:"hooks,omitempty"`
 
 	ResyncPeriodSeconds *int32 `json:"resyncPeriodSeconds,omitempty"`
+
+	// WorkerCount is the number of workers reconciling the parents
+	// in parallel. Defaults to the worker count of metac.
+	WorkerCount *int32 `json:"workerCount,omitempty"`
+
+	// RateLimiter configures the rate at which the parents are
+	// requeued
+	RateLimiter *WorkqueueRateLimiter `json:"rateLimiter,omitempty"`
+
+	// ClientRateLimit configures a dedicated kubernetes client for
+	// this controller
+	ClientRateLimit *ClientRateLimit `json:"clientRateLimit,omitempty"`
 }
 
 type DecoratorControllerResourceRule struct {
@@ -221,6 +303,10 @@ type DecoratorControllerAttachmentUpdateStrategy struct {
 type DecoratorControllerHooks struct {
 	Sync     *Hook `json:"sync,omitempty"`
 	Finalize *Hook `json:"finalize,omitempty"`
+
+	// Customize hook if set decides the related resources that
+	// are sent to sync & finalize hooks of a parent
+	Customize *Hook `json:"customize,omitempty"`
 }
 
 type DecoratorControllerStatus struct {
@@ -26,6 +26,9 @@ import (
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced,shortName=gctl
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase"
+// +kubebuilder:printcolumn:name="HookFailures",type="integer",JSONPath=".status.hookFailureCount"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // GenericController defines GenericController API schema
 type GenericController struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -121,6 +124,50 @@ type GenericControllerSpec struct {
 	// NOTE:
 	//	This is optional
 	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Namespaces restricts the watch & attachments to these
+	// namespaces. Informers are namespaced when this is set.
+	//
+	// NOTE:
+	//	This is optional. Cluster scoped resources are not
+	// restricted.
+	//
+	// NOTE:
+	//	This must be a subset of the namespaces metac is restricted
+	// to, if any
+	Namespaces []string `json:"namespaces,omitempty"`
+
+	// WorkerCount is the number of workers reconciling the watches
+	// in parallel.
+	//
+	// NOTE:
+	//	This is optional. Defaults to the worker count of metac.
+	WorkerCount *int32 `json:"workerCount,omitempty"`
+
+	// RateLimiter configures the rate at which the watches are
+	// requeued.
+	//
+	// NOTE:
+	//	This is optional
+	RateLimiter *WorkqueueRateLimiter `json:"rateLimiter,omitempty"`
+
+	// ClientRateLimit configures a dedicated kubernetes client for
+	// this controller. This prevents a chatty controller from
+	// starving the other controllers that share the same client.
+	//
+	// NOTE:
+	//	This is optional
+	ClientRateLimit *ClientRateLimit `json:"clientRateLimit,omitempty"`
+
+	// DryRun when set to true computes the creates, updates &
+	// deletes of the watch & attachments without applying them.
+	// These changes are logged, emitted as events & summarised
+	// in the status.
+	//
+	// NOTE:
+	//	This is optional. This is useful to roll out a new sync
+	// hook safely.
+	DryRun *bool `json:"dryRun,omitempty"`
 }
 
 // GenericControllerHooks holds the sync as well as finalize hooks
@@ -186,6 +233,15 @@ type GenericControllerAttachment struct {
 	// UpdateStrategy to be used for the resource to take into
 	// account the changes due to sync/finalize
 	UpdateStrategy *GenericControllerAttachmentUpdateStrategy `json:"updateStrategy,omitempty"`
+
+	// SkipWatchResync when set to true does not resync the watch(es)
+	// when this attachment is added, updated or deleted.
+	//
+	// NOTE:
+	//	This is useful for attachment kinds that change very
+	// frequently. Such attachments are observed by the watch
+	// during its next resync or update.
+	SkipWatchResync *bool `json:"skipWatchResync,omitempty"`
 }
 
 // GenericControllerAttachmentUpdateStrategy represents the update
@@ -216,12 +272,57 @@ const (
 	// GenericControllerStatusPhaseError is used to indicate Error
 	// state of GenericController
 	GenericControllerStatusPhaseError GenericControllerStatusPhase = "Error"
+
+	// GenericControllerStatusPhaseInProgress is used to indicate
+	// GenericController is waiting for its informers to sync
+	GenericControllerStatusPhaseInProgress GenericControllerStatusPhase = "InProgress"
 )
 
 // GenericControllerStatus represents the current state of this controller
 type GenericControllerStatus struct {
 	Phase      GenericControllerStatusPhase `json:"phase"`
 	Conditions []GenericControllerCondition `json:"conditions,omitempty"`
+
+	// HookFailureCount is the number of sync & finalize hook
+	// invocations that failed since this controller was last
+	// started
+	HookFailureCount int64 `json:"hookFailureCount,omitempty"`
+
+	// DryRun summarises the changes that were computed but not
+	// applied since this controller runs in dry run mode
+	DryRun *GenericControllerDryRunStatus `json:"dryRun,omitempty"`
+}
+
+// GenericControllerDryRunStatus summarises the changes that would
+// be applied if this controller was not running in dry run mode.
+// These are the changes computed during the last sync of every
+// watch.
+type GenericControllerDryRunStatus struct {
+	// Creates is the number of resources that would be created
+	Creates int64 `json:"creates"`
+
+	// Updates is the number of resources that would be updated
+	Updates int64 `json:"updates"`
+
+	// Deletes is the number of resources that would be deleted
+	Deletes int64 `json:"deletes"`
+
+	// Changes lists some of these changes. The number of changes
+	// listed here is bounded.
+	Changes []GenericControllerDryRunChange `json:"changes,omitempty"`
+}
+
+// GenericControllerDryRunChange is a change that would be applied
+// if this controller was not running in dry run mode
+type GenericControllerDryRunChange struct {
+	// Watch that resulted in this change
+	Watch string `json:"watch"`
+
+	// Operation is one of create, update or delete
+	Operation string `json:"operation"`
+
+	// Object that would be changed
+	Object string `json:"object"`
 }
 
 // GenericControllerConditionState represents various execution states
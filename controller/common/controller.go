@@ -21,15 +21,19 @@ import (
 	"strings"
 
 	"github.com/golang/glog"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/tools/record"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	dynamicapply "openebs.io/metac/dynamic/apply"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
+	"openebs.io/metac/events"
+	"openebs.io/metac/metrics"
 	"openebs.io/metac/third_party/kubernetes"
 )
 
@@ -93,6 +97,14 @@ type ClusterStatesControllerBase struct {
 	// update the resource even if this resource is pending
 	// deletion
 	UpdateDuringPendingDelete *bool
+
+	// EventRecorder if set emits events against the watch for
+	// every create, update & delete of a resource
+	EventRecorder record.EventRecorder
+
+	// DryRun if set collects the creates, updates & deletes of
+	// resources instead of executing them against the cluster
+	DryRun *DryRun
 }
 
 // ClusterStatesController **applies** resources in Kubernetes cluster.
@@ -445,6 +457,74 @@ func (e ResourceStatesController) IsUpdateDuringPendingDelete() bool {
 	return *e.UpdateDuringPendingDelete
 }
 
+// recordEvent emits an event against the watch based on the
+// result of the provided operation against the given resource
+func (e *ResourceStatesController) recordEvent(
+	operation string,
+	obj *unstructured.Unstructured,
+	err error,
+) {
+	if e.EventRecorder == nil || e.Watch == nil {
+		return
+	}
+	if operation == metrics.OperationDelete && apierrors.IsNotFound(err) {
+		// resource is already deleted
+		return
+	}
+	var reason, failedReason string
+	switch operation {
+	case metrics.OperationCreate:
+		reason, failedReason = events.ReasonCreated, events.ReasonCreateFailed
+	case metrics.OperationUpdate:
+		reason, failedReason = events.ReasonUpdated, events.ReasonUpdateFailed
+	case metrics.OperationDelete:
+		reason, failedReason = events.ReasonDeleted, events.ReasonDeleteFailed
+	default:
+		return
+	}
+	if err != nil {
+		events.Warningf(
+			e.EventRecorder,
+			e.Watch,
+			failedReason,
+			"Failed to %s %s: %v",
+			operation,
+			DescObjectAsKey(obj),
+			err,
+		)
+		return
+	}
+	events.Normalf(
+		e.EventRecorder,
+		e.Watch,
+		reason,
+		"%s %s",
+		reason,
+		DescObjectAsKey(obj),
+	)
+}
+
+// recordDryRun remembers the provided operation that was not
+// executed due to dry run & emits an event against the watch
+func (e *ResourceStatesController) recordDryRun(
+	operation string,
+	obj *unstructured.Unstructured,
+	diff string,
+) {
+	e.DryRun.Record(operation, obj, diff)
+	if e.EventRecorder == nil || e.Watch == nil {
+		return
+	}
+	events.Normalf(
+		e.EventRecorder,
+		e.Watch,
+		events.ReasonDryRun,
+		"Skipped %s of %s",
+		operation,
+		DescObjectAsKey(obj),
+	)
+}
+
 // Update updates the observed state to its desired state
 //
 // NOTE:
@@ -591,6 +671,17 @@ func (e *ResourceStatesController) update(
 	// Act based on the update strategy for this child kind.
 	switch method {
 	case v1alpha1.ChildUpdateRecreate, v1alpha1.ChildUpdateRollingRecreate:
+		if e.DryRun.IsEnabled() {
+			e.recordDryRun(
+				metrics.OperationDelete,
+				desired,
+				cmp.Diff(
+					observed.UnstructuredContent(),
+					mergedObj.UnstructuredContent(),
+				),
+			)
+			return false, nil
+		}
 		// Delete the object (now) and recreate it (on the next sync).
 		glog.V(4).Infof(
 			"Deleting %s for update: %s",
@@ -609,6 +700,12 @@ func (e *ResourceStatesController) update(
 				PropagationPolicy: &propagation,
 			},
 		)
+		metrics.RecordResourceOperation(
+			metrics.OperationDelete,
+			e.DynamicClient.Kind,
+			err,
+		)
+		e.recordEvent(metrics.OperationDelete, desired, err)
 		if err != nil {
 			return false, err
 		}
@@ -636,14 +733,38 @@ func (e *ResourceStatesController) update(
 			DescObjectAsSanitisedKey(e.Watch)
 		mergedObj.SetAnnotations(updatedAnns)
 
+		if e.DryRun.IsEnabled() {
+			e.recordDryRun(
+				metrics.OperationUpdate,
+				desired,
+				cmp.Diff(
+					observed.UnstructuredContent(),
+					mergedObj.UnstructuredContent(),
+				),
+			)
+			return false, nil
+		}
+
 		// update the merged state at the cluster
-		_, err := e.DynamicClient.Namespace(ns).Update(
+		updated, err := e.DynamicClient.Namespace(ns).Update(
 			mergedObj,
 			metav1.UpdateOptions{},
 		)
+		metrics.RecordResourceOperation(
+			metrics.OperationUpdate,
+			e.DynamicClient.Kind,
+			err,
+		)
 		if err != nil {
+			e.recordEvent(metrics.OperationUpdate, desired, err)
 			return false, err
 		}
+		// an update that did not change the resource version is a
+		// no-op at the cluster & hence is not reported
+		if updated == nil ||
+			updated.GetResourceVersion() != observed.GetResourceVersion() {
+			e.recordEvent(metrics.OperationUpdate, desired, nil)
+		}
 
 		glog.V(6).Infof(
 			"Updated %s: %s",
@@ -714,12 +835,23 @@ func (e *ResourceStatesController) create(desired *unstructured.Unstructured) er
 		desired.SetOwnerReferences(ownerRefs)
 	}
 
+	if e.DryRun.IsEnabled() {
+		e.recordDryRun(metrics.OperationCreate, desired, "")
+		return nil
+	}
+
 	_, err = e.DynamicClient.
 		Namespace(ns).
 		Create(
 			desired,
 			metav1.CreateOptions{},
 		)
+	metrics.RecordResourceOperation(
+		metrics.OperationCreate,
+		e.DynamicClient.Kind,
+		err,
+	)
+	e.recordEvent(metrics.OperationCreate, desired, err)
 	if err != nil {
 		return err
 	}
@@ -838,6 +970,10 @@ func (e *ResourceStatesController) Delete() error {
 
 			// This observed object wasn't listed as desired.
 			// Hence, this is the right candidate to be deleted.
+			if e.DryRun.IsEnabled() {
+				e.recordDryRun(metrics.OperationDelete, obj, "")
+				continue
+			}
 			glog.V(4).Infof(
 				"Deleting %s: %s",
 				DescObjectAsKey(obj),
@@ -855,6 +991,12 @@ func (e *ResourceStatesController) Delete() error {
 					PropagationPolicy: &propagation,
 				},
 			)
+			metrics.RecordResourceOperation(
+				metrics.OperationDelete,
+				e.DynamicClient.Kind,
+				err,
+			)
+			e.recordEvent(metrics.OperationDelete, obj, err)
 			if err != nil {
 				if apierrors.IsNotFound(err) {
 					glog.V(4).Infof(
@@ -914,6 +1056,10 @@ func (e *ResourceStatesController) ExplicitDelete() error {
 			continue
 		}
 		// observed object is listed for explicit delete.
+		if e.DryRun.IsEnabled() {
+			e.recordDryRun(metrics.OperationDelete, obj, "")
+			continue
+		}
 		glog.V(4).Infof(
 			"Will explicitly delete %s: %s",
 			DescObjectAsKey(obj),
@@ -931,6 +1077,12 @@ func (e *ResourceStatesController) ExplicitDelete() error {
 				PropagationPolicy: &propagation,
 			},
 		)
+		metrics.RecordResourceOperation(
+			metrics.OperationDelete,
+			e.DynamicClient.Kind,
+			err,
+		)
+		e.recordEvent(metrics.OperationDelete, obj, err)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				glog.V(4).Infof(
@@ -0,0 +1,152 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// informerSyncTimeout is the maximum time to wait for informers
+// to sync before the operation that needs them fails
+const informerSyncTimeout = 10 * time.Second
+
+// coreInformers serves core resources e.g. Secrets & ConfigMaps from
+// informers that are started lazily per namespace
+//
+// NOTE:
+//	Informer of a namespace is started when a resource of this
+// namespace is looked up for the first time. Subsequent lookups are
+// served from the informer's cache instead of the API server.
+type coreInformers struct {
+	client kubernetes.Interface
+
+	// mutex guards the factories
+	mutex sync.Mutex
+
+	// informer factories anchored by their namespace
+	factories map[string]informers.SharedInformerFactory
+
+	// stopCh stops all the informers once closed
+	stopCh chan struct{}
+}
+
+// newCoreInformers returns a new instance of coreInformers
+func newCoreInformers(client kubernetes.Interface) *coreInformers {
+	return &coreInformers{
+		client:    client,
+		factories: make(map[string]informers.SharedInformerFactory),
+		stopCh:    make(chan struct{}),
+	}
+}
+
+// waitForInformer starts the informer returned by the given
+// function for the given namespace & waits for this informer to sync
+func (c *coreInformers) waitForInformer(
+	namespace string,
+	informerFn func(informers.SharedInformerFactory) cache.SharedIndexInformer,
+) (informers.SharedInformerFactory, error) {
+	c.mutex.Lock()
+	factory, found := c.factories[namespace]
+	if !found {
+		factory = informers.NewSharedInformerFactoryWithOptions(
+			c.client,
+			0,
+			informers.WithNamespace(namespace),
+		)
+		c.factories[namespace] = factory
+	}
+	informer := informerFn(factory)
+	// starts only the informers that are not yet started
+	factory.Start(c.stopCh)
+	c.mutex.Unlock()
+
+	if !waitForCacheSync(c.stopCh, informerSyncTimeout, informer.HasSynced) {
+		return nil, errors.Errorf(
+			"Informer of namespace %q didn't sync within %s",
+			namespace,
+			informerSyncTimeout,
+		)
+	}
+	return factory, nil
+}
+
+// getSecret returns the Secret with the given namespace & name
+func (c *coreInformers) getSecret(namespace, name string) (*corev1.Secret, error) {
+	factory, err := c.waitForInformer(
+		namespace,
+		func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Secrets().Informer()
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return factory.Core().V1().Secrets().Lister().Secrets(namespace).Get(name)
+}
+
+// getConfigMap returns the ConfigMap with the given namespace & name
+func (c *coreInformers) getConfigMap(namespace, name string) (*corev1.ConfigMap, error) {
+	factory, err := c.waitForInformer(
+		namespace,
+		func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().ConfigMaps().Informer()
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	return factory.Core().V1().ConfigMaps().Lister().ConfigMaps(namespace).Get(name)
+}
+
+// stop stops all the informers
+func (c *coreInformers) stop() {
+	close(c.stopCh)
+}
+
+// waitForCacheSync waits for the given informers to sync. It returns
+// false if these informers are not synced within the given timeout
+// or before the given stop channel is closed.
+func waitForCacheSync(
+	stopCh <-chan struct{},
+	timeout time.Duration,
+	synced ...cache.InformerSynced,
+) bool {
+	syncStopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	go func() {
+		select {
+		case <-stopCh:
+		case <-timer.C:
+		case <-doneCh:
+			return
+		}
+		close(syncStopCh)
+	}()
+	return cache.WaitForCacheSync(syncStopCh, synced...)
+}
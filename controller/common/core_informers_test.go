@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestCoreInformersGetSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "creds",
+			Namespace: "hooks",
+		},
+		Data: map[string][]byte{"token": []byte("old")},
+	}
+	client := fake.NewSimpleClientset(secret)
+	informers := newCoreInformers(client)
+	defer informers.stop()
+
+	got, err := informers.getSecret("hooks", "creds")
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if string(got.Data["token"]) != "old" {
+		t.Fatalf("Expected token %q got %q", "old", got.Data["token"])
+	}
+	_, err = informers.getSecret("hooks", "junk")
+	if err == nil {
+		t.Fatalf("Expected error for missing secret got none")
+	}
+
+	// rotated secret is served without a restart
+	rotated := secret.DeepCopy()
+	rotated.Data["token"] = []byte("new")
+	_, err = client.CoreV1().Secrets("hooks").Update(rotated)
+	if err != nil {
+		t.Fatalf("Can't update secret: %+v", err)
+	}
+	err = wait.PollImmediate(10*time.Millisecond, 5*time.Second, func() (bool, error) {
+		got, err := informers.getSecret("hooks", "creds")
+		if err != nil {
+			return false, err
+		}
+		return string(got.Data["token"]) == "new", nil
+	})
+	if err != nil {
+		t.Fatalf("Expected rotated token got %+v", err)
+	}
+}
+
+func TestWaitForCacheSync(t *testing.T) {
+	synced := func() bool { return true }
+	notSynced := func() bool { return false }
+	stopped := make(chan struct{})
+	close(stopped)
+
+	var tests = map[string]struct {
+		stopCh   chan struct{}
+		synced   []cache.InformerSynced
+		isSynced bool
+	}{
+		"synced": {
+			stopCh:   make(chan struct{}),
+			synced:   []cache.InformerSynced{synced, synced},
+			isSynced: true,
+		},
+		"not synced within timeout": {
+			stopCh: make(chan struct{}),
+			synced: []cache.InformerSynced{synced, notSynced},
+		},
+		"stopped": {
+			stopCh: stopped,
+			synced: []cache.InformerSynced{notSynced},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := waitForCacheSync(mock.stopCh, 300*time.Millisecond, mock.synced...)
+			if got != mock.isSynced {
+				t.Fatalf("Expected synced %t got %t", mock.isSynced, got)
+			}
+		})
+	}
+}
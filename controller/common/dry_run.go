@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sync"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"openebs.io/metac/metrics"
+)
+
+// DryRunChange is a create, update or delete of a resource that
+// was computed but not executed against the cluster
+type DryRunChange struct {
+	// Operation is one of create, update or delete
+	Operation string
+
+	// Object identifies the resource that would have changed
+	Object string
+
+	// Diff between the observed & the desired state of this
+	// resource. This is set for updates only.
+	Diff string
+}
+
+// DryRun collects the changes that were computed but not executed
+// against the cluster
+//
+// NOTE:
+//	All the methods are safe to be invoked on a nil DryRun. A nil
+// DryRun implies changes are executed against the cluster.
+type DryRun struct {
+	mutex   sync.Mutex
+	changes []DryRunChange
+}
+
+// NewDryRun returns a new instance of DryRun
+func NewDryRun() *DryRun {
+	return &DryRun{}
+}
+
+// IsEnabled returns true if changes should not be executed
+// against the cluster
+func (d *DryRun) IsEnabled() bool {
+	return d != nil
+}
+
+// Record logs & remembers the provided change
+func (d *DryRun) Record(
+	operation string,
+	obj *unstructured.Unstructured,
+	diff string,
+) {
+	if d == nil {
+		return
+	}
+	glog.Infof("Dry run: Will skip %s of %s", operation, DescObjectAsKey(obj))
+	if diff != "" {
+		glog.V(4).Infof("Dry run: Diff of %s:\n%s", DescObjectAsKey(obj), diff)
+	}
+	metrics.RecordDryRunOperation(operation, obj.GetKind())
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.changes = append(d.changes, DryRunChange{
+		Operation: operation,
+		Object:    DescObjectAsKey(obj),
+		Diff:      diff,
+	})
+}
+
+// Changes returns the changes recorded so far
+func (d *DryRun) Changes() []DryRunChange {
+	if d == nil {
+		return nil
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return append([]DryRunChange(nil), d.changes...)
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDryRunRecord(t *testing.T) {
+	pod := &unstructured.Unstructured{}
+	pod.SetAPIVersion("v1")
+	pod.SetKind("Pod")
+	pod.SetNamespace("default")
+	pod.SetName("test")
+
+	var tests = map[string]struct {
+		dryRun        *DryRun
+		expectEnabled bool
+		expect        []DryRunChange
+	}{
+		"nil dry run": {},
+		"dry run": {
+			dryRun:        NewDryRun(),
+			expectEnabled: true,
+			expect: []DryRunChange{
+				{Operation: "create", Object: "v1:Pod:default:test"},
+				{Operation: "update", Object: "v1:Pod:default:test", Diff: "diff"},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			if mock.dryRun.IsEnabled() != mock.expectEnabled {
+				t.Fatalf(
+					"Expected enabled %t got %t",
+					mock.expectEnabled,
+					mock.dryRun.IsEnabled(),
+				)
+			}
+			mock.dryRun.Record("create", pod, "")
+			mock.dryRun.Record("update", pod, "diff")
+			got := mock.dryRun.Changes()
+			if !reflect.DeepEqual(got, mock.expect) {
+				t.Fatalf("Expected changes %+v got %+v", mock.expect, got)
+			}
+		})
+	}
+}
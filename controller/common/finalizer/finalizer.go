@@ -21,9 +21,11 @@ import (
 	"github.com/golang/glog"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamicobject "openebs.io/metac/dynamic/object"
+	"openebs.io/metac/events"
 )
 
 // Finalizer manages updating metacontroller finalizer value
@@ -34,6 +36,10 @@ type Finalizer struct {
 
 	// Boolean that flags if finalizer should be added or removed
 	Enabled bool
+
+	// Recorder if set emits events when the finalizer is added
+	// or removed
+	Recorder record.EventRecorder
 }
 
 // SyncObject reconciles i.e. adds or removes the finalizer on
@@ -70,9 +76,47 @@ func (m *Finalizer) SyncObject(
 			)
 			return obj, nil
 		}
-		return client.Namespace(obj.GetNamespace()).AddFinalizer(obj, m.Name)
+		updated, err := client.Namespace(obj.GetNamespace()).AddFinalizer(obj, m.Name)
+		if err != nil {
+			events.Warningf(
+				m.Recorder,
+				obj,
+				events.ReasonFinalizerFailed,
+				"Failed to add finalizer %s: %v",
+				m.Name,
+				err,
+			)
+			return nil, err
+		}
+		events.Normalf(
+			m.Recorder,
+			obj,
+			events.ReasonFinalizerAdded,
+			"Added finalizer %s",
+			m.Name,
+		)
+		return updated, nil
+	}
+	updated, err := client.Namespace(obj.GetNamespace()).RemoveFinalizer(obj, m.Name)
+	if err != nil {
+		events.Warningf(
+			m.Recorder,
+			obj,
+			events.ReasonFinalizerFailed,
+			"Failed to remove finalizer %s: %v",
+			m.Name,
+			err,
+		)
+		return nil, err
 	}
-	return client.Namespace(obj.GetNamespace()).RemoveFinalizer(obj, m.Name)
+	events.Normalf(
+		m.Recorder,
+		obj,
+		events.ReasonFinalizerRemoved,
+		"Removed finalizer %s",
+		m.Name,
+	)
+	return updated, nil
 }
 
 // ShouldFinalize returns true if the controller should take action
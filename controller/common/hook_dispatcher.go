@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks"
+)
+
+// HookDispatcher invokes the hooks of meta controllers through
+// a chain of middlewares. Inline hooks are resolved from its
+// registry.
+//
+// NOTE:
+//	A nil HookDispatcher is valid. It neither has inline hooks
+// nor middlewares.
+type HookDispatcher struct {
+	// InlineHooks resolves the inline hooks by their function
+	// names
+	InlineHooks *hooks.Registry
+
+	// Middlewares wrap every hook invocation. First middleware is
+	// the outermost one.
+	Middlewares []hooks.Middleware
+}
+
+// hookTypeOf returns the implementation of the given hook
+func hookTypeOf(schema *v1alpha1.Hook) string {
+	switch {
+	case schema.Inline != nil:
+		return "inline"
+	case schema.Starlark != nil:
+		return "starlark"
+	case schema.Jsonnet != nil:
+		return "jsonnet"
+	case schema.Exec != nil:
+		return "exec"
+	case schema.GRPC != nil:
+		return "grpc"
+	default:
+		return "webhook"
+	}
+}
+
+// Dispatch invokes the given hook with the given request & fills
+// up the given response with the hook's response
+//
+// NOTE:
+//	An inline hook is looked up in this dispatcher's registry
+// first. The provided inlineFallback is invoked if the inline hook
+// is not found here. This lets the inline hooks registered at the
+// package level registries of the meta controllers work as before.
+func (d *HookDispatcher) Dispatch(
+	ctx context.Context,
+	schema *v1alpha1.Hook,
+	request, response interface{},
+	inlineFallback hooks.Handler,
+) error {
+	info := hooks.InfoFrom(ctx)
+	info.Type = hookTypeOf(schema)
+	ctx = hooks.WithInfo(ctx, info)
+
+	handler := func(ctx context.Context, request, response interface{}) error {
+		return invokeHookOf(ctx, ownerOf(info), schema, request, response)
+	}
+	if schema.Inline != nil && schema.Inline.FuncName != nil {
+		funcName := *schema.Inline.FuncName
+		inline, found := d.inlineHooks().Lookup(funcName)
+		if !found {
+			inline = inlineFallback
+		}
+		if inline == nil {
+			return errors.Errorf(
+				"Inline hook function not found for %s", funcName,
+			)
+		}
+		handler = inline
+	}
+	return hooks.Chain(handler, d.middlewares()...)(ctx, request, response)
+}
+
+// ownerOf returns the owner of the hook invoker that is used for
+// the invocation of the given info
+func ownerOf(info hooks.Info) string {
+	if info.Controller == "" {
+		return ""
+	}
+	return info.Controller + ": " + info.Hook
+}
+
+// inlineHooks returns the registry of inline hooks if any
+func (d *HookDispatcher) inlineHooks() *hooks.Registry {
+	if d == nil {
+		return nil
+	}
+	return d.InlineHooks
+}
+
+// middlewares returns the middlewares if any
+func (d *HookDispatcher) middlewares() []hooks.Middleware {
+	if d == nil {
+		return nil
+	}
+	return d.Middlewares
+}
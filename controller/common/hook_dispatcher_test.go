@@ -0,0 +1,201 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package common
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks"
+)
+
+// dispatchResponse is the response of the dispatched hooks
+type dispatchResponse struct {
+	From string `json:"from"`
+}
+
+func TestHookDispatcherDispatch(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	registry := hooks.NewRegistry()
+	err := registry.Register(
+		"registered",
+		func(ctx context.Context, _, response interface{}) error {
+			response.(*dispatchResponse).From = "registry"
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	fallback := func(_ context.Context, _, response interface{}) error {
+		response.(*dispatchResponse).From = "fallback"
+		return nil
+	}
+	source := `
+def sync(request):
+    return {"from": "starlark"}
+`
+	// typeOf records the type of the invoked hook
+	var invokedType string
+	typeOf := func(next hooks.Handler) hooks.Handler {
+		return func(ctx context.Context, request, response interface{}) error {
+			invokedType = hooks.InfoFrom(ctx).Type
+			return next(ctx, request, response)
+		}
+	}
+
+	var tests = map[string]struct {
+		dispatcher   *HookDispatcher
+		schema       *v1alpha1.Hook
+		fallback     hooks.Handler
+		expectFrom   string
+		expectedType string
+		isErr        bool
+	}{
+		"inline hook from registry": {
+			dispatcher: &HookDispatcher{
+				InlineHooks: registry,
+				Middlewares: []hooks.Middleware{typeOf},
+			},
+			schema: &v1alpha1.Hook{
+				Inline: &v1alpha1.Inline{FuncName: strPtr("registered")},
+			},
+			fallback:     fallback,
+			expectFrom:   "registry",
+			expectedType: "inline",
+		},
+		"inline hook from fallback": {
+			dispatcher: &HookDispatcher{
+				InlineHooks: registry,
+				Middlewares: []hooks.Middleware{typeOf},
+			},
+			schema: &v1alpha1.Hook{
+				Inline: &v1alpha1.Inline{FuncName: strPtr("unregistered")},
+			},
+			fallback:     fallback,
+			expectFrom:   "fallback",
+			expectedType: "inline",
+		},
+		"inline hook without fallback": {
+			dispatcher: &HookDispatcher{InlineHooks: registry},
+			schema: &v1alpha1.Hook{
+				Inline: &v1alpha1.Inline{FuncName: strPtr("unregistered")},
+			},
+			isErr: true,
+		},
+		"nil dispatcher uses fallback": {
+			schema: &v1alpha1.Hook{
+				Inline: &v1alpha1.Inline{FuncName: strPtr("registered")},
+			},
+			fallback:   fallback,
+			expectFrom: "fallback",
+		},
+		"middlewares wrap non inline hook": {
+			dispatcher: &HookDispatcher{
+				Middlewares: []hooks.Middleware{typeOf},
+			},
+			schema: &v1alpha1.Hook{
+				Starlark: &v1alpha1.Starlark{Source: &source},
+			},
+			expectFrom:   "starlark",
+			expectedType: "starlark",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			invokedType = ""
+			response := &dispatchResponse{}
+			err := mock.dispatcher.Dispatch(
+				context.Background(),
+				mock.schema,
+				map[string]interface{}{},
+				response,
+				mock.fallback,
+			)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if response.From != mock.expectFrom {
+				t.Fatalf(
+					"Expected response from %q got %q",
+					mock.expectFrom,
+					response.From,
+				)
+			}
+			if invokedType != mock.expectedType {
+				t.Fatalf(
+					"Expected hook type %q got %q",
+					mock.expectedType,
+					invokedType,
+				)
+			}
+		})
+	}
+}
+
+func TestHookDispatcherDispatchTimeoutCancelsWebhook(t *testing.T) {
+	// server responds only after the request is cancelled
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// NOTE:
+			//	Body is read so that the server notices the closed
+			// connection of the cancelled request
+			ioutil.ReadAll(r.Body)
+			select {
+			case <-r.Context().Done():
+			case <-time.After(10 * time.Second):
+			}
+			w.Write([]byte(`{"from": "webhook"}`))
+		},
+	))
+	defer server.Close()
+
+	url := server.URL
+	dispatcher := &HookDispatcher{
+		Middlewares: []hooks.Middleware{hooks.Timeout(100 * time.Millisecond)},
+	}
+	schema := &v1alpha1.Hook{
+		Webhook: &v1alpha1.Webhook{URL: &url},
+	}
+	start := time.Now()
+	err := dispatcher.Dispatch(
+		context.Background(),
+		schema,
+		map[string]interface{}{},
+		&dispatchResponse{},
+		nil,
+	)
+	if err == nil {
+		t.Fatalf("Expected error got none")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("Expected webhook to be cancelled at deadline got %s", elapsed)
+	}
+}
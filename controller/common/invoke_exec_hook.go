@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/exechook"
+)
+
+var (
+	execHooksEnabledMutex sync.RWMutex
+
+	// execHooksEnabled is true if hooks may run local commands
+	execHooksEnabled bool
+)
+
+// SetExecHooksEnabled allows or disallows hooks that run local
+// commands
+//
+// NOTE:
+//	Exec hooks run commands within metac's container. Hence these
+// should be enabled only when the hooks are defined by the ones who
+// run metac e.g. via config files. Otherwise, anyone who can create
+// a controller resource would be able to run commands in metac.
+func SetExecHooksEnabled(enabled bool) {
+	execHooksEnabledMutex.Lock()
+	defer execHooksEnabledMutex.Unlock()
+
+	execHooksEnabled = enabled
+}
+
+// isExecHooksEnabled returns true if hooks may run local commands
+func isExecHooksEnabled() bool {
+	execHooksEnabledMutex.RLock()
+	defer execHooksEnabledMutex.RUnlock()
+
+	return execHooksEnabled
+}
+
+// SetExecCommandFromSchema sets the command, its arguments, its
+// environment & its working directory against the exec invoker
+// instance
+func SetExecCommandFromSchema(schema *v1alpha1.Exec) exechook.InvokerOption {
+	return func(caller *exechook.Invoker) error {
+		if !isExecHooksEnabled() {
+			return errors.Errorf(
+				"Exec hooks are supported in config mode only: %v",
+				schema,
+			)
+		}
+		if schema.Command == "" {
+			return errors.Errorf(
+				"Invalid exec hook: Specify 'Command': %v",
+				schema,
+			)
+		}
+		caller.Command = schema.Command
+		caller.Args = schema.Args
+		for _, env := range schema.Env {
+			if env.Name == "" {
+				return errors.Errorf(
+					"Invalid exec hook env: Specify 'Name': %v",
+					schema,
+				)
+			}
+			caller.Env = append(caller.Env, env.Name+"="+env.Value)
+		}
+		if schema.WorkingDir != nil {
+			caller.WorkingDir = *schema.WorkingDir
+		}
+		return nil
+	}
+}
+
+// SetExecTimeoutFromSchemaOrDefault evaluates the exec hook's
+// timeout & sets it against the exec invoker instance
+func SetExecTimeoutFromSchemaOrDefault(schema *v1alpha1.Exec) exechook.InvokerOption {
+	return func(caller *exechook.Invoker) error {
+		if schema.Timeout == nil {
+			// defaults to the timeout of webhooks
+			caller.Timeout = 10 * time.Second
+			return nil
+		}
+		if schema.Timeout.Duration <= 0 {
+			return errors.Errorf(
+				"Invalid exec hook timeout: Must be > 0: %v",
+				schema,
+			)
+		}
+		caller.Timeout = schema.Timeout.Duration
+		return nil
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"reflect"
+	"testing"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/exechook"
+)
+
+func TestSetExecCommandFromSchema(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	var tests = map[string]struct {
+		isDisabled bool
+		schema     *v1alpha1.Exec
+		expect     *exechook.Invoker
+		isErr      bool
+	}{
+		"exec hooks disabled": {
+			isDisabled: true,
+			schema: &v1alpha1.Exec{
+				Command: "sync",
+			},
+			isErr: true,
+		},
+		"no command": {
+			schema: &v1alpha1.Exec{},
+			isErr:  true,
+		},
+		"env without name": {
+			schema: &v1alpha1.Exec{
+				Command: "sync",
+				Env: []v1alpha1.ExecEnvVar{
+					{Value: "junk"},
+				},
+			},
+			isErr: true,
+		},
+		"command with args, env & working dir": {
+			schema: &v1alpha1.Exec{
+				Command: "sync",
+				Args:    []string{"--verbose"},
+				Env: []v1alpha1.ExecEnvVar{
+					{Name: "MODE", Value: "test"},
+					{Name: "EMPTY"},
+				},
+				WorkingDir: strPtr("/hooks"),
+			},
+			expect: &exechook.Invoker{
+				Command:    "sync",
+				Args:       []string{"--verbose"},
+				Env:        []string{"MODE=test", "EMPTY="},
+				WorkingDir: "/hooks",
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			SetExecHooksEnabled(!mock.isDisabled)
+			defer SetExecHooksEnabled(false)
+
+			i := &exechook.Invoker{}
+			err := SetExecCommandFromSchema(mock.schema)(i)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if !reflect.DeepEqual(i, mock.expect) {
+				t.Fatalf("Expected invoker %+v got %+v", mock.expect, i)
+			}
+		})
+	}
+}
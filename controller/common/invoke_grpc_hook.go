@@ -0,0 +1,174 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/grpchook"
+)
+
+// SetGRPCAddressFromSchema evaluates the provided gRPC hook's
+// address & sets it against the gRPC invoker instance
+func SetGRPCAddressFromSchema(schema *v1alpha1.GRPC) grpchook.InvokerOption {
+	return func(caller *grpchook.Invoker) error {
+		if schema.Address != nil {
+			// address overrides the service
+			caller.Address = *schema.Address
+			return nil
+		}
+		if schema.Service == nil {
+			return errors.Errorf(
+				"Invalid grpc hook: Specify either 'Address' or 'Service': %v",
+				schema,
+			)
+		}
+		// cluster DNS is used to resolve the service
+		if schema.Service.Name == "" || schema.Service.Namespace == "" {
+			return errors.Errorf(
+				"Invalid grpc hook service: Specify service 'Name' & 'Namespace': %v",
+				schema,
+			)
+		}
+		port := int32(80)
+		if schema.Service.Port != nil {
+			port = *schema.Service.Port
+		}
+		caller.Address = fmt.Sprintf(
+			"%s.%s:%d",
+			schema.Service.Name,
+			schema.Service.Namespace,
+			port,
+		)
+		return nil
+	}
+}
+
+// SetGRPCMethodFromSchema sets the method that overrides the one
+// decided by the hook request against the gRPC invoker instance
+func SetGRPCMethodFromSchema(schema *v1alpha1.GRPC) grpchook.InvokerOption {
+	return func(caller *grpchook.Invoker) error {
+		if schema.Method != nil {
+			caller.Method = *schema.Method
+		}
+		return nil
+	}
+}
+
+// SetGRPCTimeoutFromSchemaOrDefault evaluates the gRPC hook's
+// timeout & sets it against the gRPC invoker instance
+func SetGRPCTimeoutFromSchemaOrDefault(schema *v1alpha1.GRPC) grpchook.InvokerOption {
+	return func(caller *grpchook.Invoker) error {
+		if schema.Timeout == nil {
+			// defaults to the timeout of webhooks
+			caller.Timeout = 10 * time.Second
+			return nil
+		}
+		if schema.Timeout.Duration <= 0 {
+			return errors.Errorf(
+				"Invalid grpc hook timeout: Must be > 0: %v",
+				schema,
+			)
+		}
+		caller.Timeout = schema.Timeout.Duration
+		return nil
+	}
+}
+
+// SetGRPCTLSFromSchema sets the CA bundle & server name used to
+// verify the gRPC server against the gRPC invoker instance
+func SetGRPCTLSFromSchema(schema *v1alpha1.GRPC) grpchook.InvokerOption {
+	return func(caller *grpchook.Invoker) error {
+		caller.CABundle = schema.CABundle
+		if schema.ServerName != nil {
+			caller.ServerName = *schema.ServerName
+		}
+		// CA bundle & server name are meant to verify a TLS server
+		caller.TLS = len(schema.CABundle) != 0 || schema.ServerName != nil
+		if schema.TLS != nil {
+			caller.TLS = *schema.TLS
+		}
+		return nil
+	}
+}
+
+// SetGRPCCredentialsFromSchema sets the function that fetches the
+// client certificate, key and / or bearer token from the Secret
+// referred to by the gRPC hook against the gRPC invoker instance
+//
+// NOTE:
+//	Secret is fetched for every invocation so that rotated
+// credentials are picked up without restarting metac
+func SetGRPCCredentialsFromSchema(schema *v1alpha1.GRPC) grpchook.InvokerOption {
+	return func(caller *grpchook.Invoker) error {
+		if schema.SecretRef == nil {
+			return nil
+		}
+		ref := *schema.SecretRef
+		if ref.Name == "" || ref.Namespace == "" {
+			return errors.Errorf(
+				"Invalid grpc hook secret: Specify secret 'Name' & 'Namespace': %v",
+				schema,
+			)
+		}
+		caller.CredentialsFn = func() (*grpchook.Credentials, error) {
+			creds, err := fetchWebhookCredentials(ref)
+			if err != nil {
+				return nil, err
+			}
+			return &grpchook.Credentials{
+				ClientCert:  creds.ClientCert,
+				ClientKey:   creds.ClientKey,
+				BearerToken: creds.BearerToken,
+			}, nil
+		}
+		return nil
+	}
+}
+
+// SetGRPCMessageOptionsFromSchema evaluates the gRPC hook's maximum
+// message size & compression & sets them against the gRPC invoker
+// instance
+func SetGRPCMessageOptionsFromSchema(schema *v1alpha1.GRPC) grpchook.InvokerOption {
+	return func(caller *grpchook.Invoker) error {
+		if schema.MaxMessageSize != nil {
+			if *schema.MaxMessageSize <= 0 {
+				return errors.Errorf(
+					"Invalid grpc hook max message size: Must be > 0: %v",
+					schema,
+				)
+			}
+			caller.MaxMessageSize = int(*schema.MaxMessageSize)
+		}
+		if schema.Compression != nil && *schema.Compression != "" {
+			if *schema.Compression != grpchook.CompressionGzip {
+				return errors.Errorf(
+					"Invalid grpc hook compression %q: Supports %q only: %v",
+					*schema.Compression,
+					grpchook.CompressionGzip,
+					schema,
+				)
+			}
+			caller.Compression = *schema.Compression
+		}
+		return nil
+	}
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/hooks/grpchook"
+)
+
+func TestSetGRPCAddressFromSchema(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	var tests = map[string]struct {
+		schema *v1alpha1.GRPC
+		expect string
+		isErr  bool
+	}{
+		"no address & no service": {
+			schema: &v1alpha1.GRPC{},
+			isErr:  true,
+		},
+		"address": {
+			schema: &v1alpha1.GRPC{
+				Address: strPtr("hooks:9090"),
+			},
+			expect: "hooks:9090",
+		},
+		"address overrides service": {
+			schema: &v1alpha1.GRPC{
+				Address: strPtr("hooks:9090"),
+				Service: &v1alpha1.ServiceReference{
+					Name:      "svc",
+					Namespace: "ns",
+				},
+			},
+			expect: "hooks:9090",
+		},
+		"service without namespace": {
+			schema: &v1alpha1.GRPC{
+				Service: &v1alpha1.ServiceReference{
+					Name: "svc",
+				},
+			},
+			isErr: true,
+		},
+		"service with default port": {
+			schema: &v1alpha1.GRPC{
+				Service: &v1alpha1.ServiceReference{
+					Name:      "svc",
+					Namespace: "ns",
+				},
+			},
+			expect: "svc.ns:80",
+		},
+		"service with port": {
+			schema: &v1alpha1.GRPC{
+				Service: &v1alpha1.ServiceReference{
+					Name:      "svc",
+					Namespace: "ns",
+					Port:      int32Ptr(9090),
+				},
+			},
+			expect: "svc.ns:9090",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			i := &grpchook.Invoker{}
+			err := SetGRPCAddressFromSchema(mock.schema)(i)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if i.Address != mock.expect {
+				t.Fatalf("Expected address %q got %q", mock.expect, i.Address)
+			}
+		})
+	}
+}
+
+func TestSetGRPCTLSFromSchema(t *testing.T) {
+	boolPtr := func(b bool) *bool { return &b }
+	strPtr := func(s string) *string { return &s }
+
+	var tests = map[string]struct {
+		schema *v1alpha1.GRPC
+		expect bool
+	}{
+		"no TLS settings": {
+			schema: &v1alpha1.GRPC{},
+		},
+		"CA bundle": {
+			schema: &v1alpha1.GRPC{
+				CABundle: []byte("ca"),
+			},
+			expect: true,
+		},
+		"server name": {
+			schema: &v1alpha1.GRPC{
+				ServerName: strPtr("example.com"),
+			},
+			expect: true,
+		},
+		"explicit TLS": {
+			schema: &v1alpha1.GRPC{
+				TLS: boolPtr(true),
+			},
+			expect: true,
+		},
+		"TLS disabled explicitly": {
+			schema: &v1alpha1.GRPC{
+				CABundle: []byte("ca"),
+				TLS:      boolPtr(false),
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			i := &grpchook.Invoker{}
+			err := SetGRPCTLSFromSchema(mock.schema)(i)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if i.TLS != mock.expect {
+				t.Fatalf("Expected TLS %t got %t", mock.expect, i.TLS)
+			}
+		})
+	}
+}
+
+func TestSetGRPCMessageOptionsFromSchema(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	var tests = map[string]struct {
+		schema            *v1alpha1.GRPC
+		expectSize        int
+		expectCompression string
+		isErr             bool
+	}{
+		"defaults": {
+			schema:     &v1alpha1.GRPC{},
+			expectSize: grpchook.DefaultMaxMessageSize,
+		},
+		"max message size": {
+			schema: &v1alpha1.GRPC{
+				MaxMessageSize: int32Ptr(1024),
+			},
+			expectSize: 1024,
+		},
+		"invalid max message size": {
+			schema: &v1alpha1.GRPC{
+				MaxMessageSize: int32Ptr(0),
+			},
+			isErr: true,
+		},
+		"gzip compression": {
+			schema: &v1alpha1.GRPC{
+				Compression: strPtr("gzip"),
+			},
+			expectSize:        grpchook.DefaultMaxMessageSize,
+			expectCompression: "gzip",
+		},
+		"unsupported compression": {
+			schema: &v1alpha1.GRPC{
+				Compression: strPtr("snappy"),
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			i := &grpchook.Invoker{
+				MaxMessageSize: grpchook.DefaultMaxMessageSize,
+			}
+			err := SetGRPCMessageOptionsFromSchema(mock.schema)(i)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if i.MaxMessageSize != mock.expectSize {
+				t.Fatalf(
+					"Expected max message size %d got %d",
+					mock.expectSize,
+					i.MaxMessageSize,
+				)
+			}
+			if i.Compression != mock.expectCompression {
+				t.Fatalf(
+					"Expected compression %q got %q",
+					mock.expectCompression,
+					i.Compression,
+				)
+			}
+		})
+	}
+}
+
+func TestWithHookSchemaGRPC(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	var tests = map[string]struct {
+		schema *v1alpha1.Hook
+		isErr  bool
+	}{
+		"valid grpc hook": {
+			schema: &v1alpha1.Hook{
+				GRPC: &v1alpha1.GRPC{
+					Address: strPtr("localhost:9090"),
+				},
+			},
+		},
+		"invalid grpc hook": {
+			schema: &v1alpha1.Hook{
+				GRPC: &v1alpha1.GRPC{},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			_, err := hooks.NewInvoker(WithHookSchema(mock.schema))
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+		})
+	}
+}
@@ -17,22 +17,121 @@ limitations under the License.
 package common
 
 import (
+	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/client-go/kubernetes"
+
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	"openebs.io/metac/hooks"
+	"openebs.io/metac/hooks/exechook"
+	"openebs.io/metac/hooks/grpchook"
+	"openebs.io/metac/hooks/jsonnethook"
+	"openebs.io/metac/hooks/starlarkhook"
 	"openebs.io/metac/hooks/webhook"
 )
 
+// WebhookSecretTokenKey is the key of the Secret that holds
+// the bearer token used to authenticate with the webhook server
+const WebhookSecretTokenKey = "token"
+
+var (
+	webhookSecretsGetterMutex sync.RWMutex
+
+	// webhookSecretInformers serve the Secrets referred to by
+	// the webhooks
+	webhookSecretInformers *coreInformers
+
+	// webhookSecretNamespaces are the namespaces of the Secrets
+	// that can be referred to by the webhooks
+	webhookSecretNamespaces map[string]bool
+)
+
+// SetWebhookSecretsClient sets the client used to watch the
+// Secrets referred to by the webhooks
+//
+// NOTE:
+//	Informers started with the previous client if any are stopped
+func SetWebhookSecretsClient(client kubernetes.Interface) {
+	webhookSecretsGetterMutex.Lock()
+	defer webhookSecretsGetterMutex.Unlock()
+
+	if webhookSecretInformers != nil {
+		webhookSecretInformers.stop()
+		webhookSecretInformers = nil
+	}
+	if client != nil {
+		webhookSecretInformers = newCoreInformers(client)
+	}
+}
+
+// getWebhookSecretInformers returns the informers that serve the
+// Secrets referred to by the webhooks
+func getWebhookSecretInformers() *coreInformers {
+	webhookSecretsGetterMutex.RLock()
+	defer webhookSecretsGetterMutex.RUnlock()
+
+	return webhookSecretInformers
+}
+
+// SetWebhookSecretNamespaces sets the namespaces of the Secrets
+// that can be referred to by the webhooks
+//
+// NOTE:
+//	Webhooks can't refer to any Secret if no namespaces are set.
+// Otherwise anyone who can create a meta controller could let metac
+// send the credentials of any namespace to a URL of their choice.
+func SetWebhookSecretNamespaces(namespaces []string) {
+	webhookSecretsGetterMutex.Lock()
+	defer webhookSecretsGetterMutex.Unlock()
+
+	webhookSecretNamespaces = make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		webhookSecretNamespaces[ns] = true
+	}
+}
+
+// isWebhookSecretNamespaceAllowed returns true if the webhooks can
+// refer to the Secrets of the given namespace
+func isWebhookSecretNamespaceAllowed(namespace string) bool {
+	webhookSecretsGetterMutex.RLock()
+	defer webhookSecretsGetterMutex.RUnlock()
+
+	return webhookSecretNamespaces[namespace]
+}
+
 // InvokeHook invokes the given hook with the given request
+//
+// NOTE:
+//	Invoker is cached per hook definition to reuse connections
+// to the hook across invocations
 func InvokeHook(schema *v1alpha1.Hook, request, response interface{}) error {
-	i, err := hooks.NewInvoker(WithHookSchema(schema))
+	return invokeHookOf(context.Background(), "", schema, request, response)
+}
+
+// invokeHookOf invokes the given hook of the given owner with the
+// given request
+//
+// NOTE:
+//	Owner identifies the hook of a meta controller. This lets the
+// invoker of its older hook definition be closed once the hook
+// definition changes.
+func invokeHookOf(
+	ctx context.Context,
+	owner string,
+	schema *v1alpha1.Hook,
+	request, response interface{},
+) error {
+	i, err := hookInvokers.GetOrCreate(owner, schema)
 	if err != nil {
 		return err
 	}
-	return i.Invoke(request, response)
+	return i.InvokeContext(ctx, request, response)
 }
 
 // WithHookSchema sets the hook invoker instance with appropriate
@@ -43,8 +142,59 @@ func InvokeHook(schema *v1alpha1.Hook, request, response interface{}) error {
 // different hook types e.g. webhook, inline hook, etc
 func WithHookSchema(schema *v1alpha1.Hook) hooks.InvokerOption {
 	return func(invoker *hooks.Invoker) error {
-		// webhook is the only commonly supported hook for
-		// all meta controllers
+		// webhook, grpc, exec, jsonnet & starlark are the commonly
+		// supported hooks for all meta controllers
+		if schema.Starlark != nil {
+			si, err := starlarkhook.NewInvoker(
+				SetStarlarkSourceFromSchema(schema.Starlark),
+				SetStarlarkLimitsFromSchema(schema.Starlark),
+			)
+			if err != nil {
+				return err
+			}
+			invoker.InvokeFn = si.InvokeContext
+			return nil
+		}
+		if schema.Jsonnet != nil {
+			ji, err := jsonnethook.NewInvoker(
+				SetJsonnetSourceFromSchema(schema.Jsonnet),
+				SetJsonnetTimeoutFromSchema(schema.Jsonnet),
+			)
+			if err != nil {
+				return err
+			}
+			invoker.InvokeFn = ji.InvokeContext
+			return nil
+		}
+		if schema.Exec != nil {
+			ei, err := exechook.NewInvoker(
+				// set various exec options
+				SetExecCommandFromSchema(schema.Exec),
+				SetExecTimeoutFromSchemaOrDefault(schema.Exec),
+			)
+			if err != nil {
+				return err
+			}
+			invoker.InvokeFn = ei.InvokeContext
+			return nil
+		}
+		if schema.GRPC != nil {
+			gi, err := grpchook.NewInvoker(
+				// set various grpc options
+				SetGRPCAddressFromSchema(schema.GRPC),
+				SetGRPCMethodFromSchema(schema.GRPC),
+				SetGRPCTimeoutFromSchemaOrDefault(schema.GRPC),
+				SetGRPCTLSFromSchema(schema.GRPC),
+				SetGRPCCredentialsFromSchema(schema.GRPC),
+				SetGRPCMessageOptionsFromSchema(schema.GRPC),
+			)
+			if err != nil {
+				return err
+			}
+			invoker.InvokeFn = gi.InvokeContext
+			invoker.CloseFn = gi.Close
+			return nil
+		}
 		if schema.Webhook == nil {
 			return errors.Errorf("Unsupported hook %v", schema)
 		}
@@ -54,11 +204,19 @@ func WithHookSchema(schema *v1alpha1.Hook) hooks.InvokerOption {
 			// set various webhook options
 			SetWebhookURLFromSchema(schema.Webhook),
 			SetWebhookTimeoutFromSchemaOrDefault(schema.Webhook),
+			SetWebhookTLSFromSchema(schema.Webhook),
+			SetWebhookCredentialsFromSchema(schema.Webhook),
+			SetWebhookRetryPolicyFromSchema(schema.Webhook),
+			// NOTE:
+			//	This needs to be set after url since the circuit
+			// breaker reports the url in its logs & metrics
+			SetWebhookCircuitBreakerFromSchema(schema.Webhook),
 		)
 		if err != nil {
 			return err
 		}
-		invoker.InvokeFn = whi.Invoke
+		invoker.InvokeFn = whi.InvokeContext
+		invoker.CloseFn = whi.Close
 		return nil
 	}
 }
@@ -95,6 +253,10 @@ func SetWebhookURLFromSchema(schema *v1alpha1.Webhook) webhook.InvokerOption {
 		}
 
 		protocol := "http"
+		if len(schema.CABundle) != 0 {
+			// CA bundle is meant to verify a TLS server
+			protocol = "https"
+		}
 		if schema.Service.Protocol != nil {
 			protocol = *schema.Service.Protocol
 		}
@@ -131,3 +293,179 @@ func SetWebhookTimeoutFromSchemaOrDefault(schema *v1alpha1.Webhook) webhook.Invo
 		return nil
 	}
 }
+
+// SetWebhookTLSFromSchema sets the CA bundle & server name used
+// to verify the webhook server against the WebhookCaller instance
+func SetWebhookTLSFromSchema(schema *v1alpha1.Webhook) webhook.InvokerOption {
+	return func(caller *webhook.Invoker) error {
+		caller.CABundle = schema.CABundle
+		if schema.ServerName != nil {
+			caller.ServerName = *schema.ServerName
+		}
+		return nil
+	}
+}
+
+// SetWebhookCredentialsFromSchema sets the function that fetches
+// the client certificate, key and / or bearer token from the Secret
+// referred to by the webhook against the WebhookCaller instance
+//
+// NOTE:
+//	Secret is looked up for every invocation so that rotated
+// credentials are picked up without restarting metac. This lookup
+// is served from an informer's cache & not from the API server.
+func SetWebhookCredentialsFromSchema(schema *v1alpha1.Webhook) webhook.InvokerOption {
+	return func(caller *webhook.Invoker) error {
+		if schema.SecretRef == nil {
+			return nil
+		}
+		ref := *schema.SecretRef
+		if ref.Name == "" || ref.Namespace == "" {
+			return errors.Errorf(
+				"Invalid webhook secret: Specify secret 'Name' & 'Namespace': %v",
+				schema,
+			)
+		}
+		if !isWebhookSecretNamespaceAllowed(ref.Namespace) {
+			return errors.Errorf(
+				"Invalid webhook secret %s/%s: Namespace %q is not allowed: %v",
+				ref.Namespace,
+				ref.Name,
+				ref.Namespace,
+				schema,
+			)
+		}
+		caller.CredentialsFn = func() (*webhook.Credentials, error) {
+			return fetchWebhookCredentials(ref)
+		}
+		return nil
+	}
+}
+
+// fetchWebhookCredentials returns the client certificate, key
+// and / or bearer token found in the referred Secret
+func fetchWebhookCredentials(
+	ref v1alpha1.SecretReference,
+) (*webhook.Credentials, error) {
+	informers := getWebhookSecretInformers()
+	if informers == nil {
+		return nil, errors.Errorf(
+			"Can't fetch webhook secret %s/%s: Secrets client is not set",
+			ref.Namespace,
+			ref.Name,
+		)
+	}
+	secret, err := informers.getSecret(ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't fetch webhook secret %s/%s",
+			ref.Namespace,
+			ref.Name,
+		)
+	}
+	cert := secret.Data[corev1.TLSCertKey]
+	key := secret.Data[corev1.TLSPrivateKeyKey]
+	token := secret.Data[WebhookSecretTokenKey]
+	if (len(cert) == 0) != (len(key) == 0) {
+		return nil, errors.Errorf(
+			"Invalid webhook secret %s/%s: Specify both %q & %q",
+			ref.Namespace,
+			ref.Name,
+			corev1.TLSCertKey,
+			corev1.TLSPrivateKeyKey,
+		)
+	}
+	if len(cert) == 0 && len(token) == 0 {
+		return nil, errors.Errorf(
+			"Invalid webhook secret %s/%s: Specify either %q & %q or %q",
+			ref.Namespace,
+			ref.Name,
+			corev1.TLSCertKey,
+			corev1.TLSPrivateKeyKey,
+			WebhookSecretTokenKey,
+		)
+	}
+	return &webhook.Credentials{
+		ClientCert:  cert,
+		ClientKey:   key,
+		BearerToken: string(token),
+	}, nil
+}
+
+// SetWebhookRetryPolicyFromSchema evaluates the webhook's retry
+// policy & sets it against the WebhookCaller instance
+func SetWebhookRetryPolicyFromSchema(schema *v1alpha1.Webhook) webhook.InvokerOption {
+	return func(caller *webhook.Invoker) error {
+		if schema.RetryPolicy == nil {
+			return nil
+		}
+		policy := schema.RetryPolicy
+		retry := &webhook.RetryPolicy{
+			MaxAttempts: 1,
+		}
+		if policy.MaxAttempts != nil {
+			if *policy.MaxAttempts <= 0 {
+				return errors.Errorf(
+					"Invalid webhook retry policy: MaxAttempts must be > 0: %v",
+					schema,
+				)
+			}
+			retry.MaxAttempts = int(*policy.MaxAttempts)
+		}
+		if policy.Backoff != nil {
+			retry.Backoff = policy.Backoff.Duration
+		}
+		if policy.MaxBackoff != nil {
+			retry.MaxBackoff = policy.MaxBackoff.Duration
+		}
+		for _, code := range policy.RetryableStatusCodes {
+			retry.RetryableStatusCodes = append(
+				retry.RetryableStatusCodes,
+				int(code),
+			)
+		}
+		caller.RetryPolicy = retry
+		return nil
+	}
+}
+
+// SetWebhookCircuitBreakerFromSchema sets the circuit breaker of
+// the webhook's definition against the WebhookCaller instance
+//
+// NOTE:
+//	Circuit breaker is anchored by the webhook's definition excluding
+// its circuit breaker settings. Hence changes to these settings are
+// applied to the existing circuit breaker.
+func SetWebhookCircuitBreakerFromSchema(schema *v1alpha1.Webhook) webhook.InvokerOption {
+	return func(caller *webhook.Invoker) error {
+		if schema.CircuitBreaker == nil {
+			return nil
+		}
+		definition := *schema
+		definition.CircuitBreaker = nil
+		raw, err := json.Marshal(definition)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"Can't get webhook circuit breaker: %v",
+				schema,
+			)
+		}
+		var threshold int
+		if schema.CircuitBreaker.FailureThreshold != nil {
+			threshold = int(*schema.CircuitBreaker.FailureThreshold)
+		}
+		var openDuration time.Duration
+		if schema.CircuitBreaker.OpenDuration != nil {
+			openDuration = schema.CircuitBreaker.OpenDuration.Duration
+		}
+		caller.CircuitBreaker = webhook.GetOrCreateCircuitBreaker(
+			string(raw),
+			caller.URL,
+			threshold,
+			openDuration,
+		)
+		return nil
+	}
+}
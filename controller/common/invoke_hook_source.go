@@ -0,0 +1,145 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+)
+
+var (
+	hookConfigMapsGetterMutex sync.RWMutex
+
+	// hookConfigMapInformers serve the ConfigMaps referred to by
+	// the hooks
+	hookConfigMapInformers *coreInformers
+)
+
+// SetHookConfigMapsClient sets the client used to watch the
+// ConfigMaps referred to by the hooks
+//
+// NOTE:
+//	Informers started with the previous client if any are stopped
+func SetHookConfigMapsClient(client kubernetes.Interface) {
+	hookConfigMapsGetterMutex.Lock()
+	defer hookConfigMapsGetterMutex.Unlock()
+
+	if hookConfigMapInformers != nil {
+		hookConfigMapInformers.stop()
+		hookConfigMapInformers = nil
+	}
+	if client != nil {
+		hookConfigMapInformers = newCoreInformers(client)
+	}
+}
+
+// getHookConfigMapInformers returns the informers that serve the
+// ConfigMaps referred to by the hooks
+func getHookConfigMapInformers() *coreInformers {
+	hookConfigMapsGetterMutex.RLock()
+	defer hookConfigMapsGetterMutex.RUnlock()
+
+	return hookConfigMapInformers
+}
+
+// newHookSourceFn returns the file name & the function that returns
+// the source of a hook that is either set inline or is referred to
+// via a ConfigMap key
+func newHookSourceFn(
+	language string,
+	source *string,
+	ref *v1alpha1.ConfigMapKeyReference,
+) (string, func() (string, error), error) {
+	if source != nil {
+		src := *source
+		return "inline." + language, func() (string, error) {
+			return src, nil
+		}, nil
+	}
+	if ref == nil {
+		return "", nil, errors.Errorf(
+			"Specify either 'Source' or 'ConfigMapRef'",
+		)
+	}
+	cmRef := *ref
+	filename, err := hookConfigMapFilename(cmRef)
+	if err != nil {
+		return "", nil, err
+	}
+	return filename, func() (string, error) {
+		return fetchHookConfigMapKey(cmRef)
+	}, nil
+}
+
+// hookConfigMapFilename returns the file name of the hook source
+// that is referred to via the given ConfigMap key
+func hookConfigMapFilename(ref v1alpha1.ConfigMapKeyReference) (string, error) {
+	if ref.Name == "" || ref.Namespace == "" || ref.Key == "" {
+		return "", errors.Errorf(
+			"Specify configmap 'Name', 'Namespace' & 'Key'",
+		)
+	}
+	return fmt.Sprintf("%s/%s/%s", ref.Namespace, ref.Name, ref.Key), nil
+}
+
+// fetchHookConfigMapKey returns the value of the referred
+// ConfigMap key
+func fetchHookConfigMapKey(ref v1alpha1.ConfigMapKeyReference) (string, error) {
+	configMap, err := fetchHookConfigMap(ref)
+	if err != nil {
+		return "", err
+	}
+	value, found := configMap.Data[ref.Key]
+	if !found {
+		return "", errors.Errorf(
+			"Invalid hook configmap %s/%s: Key %q not found",
+			ref.Namespace,
+			ref.Name,
+			ref.Key,
+		)
+	}
+	return value, nil
+}
+
+// fetchHookConfigMap returns the referred ConfigMap from the
+// informer's cache
+func fetchHookConfigMap(ref v1alpha1.ConfigMapKeyReference) (*corev1.ConfigMap, error) {
+	informers := getHookConfigMapInformers()
+	if informers == nil {
+		return nil, errors.Errorf(
+			"Can't fetch hook configmap %s/%s: ConfigMaps client is not set",
+			ref.Namespace,
+			ref.Name,
+		)
+	}
+	configMap, err := informers.getConfigMap(ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't fetch hook configmap %s/%s",
+			ref.Namespace,
+			ref.Name,
+		)
+	}
+	return configMap, nil
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/webhook"
+)
+
+func TestSetWebhookCredentialsFromSchema(t *testing.T) {
+	newSecret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "hooks",
+			},
+			Data: data,
+		}
+	}
+	SetWebhookSecretsClient(
+		fake.NewSimpleClientset(
+			newSecret("token", map[string][]byte{
+				WebhookSecretTokenKey: []byte("secret"),
+			}),
+			newSecret("cert", map[string][]byte{
+				corev1.TLSCertKey:       []byte("cert"),
+				corev1.TLSPrivateKeyKey: []byte("key"),
+			}),
+			newSecret("cert-without-key", map[string][]byte{
+				corev1.TLSCertKey: []byte("cert"),
+			}),
+			newSecret("empty", nil),
+		),
+	)
+	defer SetWebhookSecretsClient(nil)
+	SetWebhookSecretNamespaces([]string{"hooks"})
+	defer SetWebhookSecretNamespaces(nil)
+
+	var tests = map[string]struct {
+		secretRef   *v1alpha1.SecretReference
+		expectToken string
+		expectCert  string
+		expectKey   string
+		isErr       bool
+	}{
+		"no secret ref": {},
+		"secret ref without namespace": {
+			secretRef: &v1alpha1.SecretReference{Name: "token"},
+			isErr:     true,
+		},
+		"secret of namespace that is not allowed": {
+			secretRef: &v1alpha1.SecretReference{Name: "token", Namespace: "kube-system"},
+			isErr:     true,
+		},
+		"secret not found": {
+			secretRef: &v1alpha1.SecretReference{Name: "junk", Namespace: "hooks"},
+			isErr:     true,
+		},
+		"secret with token": {
+			secretRef:   &v1alpha1.SecretReference{Name: "token", Namespace: "hooks"},
+			expectToken: "secret",
+		},
+		"secret with cert & key": {
+			secretRef:  &v1alpha1.SecretReference{Name: "cert", Namespace: "hooks"},
+			expectCert: "cert",
+			expectKey:  "key",
+		},
+		"secret with cert without key": {
+			secretRef: &v1alpha1.SecretReference{Name: "cert-without-key", Namespace: "hooks"},
+			isErr:     true,
+		},
+		"empty secret": {
+			secretRef: &v1alpha1.SecretReference{Name: "empty", Namespace: "hooks"},
+			isErr:     true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			caller := &webhook.Invoker{}
+			err := SetWebhookCredentialsFromSchema(
+				&v1alpha1.Webhook{SecretRef: mock.secretRef},
+			)(caller)
+			creds := &webhook.Credentials{}
+			if err == nil && caller.CredentialsFn != nil {
+				creds, err = caller.CredentialsFn()
+			}
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if creds.BearerToken != mock.expectToken {
+				t.Fatalf("Expected token %q got %q", mock.expectToken, creds.BearerToken)
+			}
+			if string(creds.ClientCert) != mock.expectCert {
+				t.Fatalf("Expected cert %q got %q", mock.expectCert, creds.ClientCert)
+			}
+			if string(creds.ClientKey) != mock.expectKey {
+				t.Fatalf("Expected key %q got %q", mock.expectKey, creds.ClientKey)
+			}
+		})
+	}
+}
+
+func TestSetWebhookCircuitBreakerFromSchema(t *testing.T) {
+	url := "http://test-circuit-breaker/sync"
+	newSchema := func(timeout time.Duration, threshold int32) *v1alpha1.Webhook {
+		return &v1alpha1.Webhook{
+			URL:     &url,
+			Timeout: &metav1.Duration{Duration: timeout},
+			CircuitBreaker: &v1alpha1.WebhookCircuitBreaker{
+				FailureThreshold: &threshold,
+			},
+		}
+	}
+	newCircuitBreaker := func(schema *v1alpha1.Webhook) *webhook.CircuitBreaker {
+		invoker, err := webhook.NewInvoker(
+			SetWebhookURLFromSchema(schema),
+			SetWebhookCircuitBreakerFromSchema(schema),
+		)
+		if err != nil {
+			t.Fatalf("Expected no error got %+v", err)
+		}
+		return invoker.CircuitBreaker
+	}
+
+	first := newCircuitBreaker(newSchema(time.Second, 2))
+	if first.Hook != url {
+		t.Fatalf("Expected hook %q got %q", url, first.Hook)
+	}
+	updated := newCircuitBreaker(newSchema(time.Second, 3))
+	if updated != first {
+		t.Fatalf("Expected same circuit breaker for changed circuit breaker settings")
+	}
+	if updated.FailureThreshold != 3 {
+		t.Fatalf("Expected failure threshold 3 got %d", updated.FailureThreshold)
+	}
+	other := newCircuitBreaker(newSchema(2*time.Second, 3))
+	if other == first {
+		t.Fatalf("Expected different circuit breaker for different hook definition")
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/jsonnethook"
+)
+
+// SetJsonnetSourceFromSchema sets the function that returns the
+// Jsonnet program against the jsonnet invoker instance
+func SetJsonnetSourceFromSchema(schema *v1alpha1.Jsonnet) jsonnethook.InvokerOption {
+	return func(caller *jsonnethook.Invoker) error {
+		filename, sourceFn, err := newHookSourceFn(
+			"jsonnet",
+			schema.Source,
+			schema.ConfigMapRef,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid jsonnet hook: %v", schema)
+		}
+		caller.Filename = filename
+		caller.SourceFn = sourceFn
+		return nil
+	}
+}
+
+// SetJsonnetTimeoutFromSchema evaluates the jsonnet hook's timeout &
+// sets it against the jsonnet invoker instance
+func SetJsonnetTimeoutFromSchema(schema *v1alpha1.Jsonnet) jsonnethook.InvokerOption {
+	return func(caller *jsonnethook.Invoker) error {
+		if schema.Timeout == nil {
+			return nil
+		}
+		if schema.Timeout.Duration <= 0 {
+			return errors.Errorf(
+				"Invalid jsonnet hook timeout: Must be > 0: %v",
+				schema,
+			)
+		}
+		caller.Timeout = schema.Timeout.Duration
+		return nil
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/jsonnethook"
+)
+
+func TestSetJsonnetSourceFromSchema(t *testing.T) {
+	strPtr := func(s string) *string { return &s }
+
+	SetHookConfigMapsClient(
+		fake.NewSimpleClientset(
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "sync",
+					Namespace: "hooks",
+				},
+				Data: map[string]string{
+					"sync.jsonnet": "function(request) {}",
+				},
+			},
+		),
+	)
+	defer SetHookConfigMapsClient(nil)
+
+	var tests = map[string]struct {
+		schema         *v1alpha1.Jsonnet
+		expectFilename string
+		expectSource   string
+		isErr          bool
+	}{
+		"no source & no configmap": {
+			schema: &v1alpha1.Jsonnet{},
+			isErr:  true,
+		},
+		"source": {
+			schema: &v1alpha1.Jsonnet{
+				Source: strPtr("function(request) request"),
+			},
+			expectFilename: "inline.jsonnet",
+			expectSource:   "function(request) request",
+		},
+		"configmap without key": {
+			schema: &v1alpha1.Jsonnet{
+				ConfigMapRef: &v1alpha1.ConfigMapKeyReference{
+					Name:      "sync",
+					Namespace: "hooks",
+				},
+			},
+			isErr: true,
+		},
+		"configmap not found": {
+			schema: &v1alpha1.Jsonnet{
+				ConfigMapRef: &v1alpha1.ConfigMapKeyReference{
+					Name:      "junk",
+					Namespace: "hooks",
+					Key:       "sync.jsonnet",
+				},
+			},
+			isErr: true,
+		},
+		"configmap key not found": {
+			schema: &v1alpha1.Jsonnet{
+				ConfigMapRef: &v1alpha1.ConfigMapKeyReference{
+					Name:      "sync",
+					Namespace: "hooks",
+					Key:       "junk.jsonnet",
+				},
+			},
+			isErr: true,
+		},
+		"configmap": {
+			schema: &v1alpha1.Jsonnet{
+				ConfigMapRef: &v1alpha1.ConfigMapKeyReference{
+					Name:      "sync",
+					Namespace: "hooks",
+					Key:       "sync.jsonnet",
+				},
+			},
+			expectFilename: "hooks/sync/sync.jsonnet",
+			expectSource:   "function(request) {}",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			caller := &jsonnethook.Invoker{}
+			err := SetJsonnetSourceFromSchema(mock.schema)(caller)
+			var source string
+			if err == nil {
+				source, err = caller.SourceFn()
+			}
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if caller.Filename != mock.expectFilename {
+				t.Fatalf(
+					"Expected filename %q got %q",
+					mock.expectFilename,
+					caller.Filename,
+				)
+			}
+			if source != mock.expectSource {
+				t.Fatalf("Expected source %q got %q", mock.expectSource, source)
+			}
+		})
+	}
+}
+
+func TestSetJsonnetTimeoutFromSchema(t *testing.T) {
+	var tests = map[string]struct {
+		schema        *v1alpha1.Jsonnet
+		expectTimeout time.Duration
+		isErr         bool
+	}{
+		"default": {
+			schema:        &v1alpha1.Jsonnet{},
+			expectTimeout: jsonnethook.DefaultTimeout,
+		},
+		"timeout": {
+			schema: &v1alpha1.Jsonnet{
+				Timeout: &metav1.Duration{Duration: time.Second},
+			},
+			expectTimeout: time.Second,
+		},
+		"invalid timeout": {
+			schema: &v1alpha1.Jsonnet{
+				Timeout: &metav1.Duration{},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			i := &jsonnethook.Invoker{
+				Timeout: jsonnethook.DefaultTimeout,
+			}
+			err := SetJsonnetTimeoutFromSchema(mock.schema)(i)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if i.Timeout != mock.expectTimeout {
+				t.Fatalf(
+					"Expected timeout %s got %s",
+					mock.expectTimeout,
+					i.Timeout,
+				)
+			}
+		})
+	}
+}
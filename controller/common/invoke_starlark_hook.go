@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/starlarkhook"
+)
+
+// SetStarlarkSourceFromSchema sets the function that returns the
+// Starlark script & the script's function that gets invoked against
+// the starlark invoker instance
+func SetStarlarkSourceFromSchema(schema *v1alpha1.Starlark) starlarkhook.InvokerOption {
+	return func(caller *starlarkhook.Invoker) error {
+		filename, sourceFn, err := newHookSourceFn(
+			"star",
+			schema.Source,
+			schema.ConfigMapRef,
+		)
+		if err != nil {
+			return errors.Wrapf(err, "Invalid starlark hook: %v", schema)
+		}
+		caller.Filename = filename
+		caller.SourceFn = sourceFn
+		if schema.Function != nil {
+			caller.Function = *schema.Function
+		}
+		return nil
+	}
+}
+
+// SetStarlarkLimitsFromSchema evaluates the limits of the starlark
+// hook's sandbox & sets them against the starlark invoker instance
+func SetStarlarkLimitsFromSchema(schema *v1alpha1.Starlark) starlarkhook.InvokerOption {
+	return func(caller *starlarkhook.Invoker) error {
+		if schema.MaxExecutionSteps != nil {
+			if *schema.MaxExecutionSteps <= 0 {
+				return errors.Errorf(
+					"Invalid starlark hook: MaxExecutionSteps must be > 0: %v",
+					schema,
+				)
+			}
+			caller.MaxExecutionSteps = *schema.MaxExecutionSteps
+		}
+		if schema.MaxMemory != nil {
+			if *schema.MaxMemory <= 0 {
+				return errors.Errorf(
+					"Invalid starlark hook: MaxMemory must be > 0: %v",
+					schema,
+				)
+			}
+			caller.MaxMemory = *schema.MaxMemory
+		}
+		return nil
+	}
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks/starlarkhook"
+)
+
+func TestSetStarlarkLimitsFromSchema(t *testing.T) {
+	int64Ptr := func(i int64) *int64 { return &i }
+
+	var tests = map[string]struct {
+		schema       *v1alpha1.Starlark
+		expectSteps  int64
+		expectMemory int64
+		isErr        bool
+	}{
+		"defaults": {
+			schema:       &v1alpha1.Starlark{},
+			expectSteps:  starlarkhook.DefaultMaxExecutionSteps,
+			expectMemory: starlarkhook.DefaultMaxMemory,
+		},
+		"limits": {
+			schema: &v1alpha1.Starlark{
+				MaxExecutionSteps: int64Ptr(100),
+				MaxMemory:         int64Ptr(1024),
+			},
+			expectSteps:  100,
+			expectMemory: 1024,
+		},
+		"invalid steps": {
+			schema: &v1alpha1.Starlark{
+				MaxExecutionSteps: int64Ptr(0),
+			},
+			isErr: true,
+		},
+		"invalid memory": {
+			schema: &v1alpha1.Starlark{
+				MaxMemory: int64Ptr(-1),
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			i := &starlarkhook.Invoker{
+				MaxExecutionSteps: starlarkhook.DefaultMaxExecutionSteps,
+				MaxMemory:         starlarkhook.DefaultMaxMemory,
+			}
+			err := SetStarlarkLimitsFromSchema(mock.schema)(i)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if i.MaxExecutionSteps != mock.expectSteps {
+				t.Fatalf(
+					"Expected steps %d got %d",
+					mock.expectSteps,
+					i.MaxExecutionSteps,
+				)
+			}
+			if i.MaxMemory != mock.expectMemory {
+				t.Fatalf(
+					"Expected memory %d got %d",
+					mock.expectMemory,
+					i.MaxMemory,
+				)
+			}
+		})
+	}
+}
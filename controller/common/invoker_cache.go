@@ -0,0 +1,195 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks"
+)
+
+// DefaultHookInvokerCacheSize is the maximum number of hook
+// invokers that are cached
+const DefaultHookInvokerCacheSize = 512
+
+// hookInvokers caches the invokers of all the hooks
+var hookInvokers = NewHookInvokerCache(DefaultHookInvokerCacheSize)
+
+// HookInvokerCache caches hook invokers anchored by their hook
+// definitions. An invoker is built once per hook definition &
+// is reused for all the invocations of this hook.
+//
+// NOTE:
+//	A changed hook definition results in a new invoker. Invoker
+// of the older definition is closed once it is no longer used by
+// any of its owners or once it is evicted as the least recently
+// used one of a full cache.
+type HookInvokerCache struct {
+	mutex   sync.Mutex
+	maxSize int
+
+	// cached invokers anchored by hook definition
+	entries map[string]*list.Element
+
+	// invokers ordered from most to least recently used
+	lru *list.List
+
+	// definitions last used by the owners anchored by owner
+	owners map[string]string
+}
+
+// hookInvokerCacheEntry is a cached hook invoker
+type hookInvokerCacheEntry struct {
+	key     string
+	invoker *hooks.Invoker
+
+	// owners that last used this invoker
+	owners map[string]bool
+}
+
+// NewHookInvokerCache returns a new instance of HookInvokerCache
+// that holds up to the given number of invokers
+func NewHookInvokerCache(maxSize int) *HookInvokerCache {
+	if maxSize <= 0 {
+		maxSize = DefaultHookInvokerCacheSize
+	}
+	return &HookInvokerCache{
+		maxSize: maxSize,
+		entries: map[string]*list.Element{},
+		lru:     list.New(),
+		owners:  map[string]string{},
+	}
+}
+
+// GetOrCreate returns the cached invoker of the given hook. A new
+// invoker is built & cached if none exists.
+//
+// NOTE:
+//	Owner identifies the hook of a meta controller that uses the
+// invoker. The invoker that was last used by the owner is replaced
+// if the owner's hook definition has changed. An empty owner is
+// not tracked.
+func (c *HookInvokerCache) GetOrCreate(
+	owner string,
+	schema *v1alpha1.Hook,
+) (*hooks.Invoker, error) {
+	if schema == nil {
+		return nil, errors.Errorf("Can't get hook invoker: Nil hook")
+	}
+	raw, err := json.Marshal(schema)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Can't get hook invoker: %v", schema)
+	}
+	key := string(raw)
+
+	c.mutex.Lock()
+	invoker, removed, err := c.getOrCreate(owner, key, schema)
+	c.mutex.Unlock()
+
+	// removed invokers are closed without holding the lock since
+	// closing them may take a while
+	for _, entry := range removed {
+		err := entry.invoker.Close()
+		if err != nil {
+			glog.Warningf("Failed to close hook invoker: %v", err)
+		}
+	}
+	return invoker, err
+}
+
+// getOrCreate returns the invoker of the given key along with the
+// entries that are removed from the cache
+//
+// NOTE:
+//	This must be called with the lock held
+func (c *HookInvokerCache) getOrCreate(
+	owner string,
+	key string,
+	schema *v1alpha1.Hook,
+) (*hooks.Invoker, []*hookInvokerCacheEntry, error) {
+	elem, found := c.entries[key]
+	if found {
+		c.lru.MoveToFront(elem)
+	} else {
+		// NOTE:
+		//	Invalid hook definitions are not cached & hence result
+		// in errors for all their invocations
+		invoker, err := hooks.NewInvoker(WithHookSchema(schema))
+		if err != nil {
+			return nil, nil, err
+		}
+		elem = c.lru.PushFront(
+			&hookInvokerCacheEntry{
+				key:     key,
+				invoker: invoker,
+				owners:  map[string]bool{},
+			},
+		)
+		c.entries[key] = elem
+	}
+	entry := elem.Value.(*hookInvokerCacheEntry)
+
+	var removed []*hookInvokerCacheEntry
+	if owner != "" {
+		last, found := c.owners[owner]
+		if found && last != key {
+			// owner's hook definition has changed
+			if lastElem, found := c.entries[last]; found {
+				lastEntry := lastElem.Value.(*hookInvokerCacheEntry)
+				delete(lastEntry.owners, owner)
+				if len(lastEntry.owners) == 0 {
+					c.remove(lastElem)
+					removed = append(removed, lastEntry)
+				}
+			}
+		}
+		c.owners[owner] = key
+		entry.owners[owner] = true
+	}
+	if c.lru.Len() > c.maxSize {
+		oldest := c.lru.Back()
+		c.remove(oldest)
+		removed = append(removed, oldest.Value.(*hookInvokerCacheEntry))
+	}
+	return entry.invoker, removed, nil
+}
+
+// remove removes the given element from the cache
+func (c *HookInvokerCache) remove(elem *list.Element) {
+	entry := elem.Value.(*hookInvokerCacheEntry)
+	c.lru.Remove(elem)
+	delete(c.entries, entry.key)
+	for owner := range entry.owners {
+		if c.owners[owner] == entry.key {
+			delete(c.owners, owner)
+		}
+	}
+}
+
+// Len returns the number of cached invokers
+func (c *HookInvokerCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lru.Len()
+}
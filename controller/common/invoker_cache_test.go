@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/third_party/kubernetes"
+)
+
+func TestHookInvokerCacheGetOrCreate(t *testing.T) {
+	newHook := func(url string, timeout time.Duration) *v1alpha1.Hook {
+		return &v1alpha1.Hook{
+			Webhook: &v1alpha1.Webhook{
+				URL:     kubernetes.StringPtr(url),
+				Timeout: &metav1.Duration{Duration: timeout},
+			},
+		}
+	}
+	cache := NewHookInvokerCache(2)
+
+	first, err := cache.GetOrCreate("", newHook("http://hook-1", time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	again, err := cache.GetOrCreate("", newHook("http://hook-1", time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if first != again {
+		t.Fatalf("Expected same invoker for same hook definition")
+	}
+
+	changed, err := cache.GetOrCreate("", newHook("http://hook-1", 2*time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if first == changed {
+		t.Fatalf("Expected new invoker for changed hook definition")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 cached invokers got %d", cache.Len())
+	}
+
+	// least recently used invoker is evicted
+	_, err = cache.GetOrCreate("", newHook("http://hook-2", time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 cached invokers got %d", cache.Len())
+	}
+	evicted, err := cache.GetOrCreate("", newHook("http://hook-1", time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if first == evicted {
+		t.Fatalf("Expected new invoker for evicted hook definition")
+	}
+
+	// invalid hook definitions are not cached
+	_, err = cache.GetOrCreate("", &v1alpha1.Hook{})
+	if err == nil {
+		t.Fatalf("Expected error for invalid hook got none")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 cached invokers got %d", cache.Len())
+	}
+}
+
+func TestHookInvokerCacheClosesRemovedInvokers(t *testing.T) {
+	newHook := func(method string) *v1alpha1.Hook {
+		return &v1alpha1.Hook{
+			GRPC: &v1alpha1.GRPC{
+				Address: kubernetes.StringPtr("127.0.0.1:1"),
+				Method:  kubernetes.StringPtr(method),
+			},
+		}
+	}
+	isClosed := func(i *hooks.Invoker) bool {
+		var resp map[string]interface{}
+		err := i.Invoke(map[string]string{}, &resp)
+		return err != nil && strings.Contains(err.Error(), "Invoker is closed")
+	}
+	cache := NewHookInvokerCache(2)
+
+	first, err := cache.GetOrCreate("ctrl: sync", newHook("/test/First"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	shared, err := cache.GetOrCreate("ctrl: finalize", newHook("/test/Shared"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	_, err = cache.GetOrCreate("other: sync", newHook("/test/Shared"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+
+	// invoker is replaced once its owner's hook definition changes
+	_, err = cache.GetOrCreate("ctrl: sync", newHook("/test/Second"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !isClosed(first) {
+		t.Fatalf("Expected replaced invoker to be closed")
+	}
+	if cache.Len() != 2 {
+		t.Fatalf("Expected 2 cached invokers got %d", cache.Len())
+	}
+
+	// invoker is retained while any of its owners uses it
+	_, err = cache.GetOrCreate("ctrl: finalize", newHook("/test/Second"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	again, err := cache.GetOrCreate("other: sync", newHook("/test/Shared"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if again != shared {
+		t.Fatalf("Expected shared invoker to be retained")
+	}
+
+	// least recently used invoker is closed once evicted
+	_, err = cache.GetOrCreate("", newHook("/test/Third"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	_, err = cache.GetOrCreate("", newHook("/test/Fourth"))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !isClosed(shared) {
+		t.Fatalf("Expected evicted invoker to be closed")
+	}
+}
@@ -300,6 +300,27 @@ type ChildUpdateStrategyGetter interface {
 	Get(apiGroup, kind string) v1alpha1.ChildUpdateMethod
 }
 
+// ChildUpdateHookInvoker provides the abstraction to invoke
+// hooks around the update of a child
+type ChildUpdateHookInvoker interface {
+	// PreUpdateChild is invoked before the observed child gets
+	// updated to the desired state. Update is skipped if this
+	// returns false.
+	PreUpdateChild(parent, observed, desired *unstructured.Unstructured) (bool, error)
+
+	// PostUpdateChild is invoked after the child was updated
+	// in-place
+	PostUpdateChild(parent, updated *unstructured.Unstructured) error
+
+	// ChildDeletedForUpdate is invoked after the observed child was
+	// deleted to be recreated with its desired state
+	ChildDeletedForUpdate(parent, deleted *unstructured.Unstructured)
+
+	// ChildCreated is invoked after the child was created. This is
+	// where the update of a recreated child completes.
+	ChildCreated(parent, created *unstructured.Unstructured) error
+}
+
 // ManageChildren ensures the relevant children objects of the
 // given parent are in sync
 func ManageChildren(
@@ -307,6 +328,26 @@ func ManageChildren(
 	updateStrategy ChildUpdateStrategyGetter,
 	parent *unstructured.Unstructured,
 	observedChildren, desiredChildren AnyUnstructRegistry,
+) error {
+	return ManageChildrenWithHooks(
+		dynClient,
+		updateStrategy,
+		nil,
+		parent,
+		observedChildren,
+		desiredChildren,
+	)
+}
+
+// ManageChildrenWithHooks ensures the relevant children objects
+// of the given parent are in sync. Provided hooks if any are
+// invoked around the update of each child.
+func ManageChildrenWithHooks(
+	dynClient *dynamicclientset.Clientset,
+	updateStrategy ChildUpdateStrategyGetter,
+	hooks ChildUpdateHookInvoker,
+	parent *unstructured.Unstructured,
+	observedChildren, desiredChildren AnyUnstructRegistry,
 ) error {
 	// If some operations fail, keep trying others so, for example,
 	// we don't block recovery (create new Pod) on a failed delete.
@@ -337,6 +378,7 @@ func ManageChildren(
 		if err := updateChildren(
 			client,
 			updateStrategy,
+			hooks,
 			parent,
 			observedChildren[key],
 			objects,
@@ -383,6 +425,7 @@ func deleteChildren(
 func updateChildren(
 	client *dynamicclientset.ResourceClient,
 	updateStrategy ChildUpdateStrategyGetter,
+	hooks ChildUpdateHookInvoker,
 	parent *unstructured.Unstructured,
 	observed, desired map[string]*unstructured.Unstructured,
 ) error {
@@ -418,8 +461,8 @@ func updateChildren(
 			}
 
 			// Check the update strategy for this child kind.
-			switch method := updateStrategy.Get(client.Group, client.Kind); method {
-			case v1alpha1.ChildUpdateOnDelete, "":
+			method := updateStrategy.Get(client.Group, client.Kind)
+			if method == v1alpha1.ChildUpdateOnDelete || method == "" {
 				// This means we don't try to update anything unless it gets deleted
 				// by someone else (we won't delete it ourselves).
 				glog.V(5).Infof(
@@ -428,6 +471,26 @@ func updateChildren(
 					describeObject(obj),
 				)
 				continue
+			}
+
+			// Let the hook veto or delay this update.
+			if hooks != nil {
+				proceed, err := hooks.PreUpdateChild(parent, oldObj, newObj)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if !proceed {
+					glog.Infof(
+						"%v: not updating %v (vetoed by pre update hook)",
+						describeObject(parent),
+						describeObject(obj),
+					)
+					continue
+				}
+			}
+
+			switch method {
 			case v1alpha1.ChildUpdateRecreate, v1alpha1.ChildUpdateRollingRecreate:
 				// Delete the object (now) and recreate it (on the next sync).
 				glog.Infof(
@@ -445,13 +508,25 @@ func updateChildren(
 					errs = append(errs, err)
 					continue
 				}
+				// Let the hook know this child gets recreated.
+				if hooks != nil {
+					hooks.ChildDeletedForUpdate(parent, oldObj)
+				}
 			case v1alpha1.ChildUpdateInPlace, v1alpha1.ChildUpdateRollingInPlace:
 				// Update the object in-place.
 				glog.Infof("%v: updating %v", describeObject(parent), describeObject(obj))
-				if _, err := client.Namespace(ns).Update(newObj, metav1.UpdateOptions{}); err != nil {
+				updatedObj, err := client.Namespace(ns).Update(newObj, metav1.UpdateOptions{})
+				if err != nil {
 					errs = append(errs, err)
 					continue
 				}
+				// Let the hook know about this update.
+				if hooks != nil {
+					if err := hooks.PostUpdateChild(parent, updatedObj); err != nil {
+						errs = append(errs, err)
+						continue
+					}
+				}
 			default:
 				errs = append(errs,
 					fmt.Errorf(
@@ -482,10 +557,18 @@ func updateChildren(
 			ownerRefs = append(ownerRefs, *controllerRef)
 			obj.SetOwnerReferences(ownerRefs)
 
-			if _, err := client.Namespace(ns).Create(obj, metav1.CreateOptions{}); err != nil {
+			createdObj, err := client.Namespace(ns).Create(obj, metav1.CreateOptions{})
+			if err != nil {
 				errs = append(errs, err)
 				continue
 			}
+			// Let the hook know about this create.
+			if hooks != nil {
+				if err := hooks.ChildCreated(parent, createdObj); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
 		}
 	}
 	return utilerrors.NewAggregate(errs)
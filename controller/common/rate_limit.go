@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	dynamicclientset "openebs.io/metac/dynamic/clientset"
+)
+
+// Defaults of the workqueue rate limiter. These are the same as
+// the ones used by workqueue.DefaultControllerRateLimiter
+const (
+	DefaultRateLimiterBaseDelay = 5 * time.Millisecond
+	DefaultRateLimiterMaxDelay  = 1000 * time.Second
+	DefaultRateLimiterQPS       = 10
+	DefaultRateLimiterBurst     = 100
+)
+
+// GetWorkerCount returns the worker count declared by a controller
+// if set, or the provided default otherwise
+func GetWorkerCount(workerCount *int32, defaultCount int) int {
+	if workerCount == nil || *workerCount <= 0 {
+		return defaultCount
+	}
+	return int(*workerCount)
+}
+
+// NewWorkqueueRateLimiter returns the rate limiter of a controller's
+// workqueue based on the provided config. Defaults are used for the
+// fields that are not set.
+func NewWorkqueueRateLimiter(
+	config *v1alpha1.WorkqueueRateLimiter,
+) workqueue.RateLimiter {
+	if config == nil {
+		return workqueue.DefaultControllerRateLimiter()
+	}
+	baseDelay := DefaultRateLimiterBaseDelay
+	if config.BaseDelay != nil {
+		baseDelay = config.BaseDelay.Duration
+	}
+	maxDelay := DefaultRateLimiterMaxDelay
+	if config.MaxDelay != nil {
+		maxDelay = config.MaxDelay.Duration
+	}
+	qps := DefaultRateLimiterQPS
+	if config.QPS != nil && *config.QPS > 0 {
+		qps = int(*config.QPS)
+	}
+	burst := DefaultRateLimiterBurst
+	if config.Burst != nil && *config.Burst > 0 {
+		burst = int(*config.Burst)
+	}
+	return workqueue.NewMaxOfRateLimiter(
+		// per key exponential backoff
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		// overall token bucket
+		&workqueue.BucketRateLimiter{
+			Limiter: rate.NewLimiter(rate.Limit(qps), burst),
+		},
+	)
+}
+
+// NewClientsetForRateLimit returns the clientset to be used by a
+// controller. The provided clientset is returned if rate limit is not
+// set. Otherwise a clientset with a dedicated client that is limited
+// to the provided QPS & burst is returned.
+func NewClientsetForRateLimit(
+	clientset *dynamicclientset.Clientset,
+	limit *v1alpha1.ClientRateLimit,
+) (*dynamicclientset.Clientset, error) {
+	if limit == nil || (limit.QPS == nil && limit.Burst == nil) {
+		return clientset, nil
+	}
+	var qps float32
+	if limit.QPS != nil {
+		qps = float32(*limit.QPS)
+	}
+	var burst int
+	if limit.Burst != nil {
+		burst = int(*limit.Burst)
+	}
+	rateLimited, err := clientset.ForRateLimit(qps, burst)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't create clientset with qps %v & burst %d",
+			qps,
+			burst,
+		)
+	}
+	return rateLimited, nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+)
+
+func TestGetWorkerCount(t *testing.T) {
+	var tests = map[string]struct {
+		workerCount *int32
+		expect      int
+	}{
+		"nil worker count": {
+			expect: 5,
+		},
+		"zero worker count": {
+			workerCount: func() *int32 { c := int32(0); return &c }(),
+			expect:      5,
+		},
+		"positive worker count": {
+			workerCount: func() *int32 { c := int32(2); return &c }(),
+			expect:      2,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := GetWorkerCount(mock.workerCount, 5)
+			if got != mock.expect {
+				t.Fatalf("Expected worker count %d got %d", mock.expect, got)
+			}
+		})
+	}
+}
+
+func TestNewWorkqueueRateLimiter(t *testing.T) {
+	var tests = map[string]struct {
+		config *v1alpha1.WorkqueueRateLimiter
+		expect []time.Duration
+	}{
+		"nil config": {
+			expect: []time.Duration{
+				DefaultRateLimiterBaseDelay,
+				2 * DefaultRateLimiterBaseDelay,
+			},
+		},
+		"base delay": {
+			config: &v1alpha1.WorkqueueRateLimiter{
+				BaseDelay: &metav1.Duration{Duration: time.Second},
+			},
+			expect: []time.Duration{time.Second, 2 * time.Second},
+		},
+		"base delay capped by max delay": {
+			config: &v1alpha1.WorkqueueRateLimiter{
+				BaseDelay: &metav1.Duration{Duration: time.Second},
+				MaxDelay:  &metav1.Duration{Duration: 1500 * time.Millisecond},
+			},
+			expect: []time.Duration{time.Second, 1500 * time.Millisecond},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			limiter := NewWorkqueueRateLimiter(mock.config)
+			for i, expect := range mock.expect {
+				got := limiter.When("key")
+				if got != expect {
+					t.Fatalf(
+						"Expected delay %s got %s for attempt %d",
+						expect,
+						got,
+						i+1,
+					)
+				}
+			}
+		})
+	}
+}
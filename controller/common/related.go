@@ -0,0 +1,482 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+	dynamicinformer "openebs.io/metac/dynamic/informer"
+)
+
+// relatedSelector selects the related objects of a parent as per
+// a RelatedResourceRule
+type relatedSelector struct {
+	apiVersion string
+	resource   string
+
+	// namespace of the related objects; all namespaces are
+	// selected if this is empty
+	namespace string
+
+	// names of the related objects; all names are selected if
+	// this is empty
+	names map[string]bool
+
+	selector labels.Selector
+}
+
+// newRelatedSelector returns a new instance of relatedSelector
+// based on the given parent & rule
+//
+// NOTE:
+//	Related objects of a namespaced parent are selected from the
+// parent's namespace only
+func newRelatedSelector(
+	parent *unstructured.Unstructured,
+	related *dynamicdiscovery.APIResource,
+	rule *v1alpha1.RelatedResourceRule,
+) (*relatedSelector, error) {
+	s := &relatedSelector{
+		apiVersion: rule.APIVersion,
+		resource:   rule.Resource,
+		selector:   labels.Everything(),
+	}
+	if rule.LabelSelector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(rule.LabelSelector)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"Invalid label selector of related resource %q %q",
+				rule.APIVersion,
+				rule.Resource,
+			)
+		}
+		s.selector = selector
+	}
+	if len(rule.Names) != 0 {
+		s.names = make(map[string]bool, len(rule.Names))
+		for _, name := range rule.Names {
+			s.names[name] = true
+		}
+	}
+	if !related.Namespaced {
+		if rule.Namespace != "" {
+			return nil, errors.Errorf(
+				"Namespace %q can't be set for cluster scoped related resource %q %q",
+				rule.Namespace,
+				rule.APIVersion,
+				rule.Resource,
+			)
+		}
+		return s, nil
+	}
+	s.namespace = rule.Namespace
+	if parent.GetNamespace() != "" {
+		if rule.Namespace != "" && rule.Namespace != parent.GetNamespace() {
+			return nil, errors.Errorf(
+				"Namespace %q of related resource %q %q must match parent namespace %q",
+				rule.Namespace,
+				rule.APIVersion,
+				rule.Resource,
+				parent.GetNamespace(),
+			)
+		}
+		s.namespace = parent.GetNamespace()
+	}
+	return s, nil
+}
+
+// String implements Stringer interface
+func (s *relatedSelector) String() string {
+	return fmt.Sprintf(
+		"RelatedSelector %q %q: Namespace %q: Selector %q",
+		s.apiVersion,
+		s.resource,
+		s.namespace,
+		s.selector,
+	)
+}
+
+// Matches returns true if the given object of the given resource
+// is selected
+func (s *relatedSelector) Matches(
+	apiVersion string,
+	resource string,
+	obj *unstructured.Unstructured,
+) bool {
+	if s.apiVersion != apiVersion || s.resource != resource {
+		return false
+	}
+	if s.namespace != "" && s.namespace != obj.GetNamespace() {
+		return false
+	}
+	if len(s.names) != 0 && !s.names[obj.GetName()] {
+		return false
+	}
+	return s.selector.Matches(labels.Set(obj.GetLabels()))
+}
+
+// RelatedResourceTracker provides the related objects of parents as
+// per the rules returned by a customize hook. It enqueues the
+// parents whenever their related objects change.
+//
+// NOTE:
+//	Informers of related resources are created on demand & are
+// closed once no parent selects their resource
+type RelatedResourceTracker struct {
+	// name of the controller used in logs & errors
+	name string
+
+	resources       *dynamicdiscovery.APIResourceDiscovery
+	informerFactory *dynamicinformer.SharedInformerFactory
+
+	// enqueueFn enqueues the parent with the given key
+	enqueueFn func(key string)
+
+	// stopCh stops waiting for the informers to sync
+	stopCh chan struct{}
+
+	// mutex guards the informers, their references & selectors
+	mutex sync.Mutex
+
+	informers ResourceInformerRegistrar
+
+	// references is the number of tracked selectors of each
+	// related resource anchored by the key of its informer
+	references map[string]int
+
+	// selectors of related objects anchored by parent keys
+	selectors map[string][]*relatedSelector
+}
+
+// NewRelatedResourceTracker returns a new instance of
+// RelatedResourceTracker
+func NewRelatedResourceTracker(
+	name string,
+	resources *dynamicdiscovery.APIResourceDiscovery,
+	informerFactory *dynamicinformer.SharedInformerFactory,
+	enqueueFn func(key string),
+) *RelatedResourceTracker {
+	return &RelatedResourceTracker{
+		name:            name,
+		resources:       resources,
+		informerFactory: informerFactory,
+		enqueueFn:       enqueueFn,
+		stopCh:          make(chan struct{}),
+		informers:       make(ResourceInformerRegistrar),
+		references:      make(map[string]int),
+		selectors:       make(map[string][]*relatedSelector),
+	}
+}
+
+// String implements Stringer interface
+func (t *RelatedResourceTracker) String() string {
+	return fmt.Sprintf("RelatedResourceTracker %s", t.name)
+}
+
+// Related returns the related objects of the given parent as per
+// the given rules. The parent identified by the given key gets
+// enqueued whenever any of these related objects change.
+func (t *RelatedResourceTracker) Related(
+	key string,
+	parent *unstructured.Unstructured,
+	rules []*v1alpha1.RelatedResourceRule,
+) (AnyUnstructRegistry, error) {
+	var selectors []*relatedSelector
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		related := t.resources.GetAPIForAPIVersionAndResource(
+			rule.APIVersion,
+			rule.Resource,
+		)
+		if related == nil {
+			return nil, errors.Errorf(
+				"%s: Can't find related resource %q %q",
+				t,
+				rule.APIVersion,
+				rule.Resource,
+			)
+		}
+		selector, err := newRelatedSelector(parent, related, rule)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s", t)
+		}
+		selectors = append(selectors, selector)
+	}
+
+	// track the selectors before listing to not miss the changes
+	// that happen while informers sync
+	informers, err := t.track(key, selectors)
+	if err != nil {
+		return nil, err
+	}
+
+	// wait is bounded so that a related resource that can't be
+	// listed fails this sync & gets the parent requeued instead of
+	// blocking the worker
+	var synced []cache.InformerSynced
+	for _, informer := range informers {
+		synced = append(synced, informer.Informer().HasSynced)
+	}
+	if !waitForCacheSync(t.stopCh, informerSyncTimeout, synced...) {
+		return nil, errors.Errorf(
+			"%s: Informers didn't sync within %s or were stopped",
+			t,
+			informerSyncTimeout,
+		)
+	}
+
+	var objects []*unstructured.Unstructured
+	for i, selector := range selectors {
+		lister := informers[i].Lister()
+		var all []*unstructured.Unstructured
+		var err error
+		if selector.namespace != "" {
+			all, err = lister.ListNamespace(selector.namespace, selector.selector)
+		} else {
+			all, err = lister.List(selector.selector)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: Failed to list: %s", t, selector)
+		}
+		for _, obj := range all {
+			if selector.Matches(selector.apiVersion, selector.resource, obj) {
+				objects = append(objects, obj)
+			}
+		}
+	}
+	return MakeAnyUnstructRegistryByReference(parent, objects), nil
+}
+
+// Forget stops tracking the related objects of the parent with
+// the given key. Informers of the related resources that are not
+// selected by any other parent are closed.
+func (t *RelatedResourceTracker) Forget(key string) {
+	// no informers are created when there are no selectors
+	t.track(key, nil)
+}
+
+// Stop removes the event handlers & closes the informers of
+// related resources
+func (t *RelatedResourceTracker) Stop() {
+	close(t.stopCh)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for _, informer := range t.informers {
+		informer.Informer().RemoveEventHandlers()
+		informer.Close()
+	}
+	t.informers = make(ResourceInformerRegistrar)
+	t.references = make(map[string]int)
+	t.selectors = make(map[string][]*relatedSelector)
+}
+
+// track sets the given selectors of the parent with the given key
+// & returns the informers of the selected resources in the order of
+// these selectors
+//
+// NOTE:
+//	Informers of the given selectors are acquired before the ones
+// of the previous selectors are released. This keeps the informer of
+// a resource that is selected before & after running.
+func (t *RelatedResourceTracker) track(
+	key string,
+	selectors []*relatedSelector,
+) ([]*dynamicinformer.ResourceInformer, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var informers []*dynamicinformer.ResourceInformer
+	for i, selector := range selectors {
+		informer, err := t.acquireInformer(selector.apiVersion, selector.resource)
+		if err != nil {
+			for _, acquired := range selectors[:i] {
+				t.releaseInformer(acquired.apiVersion, acquired.resource)
+			}
+			return nil, err
+		}
+		informers = append(informers, informer)
+	}
+	for _, selector := range t.selectors[key] {
+		t.releaseInformer(selector.apiVersion, selector.resource)
+	}
+	if len(selectors) == 0 {
+		delete(t.selectors, key)
+		return nil, nil
+	}
+	t.selectors[key] = selectors
+	return informers, nil
+}
+
+// releaseInformer removes a reference to the informer of the given
+// related resource. Informer is closed when its last reference is
+// removed.
+//
+// NOTE:
+//	This must be invoked with the tracker mutex held
+func (t *RelatedResourceTracker) releaseInformer(apiVersion, resource string) {
+	key := makeKeyFromAPIVersionResource(apiVersion, resource)
+	count := t.references[key] - 1
+	if count > 0 {
+		t.references[key] = count
+		return
+	}
+	delete(t.references, key)
+	informer := t.informers.Get(apiVersion, resource)
+	if informer == nil {
+		return
+	}
+	informer.Informer().RemoveEventHandlers()
+	informer.Close()
+	delete(t.informers, key)
+	glog.V(4).Infof(
+		"%s: Closed informer for related resource %q %q",
+		t,
+		apiVersion,
+		resource,
+	)
+}
+
+// acquireInformer adds a reference to the informer of the given
+// related resource & returns this informer. Informer is created if
+// it does not exist.
+//
+// NOTE:
+//	This must be invoked with the tracker mutex held
+func (t *RelatedResourceTracker) acquireInformer(
+	apiVersion string,
+	resource string,
+) (*dynamicinformer.ResourceInformer, error) {
+	if informer := t.informers.Get(apiVersion, resource); informer != nil {
+		t.references[makeKeyFromAPIVersionResource(apiVersion, resource)]++
+		return informer, nil
+	}
+	informer, err := t.informerFactory.GetOrCreate(apiVersion, resource)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"%s: Can't create informer for related resource %q %q",
+			t,
+			apiVersion,
+			resource,
+		)
+	}
+	onChange := func(obj interface{}) {
+		t.enqueueParents(apiVersion, resource, obj)
+	}
+	informer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: onChange,
+		UpdateFunc: func(old, cur interface{}) {
+			oldObj, _ := old.(*unstructured.Unstructured)
+			curObj, _ := cur.(*unstructured.Unstructured)
+			// ignore resyncs since these are not changes
+			if oldObj != nil && curObj != nil &&
+				oldObj.GetResourceVersion() == curObj.GetResourceVersion() {
+				return
+			}
+			onChange(cur)
+		},
+		DeleteFunc: onChange,
+	})
+	t.informers.Set(apiVersion, resource, informer)
+	t.references[makeKeyFromAPIVersionResource(apiVersion, resource)] = 1
+	glog.V(4).Infof(
+		"%s: Created informer for related resource %q %q",
+		t,
+		apiVersion,
+		resource,
+	)
+	return informer, nil
+}
+
+// enqueueParents enqueues the parents that are related to the
+// given object of the given resource
+func (t *RelatedResourceTracker) enqueueParents(
+	apiVersion string,
+	resource string,
+	obj interface{},
+) {
+	related, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(
+				errors.Errorf("%s: Can't get object from tombstone %+v", t, obj),
+			)
+			return
+		}
+		related, ok = tombstone.Obj.(*unstructured.Unstructured)
+		if !ok {
+			utilruntime.HandleError(
+				errors.Errorf(
+					"%s: Tombstone object is not *unstructured.Unstructured %#v",
+					t,
+					obj,
+				),
+			)
+			return
+		}
+	}
+	for _, key := range t.parentKeys(apiVersion, resource, related) {
+		glog.V(4).Infof(
+			"%s: Related %s %s/%s changed: Will enqueue %s",
+			t,
+			related.GetKind(),
+			related.GetNamespace(),
+			related.GetName(),
+			key,
+		)
+		t.enqueueFn(key)
+	}
+}
+
+// parentKeys returns the keys of the parents that are related to
+// the given object of the given resource
+func (t *RelatedResourceTracker) parentKeys(
+	apiVersion string,
+	resource string,
+	related *unstructured.Unstructured,
+) []string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var keys []string
+	for key, selectors := range t.selectors {
+		for _, selector := range selectors {
+			if selector.Matches(apiVersion, resource, related) {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package common
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	dynamicclientset "openebs.io/metac/dynamic/clientset"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+	dynamicinformer "openebs.io/metac/dynamic/informer"
+)
+
+// newRelatedTestObject returns an unstructured instance with the
+// given namespace, name & labels
+func newRelatedTestObject(
+	namespace string,
+	name string,
+	lbls map[string]string,
+) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	obj.SetLabels(lbls)
+	return obj
+}
+
+func TestNewRelatedSelector(t *testing.T) {
+	var tests = map[string]struct {
+		parentNamespace string
+		clusterScoped   bool
+		rule            *v1alpha1.RelatedResourceRule
+		obj             *unstructured.Unstructured
+		isErr           bool
+		isMatch         bool
+	}{
+		"namespaced parent selects from its namespace": {
+			parentNamespace: "ns1",
+			rule:            &v1alpha1.RelatedResourceRule{},
+			obj:             newRelatedTestObject("ns1", "cm", nil),
+			isMatch:         true,
+		},
+		"namespaced parent does not select from other namespace": {
+			parentNamespace: "ns1",
+			rule:            &v1alpha1.RelatedResourceRule{},
+			obj:             newRelatedTestObject("ns2", "cm", nil),
+		},
+		"namespaced parent with other namespace is an error": {
+			parentNamespace: "ns1",
+			rule: &v1alpha1.RelatedResourceRule{
+				Namespace: "ns2",
+			},
+			isErr: true,
+		},
+		"cluster scoped parent selects from all namespaces": {
+			rule:    &v1alpha1.RelatedResourceRule{},
+			obj:     newRelatedTestObject("ns2", "cm", nil),
+			isMatch: true,
+		},
+		"cluster scoped parent restricts to namespace": {
+			rule: &v1alpha1.RelatedResourceRule{
+				Namespace: "ns1",
+			},
+			obj: newRelatedTestObject("ns2", "cm", nil),
+		},
+		"namespace of cluster scoped resource is an error": {
+			clusterScoped: true,
+			rule: &v1alpha1.RelatedResourceRule{
+				Namespace: "ns1",
+			},
+			isErr: true,
+		},
+		"label selector matches": {
+			rule: &v1alpha1.RelatedResourceRule{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				},
+			},
+			obj:     newRelatedTestObject("ns1", "cm", map[string]string{"app": "test"}),
+			isMatch: true,
+		},
+		"label selector does not match": {
+			rule: &v1alpha1.RelatedResourceRule{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "test"},
+				},
+			},
+			obj: newRelatedTestObject("ns1", "cm", nil),
+		},
+		"invalid label selector is an error": {
+			rule: &v1alpha1.RelatedResourceRule{
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{Key: "app", Operator: "Invalid"},
+					},
+				},
+			},
+			isErr: true,
+		},
+		"names match": {
+			rule: &v1alpha1.RelatedResourceRule{
+				Names: []string{"cm1", "cm2"},
+			},
+			obj:     newRelatedTestObject("ns1", "cm2", nil),
+			isMatch: true,
+		},
+		"names do not match": {
+			rule: &v1alpha1.RelatedResourceRule{
+				Names: []string{"cm1", "cm2"},
+			},
+			obj: newRelatedTestObject("ns1", "cm3", nil),
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			parent := newRelatedTestObject(mock.parentNamespace, "parent", nil)
+			related := &dynamicdiscovery.APIResource{}
+			related.Namespaced = !mock.clusterScoped
+			mock.rule.APIVersion = "v1"
+			mock.rule.Resource = "configmaps"
+
+			s, err := newRelatedSelector(parent, related, mock.rule)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			got := s.Matches("v1", "configmaps", mock.obj)
+			if got != mock.isMatch {
+				t.Fatalf("Expected match %t got %t", mock.isMatch, got)
+			}
+			if s.Matches("v1", "secrets", mock.obj) {
+				t.Fatalf("Expected no match for other resource")
+			}
+		})
+	}
+}
+
+// newRelatedTestTracker returns a tracker whose informers list &
+// watch namespaced resources from an unreachable server
+func newRelatedTestTracker(t *testing.T) *RelatedResourceTracker {
+	resources := &dynamicdiscovery.APIResourceDiscovery{
+		GetAPIForAPIVersionAndResourceFn: func(
+			apiVersion string,
+			resource string,
+		) *dynamicdiscovery.APIResource {
+			related := &dynamicdiscovery.APIResource{APIVersion: apiVersion}
+			related.Name = resource
+			related.Namespaced = true
+			return related
+		},
+	}
+	clientset, err := dynamicclientset.New(
+		&rest.Config{Host: "http://127.0.0.1:1"},
+		resources,
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	return NewRelatedResourceTracker(
+		"test",
+		resources,
+		dynamicinformer.NewSharedInformerFactory(clientset, time.Minute),
+		nil,
+	)
+}
+
+// newRelatedTestSelector returns a selector of the given resource
+// for the parent with the given key
+func newRelatedTestSelector(
+	t *testing.T,
+	key string,
+	resource string,
+) *relatedSelector {
+	related := &dynamicdiscovery.APIResource{}
+	related.Namespaced = true
+	s, err := newRelatedSelector(
+		newRelatedTestObject(key[:3], key[4:], nil),
+		related,
+		&v1alpha1.RelatedResourceRule{
+			ResourceRule: v1alpha1.ResourceRule{
+				APIVersion: "v1",
+				Resource:   resource,
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	return s
+}
+
+func TestRelatedResourceTrackerParentKeys(t *testing.T) {
+	tracker := newRelatedTestTracker(t)
+	defer tracker.Stop()
+
+	for _, key := range []string{"ns1/p1", "ns1/p2", "ns2/p3"} {
+		_, err := tracker.track(
+			key,
+			[]*relatedSelector{newRelatedTestSelector(t, key, "configmaps")},
+		)
+		if err != nil {
+			t.Fatalf("Expected no error got %+v", err)
+		}
+	}
+	tracker.Forget("ns1/p2")
+
+	var tests = map[string]struct {
+		resource string
+		obj      *unstructured.Unstructured
+		expect   []string
+	}{
+		"parents of same namespace": {
+			resource: "configmaps",
+			obj:      newRelatedTestObject("ns1", "cm", nil),
+			expect:   []string{"ns1/p1"},
+		},
+		"parents of other namespace": {
+			resource: "configmaps",
+			obj:      newRelatedTestObject("ns2", "cm", nil),
+			expect:   []string{"ns2/p3"},
+		},
+		"no parents of other resource": {
+			resource: "secrets",
+			obj:      newRelatedTestObject("ns1", "cm", nil),
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := tracker.parentKeys("v1", mock.resource, mock.obj)
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, mock.expect) {
+				t.Fatalf("Expected keys %v got %v", mock.expect, got)
+			}
+		})
+	}
+}
+
+func TestRelatedResourceTrackerClosesUnreferencedInformers(t *testing.T) {
+	tracker := newRelatedTestTracker(t)
+	defer tracker.Stop()
+
+	// track sets the selectors of the given resources for the
+	// parent with the given key
+	track := func(key string, resources ...string) {
+		var selectors []*relatedSelector
+		for _, resource := range resources {
+			selectors = append(selectors, newRelatedTestSelector(t, key, resource))
+		}
+		informers, err := tracker.track(key, selectors)
+		if err != nil {
+			t.Fatalf("Expected no error got %+v", err)
+		}
+		if len(informers) != len(resources) {
+			t.Fatalf(
+				"Expected %d informers got %d",
+				len(resources),
+				len(informers),
+			)
+		}
+	}
+	// verify matches the informers & their references with the
+	// given references anchored by resource
+	verify := func(step string, expect map[string]int) {
+		for _, resource := range []string{"configmaps", "secrets"} {
+			informer := tracker.informers.Get("v1", resource)
+			got := tracker.references[makeKeyFromAPIVersionResource("v1", resource)]
+			if got != expect[resource] {
+				t.Fatalf(
+					"%s: Expected %d references of %q got %d",
+					step,
+					expect[resource],
+					resource,
+					got,
+				)
+			}
+			if (informer != nil) != (expect[resource] != 0) {
+				t.Fatalf(
+					"%s: Expected informer of %q %t got %t",
+					step,
+					resource,
+					expect[resource] != 0,
+					informer != nil,
+				)
+			}
+		}
+	}
+
+	track("ns1/p1", "configmaps")
+	track("ns1/p2", "configmaps", "secrets")
+	verify("track", map[string]int{"configmaps": 2, "secrets": 1})
+
+	configmaps := tracker.informers.Get("v1", "configmaps")
+	track("ns1/p1", "configmaps")
+	if tracker.informers.Get("v1", "configmaps") != configmaps {
+		t.Fatalf("Expected informer of %q to be reused", "configmaps")
+	}
+	verify("retrack", map[string]int{"configmaps": 2, "secrets": 1})
+
+	track("ns1/p2", "configmaps")
+	verify("untrack secrets", map[string]int{"configmaps": 2})
+
+	tracker.Forget("ns1/p1")
+	verify("forget p1", map[string]int{"configmaps": 1})
+
+	tracker.Forget("ns1/p2")
+	verify("forget p2", map[string]int{})
+
+	tracker.Forget("ns1/p3")
+	verify("forget untracked", map[string]int{})
+}
+
+func TestRelatedResourceTrackerRelated(t *testing.T) {
+	tracker := NewRelatedResourceTracker(
+		"test",
+		&dynamicdiscovery.APIResourceDiscovery{
+			GetAPIForAPIVersionAndResourceFn: func(
+				apiVersion string,
+				resource string,
+			) *dynamicdiscovery.APIResource {
+				return nil
+			},
+		},
+		nil,
+		nil,
+	)
+	parent := newRelatedTestObject("ns1", "parent", nil)
+
+	related, err := tracker.Related("ns1/parent", parent, nil)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !related.IsEmpty() {
+		t.Fatalf("Expected no related objects got %s", related)
+	}
+
+	_, err = tracker.Related(
+		"ns1/parent",
+		parent,
+		[]*v1alpha1.RelatedResourceRule{
+			{
+				ResourceRule: v1alpha1.ResourceRule{
+					APIVersion: "v1",
+					Resource:   "unknowns",
+				},
+			},
+		},
+	)
+	if err == nil {
+		t.Fatalf("Expected error for unknown resource got none")
+	}
+}
@@ -272,6 +272,55 @@ func (s *ReferenceSelection) addReferencePairFromExp(
 	s.referencePairs[refPairKey] = referenceValue
 }
 
+// ReferenceNamespace returns the namespace that a reference must
+// belong to in order to match the provided target. False is returned
+// if references from any namespace may match this target.
+//
+// NOTE:
+//	This helps in narrowing down the references that need to be
+// matched against a target, e.g. watches against an attachment.
+func (s *ReferenceSelection) ReferenceNamespace(
+	target *unstructured.Unstructured,
+) (string, bool) {
+	if target == nil {
+		return "", false
+	}
+	// keys of the target whose values must equal the
+	// reference's namespace
+	var keys []string
+	for _, path := range s.config.MatchReference {
+		if path == "metadata.namespace" {
+			keys = append(keys, path)
+		}
+	}
+	for _, exp := range s.config.MatchReferenceExpressions {
+		switch exp.Operator {
+		case v1alpha1.ReferenceSelectorOpEqualsNamespace:
+			keys = append(keys, exp.Key)
+		case v1alpha1.ReferenceSelectorOpEquals,
+			v1alpha1.ReferenceSelectorOperator(""):
+			refKey := exp.Key
+			if exp.RefKey != "" {
+				refKey = exp.RefKey
+			}
+			if refKey == "metadata.namespace" {
+				keys = append(keys, exp.Key)
+			}
+		}
+	}
+	for _, key := range keys {
+		namespace, found, err := unstructured.NestedString(
+			target.Object,
+			s.pathToFields(key)...,
+		)
+		if err != nil || !found {
+			continue
+		}
+		return namespace, true
+	}
+	return "", false
+}
+
 func (s *ReferenceSelection) walkExpressions() {
 	for idx, exp := range s.config.MatchReferenceExpressions {
 		if exp.Key == "" {
@@ -514,3 +514,94 @@ func TestReferenceSelectorMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestReferenceSelectorReferenceNamespace(t *testing.T) {
+	target := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"namespace": "ns",
+				"labels": map[string]interface{}{
+					"watch.ns": "watch-ns",
+				},
+			},
+		},
+	}
+	var tests = map[string]struct {
+		config          ReferenceSelectorConfig
+		expectNamespace string
+		isNamespaced    bool
+	}{
+		"no reference select terms": {},
+		"match reference with namespace": {
+			config: ReferenceSelectorConfig{
+				MatchReference: []string{"metadata.name", "metadata.namespace"},
+			},
+			expectNamespace: "ns",
+			isNamespaced:    true,
+		},
+		"match reference without namespace": {
+			config: ReferenceSelectorConfig{
+				MatchReference: []string{"metadata.name"},
+			},
+		},
+		"equals namespace expression": {
+			config: ReferenceSelectorConfig{
+				MatchReferenceExpressions: []v1alpha1.ReferenceSelectorRequirement{
+					{
+						Key:      `metadata.labels.watch\.ns`,
+						Operator: v1alpha1.ReferenceSelectorOpEqualsNamespace,
+					},
+				},
+			},
+			expectNamespace: "watch-ns",
+			isNamespaced:    true,
+		},
+		"equals expression with namespace as refkey": {
+			config: ReferenceSelectorConfig{
+				MatchReferenceExpressions: []v1alpha1.ReferenceSelectorRequirement{
+					{
+						Key:      `metadata.labels.watch\.ns`,
+						RefKey:   "metadata.namespace",
+						Operator: v1alpha1.ReferenceSelectorOpEquals,
+					},
+				},
+			},
+			expectNamespace: "watch-ns",
+			isNamespaced:    true,
+		},
+		"not equals namespace expression": {
+			config: ReferenceSelectorConfig{
+				MatchReferenceExpressions: []v1alpha1.ReferenceSelectorRequirement{
+					{
+						Key:      "metadata.namespace",
+						Operator: v1alpha1.ReferenceSelectorOpNotEquals,
+					},
+				},
+			},
+		},
+		"namespace key not found in target": {
+			config: ReferenceSelectorConfig{
+				MatchReferenceExpressions: []v1alpha1.ReferenceSelectorRequirement{
+					{
+						Key:      "spec.namespace",
+						Operator: v1alpha1.ReferenceSelectorOpEqualsNamespace,
+					},
+				},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			s := NewReferenceSelector(mock.config)
+			namespace, isNamespaced := s.ReferenceNamespace(target)
+			if isNamespaced != mock.isNamespaced {
+				t.Fatalf("Expected namespaced %t got %t", mock.isNamespaced, isNamespaced)
+			}
+			if namespace != mock.expectNamespace {
+				t.Fatalf("Expected namespace %q got %q", mock.expectNamespace, namespace)
+			}
+		})
+	}
+}
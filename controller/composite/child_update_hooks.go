@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/events"
+)
+
+// childUpdateHooks invokes preUpdateChild & postUpdateChild
+// hooks around the update of a child. This implements
+// common.ChildUpdateHookInvoker.
+type childUpdateHooks struct {
+	api *v1alpha1.CompositeController
+
+	// dispatcher if set resolves the inline hooks & wraps the
+	// invocations with middlewares
+	dispatcher *common.HookDispatcher
+
+	// tracker if set remembers the children reported ready & the
+	// children being recreated across syncs
+	tracker *childUpdateTracker
+
+	// recorder if set emits events against the parent
+	recorder record.EventRecorder
+
+	// ResyncAfterSeconds is the smallest positive resync that
+	// was requested by the invoked hooks
+	ResyncAfterSeconds float64
+}
+
+// newChildUpdateHooks returns a new instance of childUpdateHooks
+func newChildUpdateHooks(
+	api *v1alpha1.CompositeController,
+	dispatcher *common.HookDispatcher,
+	tracker *childUpdateTracker,
+	recorder record.EventRecorder,
+) *childUpdateHooks {
+	return &childUpdateHooks{
+		api:        api,
+		dispatcher: dispatcher,
+		tracker:    tracker,
+		recorder:   recorder,
+	}
+}
+
+// hasPreUpdateChild returns true if preUpdateChild hook is set
+func (h *childUpdateHooks) hasPreUpdateChild() bool {
+	return h.api.Spec.Hooks != nil && h.api.Spec.Hooks.PreUpdateChild != nil
+}
+
+// hasPostUpdateChild returns true if postUpdateChild hook is set
+func (h *childUpdateHooks) hasPostUpdateChild() bool {
+	return h.api.Spec.Hooks != nil && h.api.Spec.Hooks.PostUpdateChild != nil
+}
+
+// minResyncAfterSeconds returns the smallest positive value
+// amongst the provided resync seconds
+func minResyncAfterSeconds(current, resync float64) float64 {
+	if resync > 0 && (current == 0 || resync < current) {
+		return resync
+	}
+	return current
+}
+
+// PreUpdateChild invokes the preUpdateChild hook. It returns
+// false if the hook vetoed the update.
+func (h *childUpdateHooks) PreUpdateChild(
+	parent, observed, desired *unstructured.Unstructured,
+) (bool, error) {
+	if !h.hasPreUpdateChild() {
+		return true, nil
+	}
+	resp, err := callPreUpdateChildHook(
+		h.api,
+		h.dispatcher,
+		&UpdateChildHookRequest{
+			Parent:       parent,
+			Child:        observed,
+			DesiredChild: desired,
+		},
+	)
+	if err != nil {
+		return false, err
+	}
+	h.ResyncAfterSeconds =
+		minResyncAfterSeconds(h.ResyncAfterSeconds, resp.ResyncAfterSeconds)
+	if resp.Veto {
+		glog.V(3).Infof(
+			"CompositeController %s/%s: PreUpdateChild hook vetoed update of %s/%s of kind %s: %s",
+			h.api.Namespace,
+			h.api.Name,
+			observed.GetNamespace(),
+			observed.GetName(),
+			observed.GetKind(),
+			resp.Message,
+		)
+		return false, nil
+	}
+	return true, nil
+}
+
+// PostUpdateChild invokes the postUpdateChild hook after the child
+// was updated. A child that is not ready is reported via an event
+// against the parent. Rolling update waits till this child is ready.
+func (h *childUpdateHooks) PostUpdateChild(
+	parent, updated *unstructured.Unstructured,
+) error {
+	resp, err := h.IsChildReady(parent, updated)
+	if err != nil {
+		return err
+	}
+	if resp.Ready {
+		return nil
+	}
+	glog.V(3).Infof(
+		"CompositeController %s/%s: Updated %s/%s of kind %s is not ready: %s",
+		h.api.Namespace,
+		h.api.Name,
+		updated.GetNamespace(),
+		updated.GetName(),
+		updated.GetKind(),
+		resp.Message,
+	)
+	events.Normalf(
+		h.recorder,
+		parent,
+		events.ReasonChildNotReady,
+		"Updated %s %s is not ready: %s",
+		updated.GetKind(),
+		updated.GetName(),
+		resp.Message,
+	)
+	return nil
+}
+
+// ChildDeletedForUpdate remembers that the given child is being
+// recreated. Its update completes once it gets created again.
+func (h *childUpdateHooks) ChildDeletedForUpdate(
+	parent, deleted *unstructured.Unstructured,
+) {
+	if !h.hasPostUpdateChild() {
+		return
+	}
+	h.tracker.setRecreating(parent, deleted)
+}
+
+// ChildCreated invokes the postUpdateChild hook if the given child
+// was recreated to be updated
+func (h *childUpdateHooks) ChildCreated(
+	parent, created *unstructured.Unstructured,
+) error {
+	if !h.tracker.popRecreating(parent, created) {
+		return nil
+	}
+	return h.PostUpdateChild(parent, created)
+}
+
+// IsChildReady invokes the postUpdateChild hook to find if the
+// updated child is ready. Child is considered to be ready if
+// postUpdateChild hook is not set.
+//
+// NOTE:
+//	Hook is not invoked for a child that was reported ready at its
+// current version.
+func (h *childUpdateHooks) IsChildReady(
+	parent, updated *unstructured.Unstructured,
+) (*PostUpdateChildHookResponse, error) {
+	if !h.hasPostUpdateChild() || h.tracker.isReady(parent, updated) {
+		return &PostUpdateChildHookResponse{Ready: true}, nil
+	}
+	resp, err := callPostUpdateChildHook(
+		h.api,
+		h.dispatcher,
+		&UpdateChildHookRequest{
+			Parent: parent,
+			Child:  updated,
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	h.ResyncAfterSeconds =
+		minResyncAfterSeconds(h.ResyncAfterSeconds, resp.ResyncAfterSeconds)
+	h.tracker.setReady(parent, updated, resp.Ready)
+	return resp, nil
+}
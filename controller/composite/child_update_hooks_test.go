@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+)
+
+// newChildUpdateHooksTestObject returns an unstructured instance
+// with the given kind, name, uid & generation
+func newChildUpdateHooksTestObject(
+	kind string,
+	name string,
+	uid string,
+	generation int64,
+) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("test.io/v1")
+	obj.SetKind(kind)
+	obj.SetNamespace("ns")
+	obj.SetName(name)
+	obj.SetUID(types.UID(uid))
+	obj.SetGeneration(generation)
+	return obj
+}
+
+func TestChildUpdateHooksPostUpdateChild(t *testing.T) {
+	var invoked int
+	var isReady bool
+	AddPostUpdateChildToInlineRegistry(
+		"post/test-child-update-hooks",
+		func(req *UpdateChildHookRequest, resp *PostUpdateChildHookResponse) error {
+			invoked++
+			resp.Ready = isReady
+			resp.Message = "waiting"
+			return nil
+		},
+	)
+	postFunc := "post/test-child-update-hooks"
+	controller := &v1alpha1.CompositeController{
+		Spec: v1alpha1.CompositeControllerSpec{
+			Hooks: &v1alpha1.CompositeControllerHooks{
+				PostUpdateChild: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &postFunc},
+				},
+			},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	tracker := newChildUpdateTracker()
+	hooks := newChildUpdateHooks(controller, nil, tracker, recorder)
+
+	parent := newChildUpdateHooksTestObject("Parent", "parent", "p1", 1)
+	child := newChildUpdateHooksTestObject("Child", "child", "c1", 1)
+
+	// not ready child is reported & checked again
+	if err := hooks.PostUpdateChild(parent, child); err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if len(recorder.Events) != 1 {
+		t.Fatalf("Expected 1 event got %d", len(recorder.Events))
+	}
+	if got := <-recorder.Events; got != "Normal ChildNotReady Updated Child child is not ready: waiting" {
+		t.Fatalf("Expected not ready event got %q", got)
+	}
+	isReady = true
+	resp, err := hooks.IsChildReady(parent, child)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !resp.Ready || invoked != 2 {
+		t.Fatalf("Expected ready after 2 invocations got %t after %d", resp.Ready, invoked)
+	}
+
+	// ready child is not checked again at the same generation
+	resp, err = hooks.IsChildReady(parent, child)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !resp.Ready || invoked != 2 {
+		t.Fatalf("Expected cached readiness got %t after %d invocations", resp.Ready, invoked)
+	}
+
+	// ready child is checked again at a new generation
+	isReady = false
+	child.SetGeneration(2)
+	resp, err = hooks.IsChildReady(parent, child)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if resp.Ready || invoked != 3 {
+		t.Fatalf("Expected not ready after 3 invocations got %t after %d", resp.Ready, invoked)
+	}
+
+	// recreated child is checked once it is created again
+	isReady = true
+	hooks.ChildDeletedForUpdate(parent, child)
+	other := newChildUpdateHooksTestObject("Child", "other", "c2", 1)
+	if err := hooks.ChildCreated(parent, other); err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if invoked != 3 {
+		t.Fatalf("Expected no invocation for a new child got %d invocations", invoked)
+	}
+	recreated := newChildUpdateHooksTestObject("Child", "child", "c3", 1)
+	if err := hooks.ChildCreated(parent, recreated); err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if invoked != 4 {
+		t.Fatalf("Expected 4 invocations got %d", invoked)
+	}
+	if err := hooks.ChildCreated(parent, recreated); err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if invoked != 4 {
+		t.Fatalf("Expected recreated child to be checked once got %d invocations", invoked)
+	}
+}
+
+func TestChildUpdateTrackerRetain(t *testing.T) {
+	parent := newChildUpdateHooksTestObject("Parent", "parent", "p1", 1)
+	kept := newChildUpdateHooksTestObject("Child", "kept", "c1", 1)
+	gone := newChildUpdateHooksTestObject("Child", "gone", "c2", 1)
+
+	tracker := newChildUpdateTracker()
+	tracker.setReady(parent, kept, true)
+	tracker.setReady(parent, gone, true)
+	tracker.setRecreating(parent, kept)
+	tracker.setRecreating(parent, gone)
+
+	registry := common.MakeAnyUnstructRegistryByReference(
+		parent,
+		[]*unstructured.Unstructured{kept},
+	)
+	tracker.retain(parent, registry, registry)
+
+	if !tracker.isReady(parent, kept) {
+		t.Fatalf("Expected observed child to be ready")
+	}
+	if tracker.isReady(parent, gone) {
+		t.Fatalf("Expected child that is not observed to be forgotten")
+	}
+	if tracker.popRecreating(parent, gone) {
+		t.Fatalf("Expected child that is not desired to be forgotten")
+	}
+	if !tracker.popRecreating(parent, kept) {
+		t.Fatalf("Expected desired child to be recreating")
+	}
+
+	tracker.forget(parentKeyOf(parent))
+	if tracker.isReady(parent, kept) {
+		t.Fatalf("Expected children of forgotten parent to be forgotten")
+	}
+}
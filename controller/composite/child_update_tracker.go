@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"openebs.io/metac/controller/common"
+)
+
+// childUpdateTracker remembers the updates of the children across
+// the syncs of their parents
+//
+// NOTE:
+//	A child reported ready by the postUpdateChild hook is not checked
+// again till its version changes. A child that is not ready is checked
+// on every sync since its readiness may change via its status alone.
+type childUpdateTracker struct {
+	mutex sync.Mutex
+
+	// updates of the children anchored by their parent's key
+	parents map[string]*childUpdates
+}
+
+// childUpdates are the updates of the children of a parent
+type childUpdates struct {
+	// versions of the children reported ready anchored by the
+	// child's UID
+	ready map[types.UID]string
+
+	// children deleted to be recreated with their desired state
+	// anchored by the child's key
+	recreating map[string]bool
+}
+
+// newChildUpdateTracker returns a new instance of childUpdateTracker
+func newChildUpdateTracker() *childUpdateTracker {
+	return &childUpdateTracker{
+		parents: make(map[string]*childUpdates),
+	}
+}
+
+// parentKeyOf returns the key of the given parent. This is same as
+// the key of the parent's queue.
+func parentKeyOf(parent *unstructured.Unstructured) string {
+	if parent.GetNamespace() == "" {
+		return parent.GetName()
+	}
+	return parent.GetNamespace() + "/" + parent.GetName()
+}
+
+// childKeyOf returns the key of the given child of the given parent.
+// The child's namespace defaults to the parent's namespace.
+func childKeyOf(parent, child *unstructured.Unstructured) string {
+	ns := child.GetNamespace()
+	if ns == "" {
+		ns = parent.GetNamespace()
+	}
+	gk := schema.FromAPIVersionAndKind(child.GetAPIVersion(), child.GetKind()).GroupKind()
+	return fmt.Sprintf("%s:%s:%s", gk, ns, child.GetName())
+}
+
+// versionOf returns the version of the given child that decides if
+// its readiness needs to be checked again. Generation is used since
+// it changes with the child's spec only. Resource version is used for
+// the children that don't track their generation.
+func versionOf(child *unstructured.Unstructured) string {
+	if generation := child.GetGeneration(); generation > 0 {
+		return strconv.FormatInt(generation, 10)
+	}
+	return "rv-" + child.GetResourceVersion()
+}
+
+// get returns the updates of the children of the given parent
+//
+// NOTE:
+//	This must be invoked while holding the lock
+func (t *childUpdateTracker) get(parent *unstructured.Unstructured) *childUpdates {
+	key := parentKeyOf(parent)
+	updates, found := t.parents[key]
+	if !found {
+		updates = &childUpdates{
+			ready:      make(map[types.UID]string),
+			recreating: make(map[string]bool),
+		}
+		t.parents[key] = updates
+	}
+	return updates
+}
+
+// isReady returns true if the given child at its current version
+// was reported ready
+func (t *childUpdateTracker) isReady(parent, child *unstructured.Unstructured) bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	version, found := t.get(parent).ready[child.GetUID()]
+	return found && version == versionOf(child)
+}
+
+// setReady remembers the readiness of the given child at its
+// current version
+func (t *childUpdateTracker) setReady(
+	parent, child *unstructured.Unstructured,
+	isReady bool,
+) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	updates := t.get(parent)
+	if !isReady {
+		delete(updates.ready, child.GetUID())
+		return
+	}
+	updates.ready[child.GetUID()] = versionOf(child)
+}
+
+// setRecreating remembers that the given child was deleted to be
+// recreated
+func (t *childUpdateTracker) setRecreating(parent, child *unstructured.Unstructured) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.get(parent).recreating[childKeyOf(parent, child)] = true
+}
+
+// popRecreating returns true if the given child was deleted to be
+// recreated & forgets this child
+func (t *childUpdateTracker) popRecreating(parent, child *unstructured.Unstructured) bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	updates := t.get(parent)
+	key := childKeyOf(parent, child)
+	if !updates.recreating[key] {
+		return false
+	}
+	delete(updates.recreating, key)
+	return true
+}
+
+// retain forgets the children of the given parent that are neither
+// observed nor desired anymore
+func (t *childUpdateTracker) retain(
+	parent *unstructured.Unstructured,
+	observed, desired common.AnyUnstructRegistry,
+) {
+	if t == nil {
+		return
+	}
+	observedUIDs := make(map[types.UID]bool)
+	for _, child := range observed.List() {
+		observedUIDs[child.GetUID()] = true
+	}
+	desiredKeys := make(map[string]bool)
+	for _, child := range desired.List() {
+		desiredKeys[childKeyOf(parent, child)] = true
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	updates := t.get(parent)
+	for uid := range updates.ready {
+		if !observedUIDs[uid] {
+			delete(updates.ready, uid)
+		}
+	}
+	for key := range updates.recreating {
+		if !desiredKeys[key] {
+			delete(updates.recreating, key)
+		}
+	}
+}
+
+// forget forgets the children of the parent with the given key
+func (t *childUpdateTracker) forget(parentKey string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.parents, parentKey)
+}
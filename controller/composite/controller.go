@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
@@ -33,6 +34,7 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
@@ -40,10 +42,13 @@ import (
 	mclisters "openebs.io/metac/client/generated/listers/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
 	"openebs.io/metac/controller/common/finalizer"
+	"openebs.io/metac/debug"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamiccontrollerref "openebs.io/metac/dynamic/controllerref"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicinformer "openebs.io/metac/dynamic/informer"
+	"openebs.io/metac/events"
+	"openebs.io/metac/metrics"
 	k8s "openebs.io/metac/third_party/kubernetes"
 )
 
@@ -61,6 +66,9 @@ type parentController struct {
 
 	revisionLister mclisters.ControllerRevisionLister
 
+	// recorder if set emits events against the parent
+	eventRecorder record.EventRecorder
+
 	stopCh, doneCh chan struct{}
 	queue          workqueue.RateLimitingInterface
 
@@ -68,6 +76,25 @@ type parentController struct {
 	childInformers common.ResourceInformerRegistrar
 
 	finalizer *finalizer.Finalizer
+
+	// synced is set to 1 once the informers of this controller
+	// have synced
+	synced int32
+
+	// tracks the keys being reconciled & their last sync errors
+	keys *debug.KeyTracker
+
+	// hookDispatcher if set resolves the inline hooks & wraps the
+	// hook invocations with middlewares
+	hookDispatcher *common.HookDispatcher
+
+	// related provides the related objects selected by the
+	// customize hook & enqueues the parents when these change
+	related *common.RelatedResourceTracker
+
+	// childUpdates remembers the children reported ready by the
+	// postUpdateChild hook & the children being recreated
+	childUpdates *childUpdateTracker
 }
 
 func newParentController(
@@ -76,8 +103,19 @@ func newParentController(
 	informerFactory *dynamicinformer.SharedInformerFactory,
 	mcClient mcclientset.Interface,
 	revisionLister mclisters.ControllerRevisionLister,
+	recorder record.EventRecorder,
 	api *v1alpha1.CompositeController,
 ) (pc *parentController, newErr error) {
+	// Use a dedicated clientset if this controller declares its
+	// own client rate limits
+	dynClientSet, err := common.NewClientsetForRateLimit(
+		dynClientSet,
+		api.Spec.ClientRateLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	// Make a dynamic client for the parent resource.
 	parentClient, err := dynClientSet.GetClientForAPIVersionAndResource(
 		api.Spec.ParentResource.APIVersion,
@@ -145,16 +183,26 @@ func newParentController(
 		parentInformer: parentInformer,
 		parentResource: parentResource,
 		revisionLister: revisionLister,
+		eventRecorder:  recorder,
 		updateStrategy: updateStrategy,
 		queue: workqueue.NewNamedRateLimitingQueue(
-			workqueue.DefaultControllerRateLimiter(),
+			common.NewWorkqueueRateLimiter(api.Spec.RateLimiter),
 			"CompositeController-"+api.Name,
 		),
 		finalizer: &finalizer.Finalizer{
-			Name:    "metac.openebs.io/compositecontroller-" + api.Name,
-			Enabled: api.Spec.Hooks.Finalize != nil,
+			Name:     "metac.openebs.io/compositecontroller-" + api.Name,
+			Enabled:  api.Spec.Hooks.Finalize != nil,
+			Recorder: recorder,
 		},
+		keys:         debug.NewKeyTracker(),
+		childUpdates: newChildUpdateTracker(),
 	}
+	pc.related = common.NewRelatedResourceTracker(
+		"CompositeController "+api.Name,
+		resources,
+		informerFactory,
+		func(key string) { pc.queue.Add(key) },
+	)
 
 	return pc, nil
 }
@@ -203,6 +251,7 @@ func (pc *parentController) Start(workerCount int) {
 		})
 	}
 
+	workerCount = common.GetWorkerCount(pc.api.Spec.WorkerCount, workerCount)
 	if workerCount <= 0 {
 		workerCount = 5
 	}
@@ -245,6 +294,7 @@ func (pc *parentController) Start(workerCount int) {
 			)
 			return
 		}
+		atomic.StoreInt32(&pc.synced, 1)
 
 		glog.V(5).Infof("Starting %d workers for %s", workerCount, pc)
 		var wg sync.WaitGroup
@@ -259,6 +309,24 @@ func (pc *parentController) Start(workerCount int) {
 	}()
 }
 
+// HasSynced returns true if the informers of this controller
+// have synced
+func (pc *parentController) HasSynced() bool {
+	return atomic.LoadInt32(&pc.synced) == 1
+}
+
+// debugInfo returns the runtime state of this controller
+func (pc *parentController) debugInfo() debug.ControllerInfo {
+	return debug.ControllerInfo{
+		Kind:         "CompositeController",
+		Name:         pc.api.Name,
+		HasSynced:    pc.HasSynced(),
+		QueueLength:  pc.queue.Len(),
+		InFlightKeys: pc.keys.InFlightKeys(),
+		SyncErrors:   pc.keys.SyncErrors(),
+	}
+}
+
 // Stop triggers the cancel signal
 func (pc *parentController) Stop() {
 	// closing stop channel will signal all the workers
@@ -274,6 +342,9 @@ func (pc *parentController) Stop() {
 		informer.Informer().RemoveEventHandlers()
 		informer.Close()
 	}
+	// Remove event handlers and close informers (i.e. decrement the counter)
+	// for all related resources.
+	pc.related.Stop()
 	// Remove event handlers and close informer (i.e. decrement the counter)
 	// for the parent resource.
 	pc.parentInformer.Informer().RemoveEventHandlers()
@@ -296,7 +367,11 @@ func (pc *parentController) processNextWorkItem() bool {
 	}
 
 	defer pc.queue.Done(key)
+	pc.keys.Start(key.(string))
+	start := time.Now()
 	err := pc.sync(key.(string))
+	metrics.RecordSync("CompositeController-"+pc.api.Name, start, err)
+	pc.keys.Done(key.(string), err)
 	if err != nil {
 		utilruntime.HandleError(errors.Wrapf(
 			err,
@@ -553,6 +628,8 @@ func (pc *parentController) sync(key string) error {
 			"CompositeController %s: parent %s/%s has been deleted",
 			pc, namespace, name,
 		)
+		pc.related.Forget(key)
+		pc.childUpdates.forget(key)
 		return nil
 	}
 	if err != nil {
@@ -580,6 +657,12 @@ func (pc *parentController) syncParentObject(parent *unstructured.Unstructured)
 	}
 	parent = updatedParent
 
+	// Select the related objects as per the customize hook if set.
+	related, err := pc.getRelatedObjects(parent)
+	if err != nil {
+		return err
+	}
+
 	// Claim all matching child resources, including orphan/adopt as necessary.
 	observedChildren, err := pc.claimChildren(parent)
 	if err != nil {
@@ -589,12 +672,13 @@ func (pc *parentController) syncParentObject(parent *unstructured.Unstructured)
 	// Reconcile ControllerRevisions belonging to this parent.
 	// Call the sync hook for each revision, then compute the overall status and
 	// desired children, accounting for any rollout in progress.
-	syncResult, err := pc.syncRevisions(parent, observedChildren)
+	syncResult, err := pc.syncRevisions(parent, observedChildren, related)
 	if err != nil {
 		return err
 	}
 	desiredChildren :=
 		common.MakeAnyUnstructRegistryByReference(parent, syncResult.Children)
+	pc.childUpdates.retain(parent, observedChildren, desiredChildren)
 
 	// Enqueue a delayed resync, if requested.
 	if syncResult.ResyncAfterSeconds > 0 {
@@ -672,10 +756,18 @@ func (pc *parentController) syncParentObject(parent *unstructured.Unstructured)
 	// or if it's pending deletion and we have a `finalize` hook.
 	var manageErr error
 	if parent.GetDeletionTimestamp() == nil || pc.finalizer.ShouldFinalize(parent) {
-		// Reconcile children.
-		if err := common.ManageChildren(
+		// Reconcile children. PreUpdateChild & PostUpdateChild
+		// hooks if set are invoked around the update of each child.
+		hooks := newChildUpdateHooks(
+			pc.api,
+			pc.hookDispatcher,
+			pc.childUpdates,
+			pc.eventRecorder,
+		)
+		if err := common.ManageChildrenWithHooks(
 			pc.dynClientSet,
 			pc.updateStrategy,
+			hooks,
 			parent,
 			observedChildren,
 			desiredChildren,
@@ -688,6 +780,13 @@ func (pc *parentController) syncParentObject(parent *unstructured.Unstructured)
 				parent.GetName(),
 			)
 		}
+		// Enqueue a delayed resync, if requested by these hooks.
+		if hooks.ResyncAfterSeconds > 0 {
+			pc.enqueueParentObjectAfter(
+				parent,
+				time.Duration(hooks.ResyncAfterSeconds*float64(time.Second)),
+			)
+		}
 	}
 
 	// Update parent status.
@@ -705,6 +804,54 @@ func (pc *parentController) syncParentObject(parent *unstructured.Unstructured)
 	return manageErr
 }
 
+// getRelatedObjects returns the related objects of the given parent
+// as selected by the customize hook. No objects are related if this
+// hook is not set.
+func (pc *parentController) getRelatedObjects(
+	parent *unstructured.Unstructured,
+) (common.AnyUnstructRegistry, error) {
+	if pc.api.Spec.Hooks == nil || pc.api.Spec.Hooks.Customize == nil {
+		return make(common.AnyUnstructRegistry), nil
+	}
+	key, err := common.KeyFunc(parent)
+	if err != nil {
+		return nil, err
+	}
+	customizeResult, err := callCustomizeHook(
+		pc.api,
+		pc.hookDispatcher,
+		&CustomizeHookRequest{Parent: parent},
+	)
+	if err != nil {
+		events.Warningf(
+			pc.eventRecorder,
+			parent,
+			events.ReasonHookFailed,
+			"Customize hook failed: %s: %v",
+			pc,
+			err,
+		)
+		return nil, errors.Wrapf(
+			err,
+			"%s: customize hook failed for %v/%v",
+			pc,
+			parent.GetNamespace(),
+			parent.GetName(),
+		)
+	}
+	related, err := pc.related.Related(key, parent, customizeResult.RelatedResources)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"CompositeController %s: can't get related objects for %v/%v",
+			pc,
+			parent.GetNamespace(),
+			parent.GetName(),
+		)
+	}
+	return related, nil
+}
+
 // makeSelector builds label selector based on parent
 // instance i.e. generateSelector or .spec.selector
 func (pc *parentController) makeSelector(
@@ -36,6 +36,7 @@ import (
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
 	dynamiccontrollerref "openebs.io/metac/dynamic/controllerref"
+	"openebs.io/metac/events"
 	k8s "openebs.io/metac/third_party/kubernetes"
 )
 
@@ -85,6 +86,7 @@ func (pc *parentController) claimRevisions(
 func (pc *parentController) syncRevisions(
 	parent *unstructured.Unstructured,
 	observedChildren common.AnyUnstructRegistry,
+	related common.AnyUnstructRegistry,
 ) (*SyncHookResponse, error) {
 
 	// If no child resources use rolling updates, just sync the latest parent.
@@ -97,9 +99,18 @@ func (pc *parentController) syncRevisions(
 			Controller: pc.api,
 			Parent:     parent,
 			Children:   observedChildren,
+			Related:    related,
 		}
-		syncResult, err := callSyncHook(pc.api, syncRequest)
+		syncResult, err := callSyncHook(pc.api, pc.hookDispatcher, syncRequest)
 		if err != nil {
+			events.Warningf(
+				pc.eventRecorder,
+				parent,
+				events.ReasonHookFailed,
+				"Sync hook failed: %s: %v",
+				pc,
+				err,
+			)
 			return nil, errors.Wrapf(
 				err,
 				"%s: sync hook failed for %v/%v",
@@ -175,8 +186,9 @@ func (pc *parentController) syncRevisions(
 				Controller: pc.api,
 				Parent:     rev.parent,
 				Children:   observedChildren,
+				Related:    related,
 			}
-			syncResult, err := callSyncHook(pc.api, syncRequest)
+			syncResult, err := callSyncHook(pc.api, pc.hookDispatcher, syncRequest)
 			if err != nil {
 				rev.syncError = err
 				return
@@ -191,6 +203,14 @@ func (pc *parentController) syncRevisions(
 	// If any of the sync calls failed, abort.
 	for _, pr := range parentRevisions {
 		if pr.syncError != nil {
+			events.Warningf(
+				pc.eventRecorder,
+				parent,
+				events.ReasonHookFailed,
+				"Sync hook failed: %s: %v",
+				pc,
+				pr.syncError,
+			)
 			return nil, fmt.Errorf("sync hook failed for %v %v/%v: %v", pc.parentResource.Kind, parent.GetNamespace(), parent.GetName(), pr.syncError)
 		}
 	}
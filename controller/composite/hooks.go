@@ -17,6 +17,7 @@ limitations under the License.
 package composite
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
@@ -24,6 +25,8 @@ import (
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/hooks/grpchook"
 )
 
 // SyncHookRequest is the object sent as JSON to the sync hook.
@@ -32,6 +35,10 @@ type SyncHookRequest struct {
 	Parent     *unstructured.Unstructured    `json:"parent"`
 	Children   common.AnyUnstructRegistry    `json:"children"`
 	Finalizing bool                          `json:"finalizing"`
+
+	// Related are the objects selected by the customize hook. This
+	// is empty if the customize hook is not set.
+	Related common.AnyUnstructRegistry `json:"related"`
 }
 
 // String implements Stringer interface
@@ -45,6 +52,23 @@ func (r *SyncHookRequest) String() string {
 	)
 }
 
+// GRPCMethod returns the method of the gRPC hook that gets
+// invoked for this request
+func (r *SyncHookRequest) GRPCMethod() string {
+	if r.Finalizing {
+		return grpchook.CompositeFinalizeMethod
+	}
+	return grpchook.CompositeSyncMethod
+}
+
+// Validate implements hooks.Validator interface
+func (r *SyncHookRequest) Validate() error {
+	if r.Parent == nil {
+		return errors.Errorf("Nil parent")
+	}
+	return nil
+}
+
 // NewSyncHookRequest returns a new instance of SyncHookRequest
 func NewSyncHookRequest(
 	parent *unstructured.Unstructured,
@@ -72,6 +96,10 @@ type SyncHookResponse struct {
 // HookExecutor can execute a hook
 type HookExecutor struct {
 	Controller *v1alpha1.CompositeController
+
+	// Dispatcher if set resolves the inline hooks from its registry
+	// & wraps the invocations with its middlewares
+	Dispatcher *common.HookDispatcher
 }
 
 // String implements Stringer interface
@@ -101,7 +129,15 @@ func (e *HookExecutor) Execute(req *SyncHookRequest) (*SyncHookResponse, error)
 		e.Controller.Spec.Hooks.Finalize != nil {
 		// Finalize
 		req.Finalizing = true
-		err := common.InvokeHook(e.Controller.Spec.Hooks.Finalize, req, &resp)
+		i := &HookInvoker{
+			Schema:     e.Controller.Spec.Hooks.Finalize,
+			Dispatcher: e.Dispatcher,
+		}
+		err := i.InvokeContext(
+			hookContext(e.Controller, hooks.FinalizeHook),
+			req,
+			&resp,
+		)
 		if err != nil {
 			return nil, errors.Wrapf(err, "%s: Finalize hook failed for %s", e, req)
 		}
@@ -113,7 +149,15 @@ func (e *HookExecutor) Execute(req *SyncHookRequest) (*SyncHookResponse, error)
 				errors.Errorf("%s: Sync hook not defined for %s", e, req)
 		}
 
-		err := common.InvokeHook(e.Controller.Spec.Hooks.Sync, req, &resp)
+		i := &HookInvoker{
+			Schema:     e.Controller.Spec.Hooks.Sync,
+			Dispatcher: e.Dispatcher,
+		}
+		err := i.InvokeContext(
+			hookContext(e.Controller, hooks.SyncHook),
+			req,
+			&resp,
+		)
 		if err != nil {
 			return nil,
 				errors.Wrapf(err, "%s: Sync hook failed for %s", e, req)
@@ -123,10 +167,211 @@ func (e *HookExecutor) Execute(req *SyncHookRequest) (*SyncHookResponse, error)
 	return &resp, nil
 }
 
+// hookContext returns the context to invoke the given controller's
+// hook of the given type
+func hookContext(
+	controller *v1alpha1.CompositeController,
+	hook string,
+) context.Context {
+	return hooks.WithInfo(context.Background(), hooks.Info{
+		Controller: fmt.Sprintf("CompositeController %q", controller.Name),
+		Hook:       hook,
+	})
+}
+
 func callSyncHook(
 	controller *v1alpha1.CompositeController,
+	dispatcher *common.HookDispatcher,
 	request *SyncHookRequest,
 ) (*SyncHookResponse, error) {
-	e := HookExecutor{Controller: controller}
+	e := HookExecutor{Controller: controller, Dispatcher: dispatcher}
 	return e.Execute(request)
 }
+
+// UpdateChildHookRequest is the object sent as JSON to the
+// preUpdateChild & postUpdateChild hooks.
+type UpdateChildHookRequest struct {
+	Controller *v1alpha1.CompositeController `json:"controller"`
+	Parent     *unstructured.Unstructured    `json:"parent"`
+
+	// Child is the child as observed in the cluster. This is
+	// the updated child when sent to postUpdateChild hook.
+	Child *unstructured.Unstructured `json:"child"`
+
+	// DesiredChild is the state the child will be updated to.
+	// This is set only when sent to preUpdateChild hook.
+	DesiredChild *unstructured.Unstructured `json:"desiredChild,omitempty"`
+}
+
+// String implements Stringer interface
+func (r *UpdateChildHookRequest) String() string {
+	if r.Child == nil {
+		return "UpdateChildHookRequest"
+	}
+	return fmt.Sprintf(
+		"UpdateChildHookRequest %s/%s of %s",
+		r.Child.GetNamespace(), r.Child.GetName(), r.Child.GroupVersionKind(),
+	)
+}
+
+// GRPCMethod returns the method of the gRPC hook that gets
+// invoked for this request
+//
+// NOTE:
+//	Desired child is set only for the preUpdateChild hook
+func (r *UpdateChildHookRequest) GRPCMethod() string {
+	if r.DesiredChild != nil {
+		return grpchook.CompositePreUpdateChildMethod
+	}
+	return grpchook.CompositePostUpdateChildMethod
+}
+
+// Validate implements hooks.Validator interface
+func (r *UpdateChildHookRequest) Validate() error {
+	if r.Parent == nil {
+		return errors.Errorf("Nil parent")
+	}
+	if r.Child == nil {
+		return errors.Errorf("Nil child")
+	}
+	return nil
+}
+
+// PreUpdateChildHookResponse is the expected format of the JSON
+// response from the preUpdateChild hook.
+type PreUpdateChildHookResponse struct {
+	// Veto when true skips updating the child. Update is attempted
+	// again in a subsequent sync.
+	Veto bool `json:"veto"`
+
+	// Message explains the reason behind the veto
+	Message string `json:"message,omitempty"`
+
+	// ResyncAfterSeconds requests a resync of the parent. This is
+	// typically set along with veto to delay the update.
+	ResyncAfterSeconds float64 `json:"resyncAfterSeconds"`
+}
+
+// PostUpdateChildHookResponse is the expected format of the JSON
+// response from the postUpdateChild hook.
+type PostUpdateChildHookResponse struct {
+	// Ready reports if the updated child is ready. Rolling update
+	// does not move ahead to the next child till this is true.
+	Ready bool `json:"ready"`
+
+	// Message explains why the child is not ready
+	Message string `json:"message,omitempty"`
+
+	// ResyncAfterSeconds requests a resync of the parent. This is
+	// typically set when the child is not ready.
+	ResyncAfterSeconds float64 `json:"resyncAfterSeconds"`
+}
+
+func callPreUpdateChildHook(
+	controller *v1alpha1.CompositeController,
+	dispatcher *common.HookDispatcher,
+	request *UpdateChildHookRequest,
+) (*PreUpdateChildHookResponse, error) {
+	request.Controller = controller
+
+	var resp PreUpdateChildHookResponse
+	i := &HookInvoker{
+		Schema:     controller.Spec.Hooks.PreUpdateChild,
+		Dispatcher: dispatcher,
+	}
+	err := i.InvokePreUpdateChild(
+		hookContext(controller, hooks.PreUpdateChildHook),
+		request,
+		&resp,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "PreUpdateChild hook failed for %s", request)
+	}
+	return &resp, nil
+}
+
+func callPostUpdateChildHook(
+	controller *v1alpha1.CompositeController,
+	dispatcher *common.HookDispatcher,
+	request *UpdateChildHookRequest,
+) (*PostUpdateChildHookResponse, error) {
+	request.Controller = controller
+
+	var resp PostUpdateChildHookResponse
+	i := &HookInvoker{
+		Schema:     controller.Spec.Hooks.PostUpdateChild,
+		Dispatcher: dispatcher,
+	}
+	err := i.InvokePostUpdateChild(
+		hookContext(controller, hooks.PostUpdateChildHook),
+		request,
+		&resp,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "PostUpdateChild hook failed for %s", request)
+	}
+	return &resp, nil
+}
+
+// CustomizeHookRequest is the object sent as JSON to the
+// customize hook.
+type CustomizeHookRequest struct {
+	Controller *v1alpha1.CompositeController `json:"controller"`
+	Parent     *unstructured.Unstructured    `json:"parent"`
+}
+
+// String implements Stringer interface
+func (r *CustomizeHookRequest) String() string {
+	if r.Parent == nil {
+		return "CustomizeHookRequest"
+	}
+	return fmt.Sprintf(
+		"CustomizeHookRequest %s/%s of %s",
+		r.Parent.GetNamespace(), r.Parent.GetName(), r.Parent.GroupVersionKind(),
+	)
+}
+
+// GRPCMethod returns the method of the gRPC hook that gets
+// invoked for this request
+func (r *CustomizeHookRequest) GRPCMethod() string {
+	return grpchook.CompositeCustomizeMethod
+}
+
+// Validate implements hooks.Validator interface
+func (r *CustomizeHookRequest) Validate() error {
+	if r.Parent == nil {
+		return errors.Errorf("Nil parent")
+	}
+	return nil
+}
+
+// CustomizeHookResponse is the expected format of the JSON
+// response from the customize hook.
+type CustomizeHookResponse struct {
+	// RelatedResources select the objects that are sent to sync &
+	// finalize hooks as related objects
+	RelatedResources []*v1alpha1.RelatedResourceRule `json:"relatedResources"`
+}
+
+func callCustomizeHook(
+	controller *v1alpha1.CompositeController,
+	dispatcher *common.HookDispatcher,
+	request *CustomizeHookRequest,
+) (*CustomizeHookResponse, error) {
+	request.Controller = controller
+
+	var resp CustomizeHookResponse
+	i := &HookInvoker{
+		Schema:     controller.Spec.Hooks.Customize,
+		Dispatcher: dispatcher,
+	}
+	err := i.InvokeCustomize(
+		hookContext(controller, hooks.CustomizeHook),
+		request,
+		&resp,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Customize hook failed for %s", request)
+	}
+	return &resp, nil
+}
@@ -0,0 +1,412 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/metrics"
+)
+
+// InlineInvokeFn is the signature for all inline sync & finalize
+// hook invocation functions
+type InlineInvokeFn func(req *SyncHookRequest, resp *SyncHookResponse) error
+
+// InlinePreUpdateChildFn is the signature for all inline
+// preUpdateChild hook invocation functions
+type InlinePreUpdateChildFn func(
+	req *UpdateChildHookRequest,
+	resp *PreUpdateChildHookResponse,
+) error
+
+// InlinePostUpdateChildFn is the signature for all inline
+// postUpdateChild hook invocation functions
+type InlinePostUpdateChildFn func(
+	req *UpdateChildHookRequest,
+	resp *PostUpdateChildHookResponse,
+) error
+
+// InlineCustomizeFn is the signature for all inline customize hook
+// invocation functions
+type InlineCustomizeFn func(
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error
+
+// InlineHookFunc is the context aware signature of inline sync &
+// finalize hook functions. These are registered with a
+// hooks.Registry via InlineHandler.
+type InlineHookFunc func(
+	ctx context.Context,
+	req *SyncHookRequest,
+	resp *SyncHookResponse,
+) error
+
+// InlinePreUpdateChildHookFunc is the context aware signature of
+// inline preUpdateChild hook functions. These are registered with
+// a hooks.Registry via InlinePreUpdateChildHandler.
+type InlinePreUpdateChildHookFunc func(
+	ctx context.Context,
+	req *UpdateChildHookRequest,
+	resp *PreUpdateChildHookResponse,
+) error
+
+// InlinePostUpdateChildHookFunc is the context aware signature of
+// inline postUpdateChild hook functions. These are registered with
+// a hooks.Registry via InlinePostUpdateChildHandler.
+type InlinePostUpdateChildHookFunc func(
+	ctx context.Context,
+	req *UpdateChildHookRequest,
+	resp *PostUpdateChildHookResponse,
+) error
+
+// InlineCustomizeHookFunc is the context aware signature of inline
+// customize hook functions. These are registered with a
+// hooks.Registry via InlineCustomizeHandler.
+type InlineCustomizeHookFunc func(
+	ctx context.Context,
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error
+
+// InlineHandler returns the given inline sync or finalize hook
+// function as a handler that can be registered with a
+// hooks.Registry
+func InlineHandler(fn InlineHookFunc) hooks.Handler {
+	return func(ctx context.Context, request, response interface{}) error {
+		req, isReq := request.(*SyncHookRequest)
+		resp, isResp := response.(*SyncHookResponse)
+		if !isReq || !isResp {
+			return invalidInlineHookError(request, response)
+		}
+		return fn(ctx, req, resp)
+	}
+}
+
+// InlinePreUpdateChildHandler returns the given inline
+// preUpdateChild hook function as a handler that can be registered
+// with a hooks.Registry
+func InlinePreUpdateChildHandler(fn InlinePreUpdateChildHookFunc) hooks.Handler {
+	return func(ctx context.Context, request, response interface{}) error {
+		req, isReq := request.(*UpdateChildHookRequest)
+		resp, isResp := response.(*PreUpdateChildHookResponse)
+		if !isReq || !isResp {
+			return invalidInlineHookError(request, response)
+		}
+		return fn(ctx, req, resp)
+	}
+}
+
+// InlinePostUpdateChildHandler returns the given inline
+// postUpdateChild hook function as a handler that can be
+// registered with a hooks.Registry
+func InlinePostUpdateChildHandler(fn InlinePostUpdateChildHookFunc) hooks.Handler {
+	return func(ctx context.Context, request, response interface{}) error {
+		req, isReq := request.(*UpdateChildHookRequest)
+		resp, isResp := response.(*PostUpdateChildHookResponse)
+		if !isReq || !isResp {
+			return invalidInlineHookError(request, response)
+		}
+		return fn(ctx, req, resp)
+	}
+}
+
+// InlineCustomizeHandler returns the given inline customize hook
+// function as a handler that can be registered with a
+// hooks.Registry
+func InlineCustomizeHandler(fn InlineCustomizeHookFunc) hooks.Handler {
+	return func(ctx context.Context, request, response interface{}) error {
+		req, isReq := request.(*CustomizeHookRequest)
+		resp, isResp := response.(*CustomizeHookResponse)
+		if !isReq || !isResp {
+			return invalidInlineHookError(request, response)
+		}
+		return fn(ctx, req, resp)
+	}
+}
+
+// invalidInlineHookError returns the error when an inline hook is
+// invoked with the request & response of another hook
+func invalidInlineHookError(request, response interface{}) error {
+	return errors.Errorf(
+		"Invalid composite controller inline hook: Got request %T & response %T",
+		request,
+		response,
+	)
+}
+
+type inlineHookRegistry struct {
+	sync.Mutex
+	invokeFuncs          map[string]InlineInvokeFn
+	preUpdateChildFuncs  map[string]InlinePreUpdateChildFn
+	postUpdateChildFuncs map[string]InlinePostUpdateChildFn
+	customizeFuncs       map[string]InlineCustomizeFn
+}
+
+var inlineHookRegistryInstance = &inlineHookRegistry{
+	invokeFuncs:          make(map[string]InlineInvokeFn),
+	preUpdateChildFuncs:  make(map[string]InlinePreUpdateChildFn),
+	postUpdateChildFuncs: make(map[string]InlinePostUpdateChildFn),
+	customizeFuncs:       make(map[string]InlineCustomizeFn),
+}
+
+// AddToInlineRegistry will add function name and correponding
+// sync or finalize function to inline hook registry
+func AddToInlineRegistry(funcName string, fn InlineInvokeFn) {
+	inlineHookRegistryInstance.Lock()
+	defer inlineHookRegistryInstance.Unlock()
+	inlineHookRegistryInstance.invokeFuncs[funcName] = fn
+}
+
+// AddPreUpdateChildToInlineRegistry will add function name and
+// correponding preUpdateChild function to inline hook registry
+func AddPreUpdateChildToInlineRegistry(funcName string, fn InlinePreUpdateChildFn) {
+	inlineHookRegistryInstance.Lock()
+	defer inlineHookRegistryInstance.Unlock()
+	inlineHookRegistryInstance.preUpdateChildFuncs[funcName] = fn
+}
+
+// AddPostUpdateChildToInlineRegistry will add function name and
+// correponding postUpdateChild function to inline hook registry
+func AddPostUpdateChildToInlineRegistry(funcName string, fn InlinePostUpdateChildFn) {
+	inlineHookRegistryInstance.Lock()
+	defer inlineHookRegistryInstance.Unlock()
+	inlineHookRegistryInstance.postUpdateChildFuncs[funcName] = fn
+}
+
+// AddCustomizeToInlineRegistry will add function name and
+// correponding customize function to inline hook registry
+func AddCustomizeToInlineRegistry(funcName string, fn InlineCustomizeFn) {
+	inlineHookRegistryInstance.Lock()
+	defer inlineHookRegistryInstance.Unlock()
+	inlineHookRegistryInstance.customizeFuncs[funcName] = fn
+}
+
+// InlineHookInvoker manages invocation of inline hook
+type InlineHookInvoker struct {
+	FuncName string
+}
+
+// NewInlineHookInvoker returns a new instance of inline hook invoker
+func NewInlineHookInvoker(funcName string) (*InlineHookInvoker, error) {
+	if funcName == "" {
+		return nil,
+			errors.Errorf("Inline invoker function name can't be empty")
+	}
+	return &InlineHookInvoker{FuncName: funcName}, nil
+}
+
+// Invoke this inline sync or finalize hook by passing the given
+// request and fill up the given response with the hook's response
+func (i *InlineHookInvoker) Invoke(req *SyncHookRequest, resp *SyncHookResponse) error {
+	inlineHookRegistryInstance.Lock()
+	fn := inlineHookRegistryInstance.invokeFuncs[i.FuncName]
+	inlineHookRegistryInstance.Unlock()
+	if fn == nil {
+		return errors.Errorf(
+			"Inline hook function not found for %s", i.FuncName,
+		)
+	}
+	start := time.Now()
+	err := fn(req, resp)
+	metrics.RecordHook(i.FuncName, start, err)
+	return err
+}
+
+// InvokePreUpdateChild invokes this inline preUpdateChild hook by
+// passing the given request and fill up the given response with
+// the hook's response
+func (i *InlineHookInvoker) InvokePreUpdateChild(
+	req *UpdateChildHookRequest,
+	resp *PreUpdateChildHookResponse,
+) error {
+	inlineHookRegistryInstance.Lock()
+	fn := inlineHookRegistryInstance.preUpdateChildFuncs[i.FuncName]
+	inlineHookRegistryInstance.Unlock()
+	if fn == nil {
+		return errors.Errorf(
+			"Inline preUpdateChild hook function not found for %s", i.FuncName,
+		)
+	}
+	start := time.Now()
+	err := fn(req, resp)
+	metrics.RecordHook(i.FuncName, start, err)
+	return err
+}
+
+// InvokePostUpdateChild invokes this inline postUpdateChild hook by
+// passing the given request and fill up the given response with
+// the hook's response
+func (i *InlineHookInvoker) InvokePostUpdateChild(
+	req *UpdateChildHookRequest,
+	resp *PostUpdateChildHookResponse,
+) error {
+	inlineHookRegistryInstance.Lock()
+	fn := inlineHookRegistryInstance.postUpdateChildFuncs[i.FuncName]
+	inlineHookRegistryInstance.Unlock()
+	if fn == nil {
+		return errors.Errorf(
+			"Inline postUpdateChild hook function not found for %s", i.FuncName,
+		)
+	}
+	start := time.Now()
+	err := fn(req, resp)
+	metrics.RecordHook(i.FuncName, start, err)
+	return err
+}
+
+// InvokeCustomize invokes this inline customize hook by passing the
+// given request and fill up the given response with the hook's
+// response
+func (i *InlineHookInvoker) InvokeCustomize(
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error {
+	inlineHookRegistryInstance.Lock()
+	fn := inlineHookRegistryInstance.customizeFuncs[i.FuncName]
+	inlineHookRegistryInstance.Unlock()
+	if fn == nil {
+		return errors.Errorf(
+			"Inline customize hook function not found for %s", i.FuncName,
+		)
+	}
+	start := time.Now()
+	err := fn(req, resp)
+	metrics.RecordHook(i.FuncName, start, err)
+	return err
+}
+
+// HookInvoker manages invocation of hook. This understands inline
+// hook invocation that is supported by composite controller
+type HookInvoker struct {
+	Schema *v1alpha1.Hook
+
+	// Dispatcher if set resolves the inline hooks from its registry
+	// & wraps the invocation with its middlewares
+	Dispatcher *common.HookDispatcher
+}
+
+// inlineInvoker returns a new instance of inline hook invoker
+// based on this hook's function name
+func (i *HookInvoker) inlineInvoker() (*InlineHookInvoker, error) {
+	return NewInlineHookInvoker(*i.Schema.Inline.FuncName)
+}
+
+// Invoke invokes the sync or finalize hook based on the given
+// request & fills the response post successful invocation
+func (i *HookInvoker) Invoke(req *SyncHookRequest, resp *SyncHookResponse) error {
+	return i.InvokeContext(context.Background(), req, resp)
+}
+
+// InvokeContext invokes the sync or finalize hook with the given
+// context based on the given request & fills the response post
+// successful invocation
+func (i *HookInvoker) InvokeContext(
+	ctx context.Context,
+	req *SyncHookRequest,
+	resp *SyncHookResponse,
+) error {
+	return i.Dispatcher.Dispatch(
+		ctx,
+		i.Schema,
+		req,
+		resp,
+		func(_ context.Context, _, _ interface{}) error {
+			ihi, err := i.inlineInvoker()
+			if err != nil {
+				return err
+			}
+			return ihi.Invoke(req, resp)
+		},
+	)
+}
+
+// InvokePreUpdateChild invokes the preUpdateChild hook with the
+// given context based on the given request & fills the response
+// post successful invocation
+func (i *HookInvoker) InvokePreUpdateChild(
+	ctx context.Context,
+	req *UpdateChildHookRequest,
+	resp *PreUpdateChildHookResponse,
+) error {
+	return i.Dispatcher.Dispatch(
+		ctx,
+		i.Schema,
+		req,
+		resp,
+		func(_ context.Context, _, _ interface{}) error {
+			ihi, err := i.inlineInvoker()
+			if err != nil {
+				return err
+			}
+			return ihi.InvokePreUpdateChild(req, resp)
+		},
+	)
+}
+
+// InvokePostUpdateChild invokes the postUpdateChild hook with the
+// given context based on the given request & fills the response
+// post successful invocation
+func (i *HookInvoker) InvokePostUpdateChild(
+	ctx context.Context,
+	req *UpdateChildHookRequest,
+	resp *PostUpdateChildHookResponse,
+) error {
+	return i.Dispatcher.Dispatch(
+		ctx,
+		i.Schema,
+		req,
+		resp,
+		func(_ context.Context, _, _ interface{}) error {
+			ihi, err := i.inlineInvoker()
+			if err != nil {
+				return err
+			}
+			return ihi.InvokePostUpdateChild(req, resp)
+		},
+	)
+}
+
+// InvokeCustomize invokes the customize hook with the given context
+// based on the given request & fills the response post successful
+// invocation
+func (i *HookInvoker) InvokeCustomize(
+	ctx context.Context,
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error {
+	return i.Dispatcher.Dispatch(
+		ctx,
+		i.Schema,
+		req,
+		resp,
+		func(_ context.Context, _, _ interface{}) error {
+			ihi, err := i.inlineInvoker()
+			if err != nil {
+				return err
+			}
+			return ihi.InvokeCustomize(req, resp)
+		},
+	)
+}
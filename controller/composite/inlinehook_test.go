@@ -0,0 +1,314 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package composite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks"
+)
+
+func TestHookExecutorExecuteInline(t *testing.T) {
+	AddToInlineRegistry(
+		"sync/test-composite",
+		func(req *SyncHookRequest, resp *SyncHookResponse) error {
+			resp.Status = map[string]interface{}{
+				"parent": req.Parent.GetName(),
+			}
+			return nil
+		},
+	)
+	AddToInlineRegistry(
+		"finalize/test-composite",
+		func(req *SyncHookRequest, resp *SyncHookResponse) error {
+			resp.Finalized = req.Finalizing
+			return nil
+		},
+	)
+	syncFunc := "sync/test-composite"
+	finalizeFunc := "finalize/test-composite"
+	junkFunc := "junk/test-composite"
+
+	var tests = map[string]struct {
+		hooks           *v1alpha1.CompositeControllerHooks
+		isDeleted       bool
+		expectStatus    map[string]interface{}
+		expectFinalized bool
+		isErr           bool
+	}{
+		"sync": {
+			hooks: &v1alpha1.CompositeControllerHooks{
+				Sync: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &syncFunc},
+				},
+			},
+			expectStatus: map[string]interface{}{"parent": "test"},
+		},
+		"finalize": {
+			hooks: &v1alpha1.CompositeControllerHooks{
+				Sync: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &syncFunc},
+				},
+				Finalize: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &finalizeFunc},
+				},
+			},
+			isDeleted:       true,
+			expectFinalized: true,
+		},
+		"function not registered": {
+			hooks: &v1alpha1.CompositeControllerHooks{
+				Sync: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &junkFunc},
+				},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			parent := &unstructured.Unstructured{}
+			parent.SetName("test")
+			if mock.isDeleted {
+				now := metav1.NewTime(time.Now())
+				parent.SetDeletionTimestamp(&now)
+			}
+			e := &HookExecutor{
+				Controller: &v1alpha1.CompositeController{
+					Spec: v1alpha1.CompositeControllerSpec{
+						Hooks: mock.hooks,
+					},
+				},
+			}
+			resp, err := e.Execute(NewSyncHookRequest(parent, nil))
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if resp.Finalized != mock.expectFinalized {
+				t.Fatalf(
+					"Expected finalized %t got %t",
+					mock.expectFinalized,
+					resp.Finalized,
+				)
+			}
+			if mock.expectStatus != nil &&
+				resp.Status["parent"] != mock.expectStatus["parent"] {
+				t.Fatalf(
+					"Expected status %v got %v",
+					mock.expectStatus,
+					resp.Status,
+				)
+			}
+		})
+	}
+}
+
+func TestCallUpdateChildHooksInline(t *testing.T) {
+	AddPreUpdateChildToInlineRegistry(
+		"pre/test-composite",
+		func(req *UpdateChildHookRequest, resp *PreUpdateChildHookResponse) error {
+			resp.Veto = req.DesiredChild.GetName() == "veto"
+			return nil
+		},
+	)
+	AddPostUpdateChildToInlineRegistry(
+		"post/test-composite",
+		func(req *UpdateChildHookRequest, resp *PostUpdateChildHookResponse) error {
+			resp.Ready = req.Child.GetName() == "ready"
+			return nil
+		},
+	)
+	preFunc := "pre/test-composite"
+	postFunc := "post/test-composite"
+
+	controller := &v1alpha1.CompositeController{
+		Spec: v1alpha1.CompositeControllerSpec{
+			Hooks: &v1alpha1.CompositeControllerHooks{
+				PreUpdateChild: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &preFunc},
+				},
+				PostUpdateChild: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &postFunc},
+				},
+			},
+		},
+	}
+	child := &unstructured.Unstructured{}
+	child.SetName("ready")
+	desired := &unstructured.Unstructured{}
+	desired.SetName("veto")
+
+	preResp, err := callPreUpdateChildHook(
+		controller,
+		nil,
+		&UpdateChildHookRequest{Child: child, DesiredChild: desired},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !preResp.Veto {
+		t.Fatalf("Expected veto got none")
+	}
+	postResp, err := callPostUpdateChildHook(
+		controller,
+		nil,
+		&UpdateChildHookRequest{Child: child},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !postResp.Ready {
+		t.Fatalf("Expected ready got not ready")
+	}
+
+	// sync & update child registries are separate
+	controller.Spec.Hooks.PreUpdateChild.Inline.FuncName = &postFunc
+	_, err = callPreUpdateChildHook(
+		controller,
+		nil,
+		&UpdateChildHookRequest{Child: child, DesiredChild: desired},
+	)
+	if err == nil {
+		t.Fatalf("Expected error got none")
+	}
+}
+
+func TestCallCustomizeHookInline(t *testing.T) {
+	AddCustomizeToInlineRegistry(
+		"customize/test-composite",
+		func(req *CustomizeHookRequest, resp *CustomizeHookResponse) error {
+			resp.RelatedResources = []*v1alpha1.RelatedResourceRule{
+				{
+					ResourceRule: v1alpha1.ResourceRule{
+						APIVersion: "v1",
+						Resource:   "configmaps",
+					},
+					Names: []string{req.Parent.GetName()},
+				},
+			}
+			return nil
+		},
+	)
+	registry := hooks.NewRegistry()
+	err := registry.Register(
+		"customize/test-composite-v2",
+		InlineCustomizeHandler(func(
+			_ context.Context,
+			req *CustomizeHookRequest,
+			resp *CustomizeHookResponse,
+		) error {
+			resp.RelatedResources = []*v1alpha1.RelatedResourceRule{
+				{
+					ResourceRule: v1alpha1.ResourceRule{
+						APIVersion: "v1",
+						Resource:   "secrets",
+					},
+					Names: []string{req.Parent.GetName()},
+				},
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+
+	var tests = map[string]struct {
+		funcName       string
+		dispatcher     *common.HookDispatcher
+		expectResource string
+		isErr          bool
+	}{
+		"customize from package registry": {
+			funcName:       "customize/test-composite",
+			expectResource: "configmaps",
+		},
+		"customize from dispatcher registry": {
+			funcName:       "customize/test-composite-v2",
+			dispatcher:     &common.HookDispatcher{InlineHooks: registry},
+			expectResource: "secrets",
+		},
+		"sync function is not a customize function": {
+			funcName: "sync/test-composite",
+			isErr:    true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			funcName := mock.funcName
+			controller := &v1alpha1.CompositeController{
+				Spec: v1alpha1.CompositeControllerSpec{
+					Hooks: &v1alpha1.CompositeControllerHooks{
+						Customize: &v1alpha1.Hook{
+							Inline: &v1alpha1.Inline{FuncName: &funcName},
+						},
+					},
+				},
+			}
+			parent := &unstructured.Unstructured{}
+			parent.SetName("test")
+
+			resp, err := callCustomizeHook(
+				controller,
+				mock.dispatcher,
+				&CustomizeHookRequest{Parent: parent},
+			)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if len(resp.RelatedResources) != 1 {
+				t.Fatalf(
+					"Expected 1 related resource got %d",
+					len(resp.RelatedResources),
+				)
+			}
+			got := resp.RelatedResources[0]
+			if got.Resource != mock.expectResource {
+				t.Fatalf(
+					"Expected resource %q got %q",
+					mock.expectResource,
+					got.Resource,
+				)
+			}
+			if len(got.Names) != 1 || got.Names[0] != "test" {
+				t.Fatalf("Expected names [test] got %v", got.Names)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package composite
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/golang/glog"
@@ -26,6 +27,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
@@ -33,9 +35,11 @@ import (
 	metainformers "openebs.io/metac/client/generated/informers/externalversions"
 	metalisters "openebs.io/metac/client/generated/listers/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
+	"openebs.io/metac/debug"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicinformer "openebs.io/metac/dynamic/informer"
+	"openebs.io/metac/health"
 	k8s "openebs.io/metac/third_party/kubernetes"
 )
 
@@ -44,6 +48,7 @@ type Metacontroller struct {
 	metaClientset          metaclientset.Interface
 	dynamicClientset       *dynamicclientset.Clientset
 	dynamicInformerFactory *dynamicinformer.SharedInformerFactory
+	eventRecorder          record.EventRecorder
 
 	lister           metalisters.CompositeControllerLister
 	informer         cache.SharedIndexInformer
@@ -54,7 +59,15 @@ type Metacontroller struct {
 	queue             workqueue.RateLimitingInterface
 	parentControllers map[string]*parentController
 
+	// guards parentControllers against concurrent readers e.g.
+	// readiness checks
+	mutex sync.RWMutex
+
 	stopCh, doneCh chan struct{}
+
+	// HookDispatcher if set resolves the inline hooks of the parent
+	// controllers & wraps their hook invocations with middlewares
+	HookDispatcher *common.HookDispatcher
 }
 
 func NewMetacontroller(
@@ -63,6 +76,7 @@ func NewMetacontroller(
 	dynamicInformerFactory *dynamicinformer.SharedInformerFactory,
 	metaInformerFactory metainformers.SharedInformerFactory,
 	metaClientset metaclientset.Interface,
+	recorder record.EventRecorder,
 	workerCount int,
 ) *Metacontroller {
 
@@ -71,6 +85,7 @@ func NewMetacontroller(
 		metaClientset:          metaClientset,
 		dynamicClientset:       dynamicClientset,
 		dynamicInformerFactory: dynamicInformerFactory,
+		eventRecorder:          recorder,
 		workerCount:            workerCount,
 
 		lister:           metaInformerFactory.Metacontroller().V1alpha1().CompositeControllers().Lister(),
@@ -163,7 +178,7 @@ func (mc *Metacontroller) sync(key string) error {
 		// Stop and remove the controller if it exists.
 		if pc, ok := mc.parentControllers[name]; ok {
 			pc.Stop()
-			delete(mc.parentControllers, name)
+			mc.deleteParentController(name)
 		}
 		return nil
 	}
@@ -182,18 +197,92 @@ func (mc *Metacontroller) syncCompositeController(cc *v1alpha1.CompositeControll
 		}
 		// Stop and remove the controller so it can be recreated.
 		pc.Stop()
-		delete(mc.parentControllers, cc.Name)
+		mc.deleteParentController(cc.Name)
 	}
 
-	pc, err := newParentController(mc.resourceManager, mc.dynamicClientset, mc.dynamicInformerFactory, mc.metaClientset, mc.revisionLister, cc)
+	pc, err := newParentController(mc.resourceManager, mc.dynamicClientset, mc.dynamicInformerFactory, mc.metaClientset, mc.revisionLister, mc.eventRecorder, cc)
 	if err != nil {
 		return err
 	}
+	pc.hookDispatcher = mc.HookDispatcher
 	pc.Start(mc.workerCount)
-	mc.parentControllers[cc.Name] = pc
+	mc.setParentController(cc.Name, pc)
 	return nil
 }
 
+func (mc *Metacontroller) setParentController(name string, pc *parentController) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	mc.parentControllers[name] = pc
+}
+
+func (mc *Metacontroller) deleteParentController(name string) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	delete(mc.parentControllers, name)
+}
+
+// parentControllerNames returns the sorted names of the running
+// parent controllers
+//
+// NOTE:
+//	This must be invoked with the lock held
+func (mc *Metacontroller) parentControllerNames() []string {
+	var names []string
+	for name := range mc.parentControllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DebugInfo returns the runtime state of the running parent
+// controllers
+func (mc *Metacontroller) DebugInfo() []debug.ControllerInfo {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+
+	var infos []debug.ControllerInfo
+	for _, name := range mc.parentControllerNames() {
+		infos = append(infos, mc.parentControllers[name].debugInfo())
+	}
+	return infos
+}
+
+// ReadinessChecks returns the readiness checks of the informers
+// of CompositeController & ControllerRevision resources as well
+// as the running parent controllers
+func (mc *Metacontroller) ReadinessChecks() []health.Check {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+
+	checks := []health.Check{
+		health.NewCheck(
+			"CompositeController informer",
+			mc.informer.HasSynced,
+			"Informer has not synced",
+		),
+		health.NewCheck(
+			"ControllerRevision informer",
+			mc.revisionInformer.HasSynced,
+			"Informer has not synced",
+		),
+	}
+	for _, name := range mc.parentControllerNames() {
+		checks = append(
+			checks,
+			health.NewCheck(
+				"CompositeController "+name,
+				mc.parentControllers[name].HasSynced,
+				"Informers have not synced",
+			),
+		)
+	}
+	return checks
+}
+
 func (mc *Metacontroller) enqueueCompositeController(obj interface{}) {
 	key, err := common.KeyFunc(obj)
 	if err != nil {
@@ -26,6 +26,7 @@ import (
 	"openebs.io/metac/controller/common"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicobject "openebs.io/metac/dynamic/object"
+	"openebs.io/metac/events"
 )
 
 func (pc *parentController) syncRollingUpdate(
@@ -138,6 +139,15 @@ func (pc *parentController) syncRollingUpdate(
 				Message: fmt.Sprintf("updating %v %v", kind, name),
 			}
 			dynamicobject.SetCondition(latest.syncResult.Status, updatedCondition)
+			events.Normalf(
+				pc.eventRecorder,
+				latest.parent,
+				events.ReasonRolloutProgressing,
+				"Updating %s %s to revision %s",
+				kind,
+				name,
+				latest.revision.Name,
+			)
 			return nil
 		}
 	}
@@ -158,6 +168,21 @@ func (pc *parentController) shouldContinueRolling(
 	observedChildren common.AnyUnstructRegistry,
 ) error {
 
+	// Resync requested by postUpdateChild hook is honoured by
+	// the latest revision
+	hooks := newChildUpdateHooks(
+		pc.api,
+		pc.hookDispatcher,
+		pc.childUpdates,
+		pc.eventRecorder,
+	)
+	defer func() {
+		latest.syncResult.ResyncAfterSeconds = minResyncAfterSeconds(
+			latest.syncResult.ResyncAfterSeconds,
+			hooks.ResyncAfterSeconds,
+		)
+	}()
+
 	// We continue rolling only if all children claimed by the latest revision
 	// are updated and were observed in a "happy" state, according to the
 	// user-supplied, resource-specific status checks.
@@ -206,6 +231,15 @@ func (pc *parentController) shouldContinueRolling(
 				// pause the rollout.
 				return fmt.Errorf("child %v %v failed status check: %v", ck.Kind, name, err)
 			}
+			// Let the postUpdateChild hook report if this child is ready.
+			// A child that isn't ready pauses the rollout.
+			readiness, err := hooks.IsChildReady(latest.parent, child)
+			if err != nil {
+				return fmt.Errorf("can't check if child %v %v is ready: %v", ck.Kind, name, err)
+			}
+			if !readiness.Ready {
+				return fmt.Errorf("child %v %v is not ready: %v", ck.Kind, name, readiness.Message)
+			}
 		}
 	}
 	return nil
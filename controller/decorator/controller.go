@@ -21,6 +21,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang/glog"
@@ -33,15 +34,19 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
 	"openebs.io/metac/controller/common/finalizer"
+	"openebs.io/metac/debug"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicinformer "openebs.io/metac/dynamic/informer"
 	dynamicobject "openebs.io/metac/dynamic/object"
+	"openebs.io/metac/events"
+	"openebs.io/metac/metrics"
 	k8s "openebs.io/metac/third_party/kubernetes"
 )
 
@@ -89,6 +94,24 @@ type decoratorController struct {
 	// instance that deals with this controller's finalizer
 	// if any
 	finalizer *finalizer.Finalizer
+
+	// recorder if set emits events against the parent
+	eventRecorder record.EventRecorder
+
+	// synced is set to 1 once the informers of this controller
+	// have synced
+	synced int32
+
+	// tracks the keys being reconciled & their last sync errors
+	keys *debug.KeyTracker
+
+	// hookDispatcher if set resolves the inline hooks & wraps the
+	// hook invocations with middlewares
+	hookDispatcher *common.HookDispatcher
+
+	// related provides the related objects selected by the
+	// customize hook & enqueues the parents when these change
+	related *common.RelatedResourceTracker
 }
 
 // newDecoratorController returns a new instance of decorator
@@ -98,9 +121,20 @@ func newDecoratorController(
 	resourceMgr *dynamicdiscovery.APIResourceDiscovery,
 	dynCliSet *dynamicclientset.Clientset,
 	informerFactory *dynamicinformer.SharedInformerFactory,
+	recorder record.EventRecorder,
 	schema *v1alpha1.DecoratorController,
 ) (controller *decoratorController, newErr error) {
 
+	// use a dedicated clientset if this controller declares its
+	// own client rate limits
+	dynCliSet, err := common.NewClientsetForRateLimit(
+		dynCliSet,
+		schema.Spec.ClientRateLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
 	c := &decoratorController{
 		schema:          schema,
 		resourceManager: resourceMgr,
@@ -111,7 +145,7 @@ func newDecoratorController(
 		childInformers:  make(common.ResourceInformerRegistrar),
 
 		queue: workqueue.NewNamedRateLimitingQueue(
-			workqueue.DefaultControllerRateLimiter(),
+			common.NewWorkqueueRateLimiter(schema.Spec.RateLimiter),
 			"DecoratorController-"+schema.Name,
 		),
 
@@ -120,10 +154,20 @@ func newDecoratorController(
 			Name: "metac.openebs.io/decoratorcontroller-" + schema.Name,
 			// gets enabled if Finalize property is set
 			Enabled: schema.Spec.Hooks.Finalize != nil,
+			// emits events when finalizer is added or removed
+			Recorder: recorder,
 		},
-	}
 
-	var err error
+		eventRecorder: recorder,
+
+		keys: debug.NewKeyTracker(),
+	}
+	c.related = common.NewRelatedResourceTracker(
+		"DecoratorController "+schema.Name,
+		resourceMgr,
+		informerFactory,
+		func(key string) { c.queue.Add(key) },
+	)
 
 	c.parentSelector, err = newDecoratorSelector(resourceMgr, schema)
 	if err != nil {
@@ -230,6 +274,7 @@ func (c *decoratorController) Start(workerCount int) {
 		})
 	}
 
+	workerCount = common.GetWorkerCount(c.schema.Spec.WorkerCount, workerCount)
 	if workerCount <= 0 {
 		workerCount = 5
 	}
@@ -263,6 +308,7 @@ func (c *decoratorController) Start(workerCount int) {
 			)
 			return
 		}
+		atomic.StoreInt32(&c.synced, 1)
 
 		glog.V(5).Infof("Starting %d workers for %v", workerCount, c.schema.Name)
 		var wg sync.WaitGroup
@@ -277,6 +323,24 @@ func (c *decoratorController) Start(workerCount int) {
 	}()
 }
 
+// HasSynced returns true if the informers of this controller
+// have synced
+func (c *decoratorController) HasSynced() bool {
+	return atomic.LoadInt32(&c.synced) == 1
+}
+
+// debugInfo returns the runtime state of this controller
+func (c *decoratorController) debugInfo() debug.ControllerInfo {
+	return debug.ControllerInfo{
+		Kind:         "DecoratorController",
+		Name:         c.schema.Name,
+		HasSynced:    c.HasSynced(),
+		QueueLength:  c.queue.Len(),
+		InFlightKeys: c.keys.InFlightKeys(),
+		SyncErrors:   c.keys.SyncErrors(),
+	}
+}
+
 func (c *decoratorController) Stop() {
 	// closing stopCh will unblock all the logics where this
 	// channel was passed earlier. This triggers closing of
@@ -296,6 +360,8 @@ func (c *decoratorController) Stop() {
 		informer.Informer().RemoveEventHandlers()
 		informer.Close()
 	}
+	// Remove event handlers and close informers for all related resources.
+	c.related.Stop()
 	// Remove event handlers and close informer for all parent resources.
 	for _, informer := range c.parentInformers {
 		informer.Informer().RemoveEventHandlers()
@@ -329,7 +395,11 @@ func (c *decoratorController) processNextWorkItem() bool {
 	defer c.queue.Done(key)
 
 	// real reconcile logic happens here
+	c.keys.Start(key.(string))
+	start := time.Now()
 	err := c.sync(key.(string))
+	metrics.RecordSync("DecoratorController-"+c.schema.Name, start, err)
+	c.keys.Done(key.(string), err)
 	if err != nil {
 		utilruntime.HandleError(
 			errors.Errorf("failed to sync %v %q: %v", c.schema.Name, key, err),
@@ -552,6 +622,7 @@ func (c *decoratorController) sync(key string) error {
 	if apierrors.IsNotFound(err) {
 		// Swallow the error since there's no point retrying if the parent is gone.
 		glog.V(4).Infof("%v %v/%v has been deleted", kind, namespace, name)
+		c.related.Forget(key)
 		return nil
 	}
 	if err != nil {
@@ -565,6 +636,7 @@ func (c *decoratorController) syncParentObject(parent *unstructured.Unstructured
 	// ignore it.
 	if !c.parentSelector.Matches(parent) &&
 		!dynamicobject.HasFinalizer(parent, c.finalizer.Name) {
+		c.forgetRelatedObjects(parent)
 		return nil
 	}
 
@@ -603,6 +675,7 @@ func (c *decoratorController) syncParentObject(parent *unstructured.Unstructured
 	// Check the finalizer again in case we just removed it.
 	if !c.parentSelector.Matches(parent) &&
 		!dynamicobject.HasFinalizer(parent, c.finalizer.Name) {
+		c.forgetRelatedObjects(parent)
 		return nil
 	}
 
@@ -613,14 +686,29 @@ func (c *decoratorController) syncParentObject(parent *unstructured.Unstructured
 		return err
 	}
 
+	// Select the related objects as per the customize hook if set.
+	related, err := c.getRelatedObjects(parent)
+	if err != nil {
+		return err
+	}
+
 	// Call the sync hook to get the desired annotations and children.
 	syncRequest := &SyncHookRequest{
 		Controller:  c.schema,
 		Object:      parent,
 		Attachments: observedChildren,
+		Related:     related,
 	}
 	syncResult, err := c.callSyncHook(syncRequest)
 	if err != nil {
+		events.Warningf(
+			c.eventRecorder,
+			parent,
+			events.ReasonHookFailed,
+			"Sync hook failed: DecoratorController %s: %v",
+			c.schema.Name,
+			err,
+		)
 		return err
 	}
 	desiredChildren :=
@@ -730,6 +818,54 @@ func (c *decoratorController) syncParentObject(parent *unstructured.Unstructured
 
 // getChildren returns the child resources of the given parent
 // resource as declared in this decorator controller resource
+// getRelatedObjects returns the related objects of the given parent
+// as selected by the customize hook. No objects are related if this
+// hook is not set.
+func (c *decoratorController) getRelatedObjects(
+	parent *unstructured.Unstructured,
+) (common.AnyUnstructRegistry, error) {
+	if c.schema.Spec.Hooks == nil || c.schema.Spec.Hooks.Customize == nil {
+		return make(common.AnyUnstructRegistry), nil
+	}
+	key, err := parentQueueKey(parent)
+	if err != nil {
+		return nil, err
+	}
+	customizeResult, err := c.callCustomizeHook(&CustomizeHookRequest{Parent: parent})
+	if err != nil {
+		events.Warningf(
+			c.eventRecorder,
+			parent,
+			events.ReasonHookFailed,
+			"Customize hook failed: DecoratorController %s: %v",
+			c.schema.Name,
+			err,
+		)
+		return nil, err
+	}
+	related, err := c.related.Related(key, parent, customizeResult.RelatedResources)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"can't get related objects for %v %v/%v",
+			parent.GetKind(),
+			parent.GetNamespace(),
+			parent.GetName(),
+		)
+	}
+	return related, nil
+}
+
+// forgetRelatedObjects stops tracking the related objects of the
+// given parent
+func (c *decoratorController) forgetRelatedObjects(parent *unstructured.Unstructured) {
+	key, err := parentQueueKey(parent)
+	if err != nil {
+		return
+	}
+	c.related.Forget(key)
+}
+
 func (c *decoratorController) getChildren(
 	parent *unstructured.Unstructured,
 ) (common.AnyUnstructRegistry, error) {
@@ -17,11 +17,16 @@ limitations under the License.
 package decorator
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/hooks/grpchook"
 )
 
 // SyncHookRequest is the object sent as JSON to the sync hook.
@@ -30,6 +35,27 @@ type SyncHookRequest struct {
 	Object      *unstructured.Unstructured    `json:"object"`
 	Attachments common.AnyUnstructRegistry    `json:"attachments"`
 	Finalizing  bool                          `json:"finalizing"`
+
+	// Related are the objects selected by the customize hook. This
+	// is empty if the customize hook is not set.
+	Related common.AnyUnstructRegistry `json:"related"`
+}
+
+// GRPCMethod returns the method of the gRPC hook that gets
+// invoked for this request
+func (r *SyncHookRequest) GRPCMethod() string {
+	if r.Finalizing {
+		return grpchook.DecoratorFinalizeMethod
+	}
+	return grpchook.DecoratorSyncMethod
+}
+
+// Validate implements hooks.Validator interface
+func (r *SyncHookRequest) Validate() error {
+	if r.Object == nil {
+		return errors.Errorf("Nil object")
+	}
+	return nil
 }
 
 // SyncHookResponse is the expected format of the JSON response from the sync hook.
@@ -45,6 +71,15 @@ type SyncHookResponse struct {
 	Finalized bool `json:"finalized"`
 }
 
+// hookContext returns the context to invoke the hook of the given
+// type
+func (c *decoratorController) hookContext(hook string) context.Context {
+	return hooks.WithInfo(context.Background(), hooks.Info{
+		Controller: fmt.Sprintf("DecoratorController %q", c.schema.Name),
+		Hook:       hook,
+	})
+}
+
 func (c *decoratorController) callSyncHook(
 	request *SyncHookRequest,
 ) (*SyncHookResponse, error) {
@@ -67,7 +102,11 @@ func (c *decoratorController) callSyncHook(
 			!c.parentSelector.Matches(request.Object)) {
 		// Finalize
 		request.Finalizing = true
-		err := common.InvokeHook(c.schema.Spec.Hooks.Finalize, request, &response)
+		i := &HookInvoker{
+			Schema:     c.schema.Spec.Hooks.Finalize,
+			Dispatcher: c.hookDispatcher,
+		}
+		err := i.InvokeContext(c.hookContext(hooks.FinalizeHook), request, &response)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Finalize hook failed")
 		}
@@ -78,7 +117,11 @@ func (c *decoratorController) callSyncHook(
 			return nil, errors.Errorf("Sync hook not defined")
 		}
 
-		err := common.InvokeHook(c.schema.Spec.Hooks.Sync, request, &response)
+		i := &HookInvoker{
+			Schema:     c.schema.Spec.Hooks.Sync,
+			Dispatcher: c.hookDispatcher,
+		}
+		err := i.InvokeContext(c.hookContext(hooks.SyncHook), request, &response)
 		if err != nil {
 			return nil, errors.Wrapf(err, "Sync hook failed")
 		}
@@ -86,3 +129,49 @@ func (c *decoratorController) callSyncHook(
 
 	return &response, nil
 }
+
+// CustomizeHookRequest is the object sent as JSON to the customize
+// hook.
+type CustomizeHookRequest struct {
+	Controller *v1alpha1.DecoratorController `json:"controller"`
+	Parent     *unstructured.Unstructured    `json:"parent"`
+}
+
+// GRPCMethod returns the method of the gRPC hook that gets
+// invoked for this request
+func (r *CustomizeHookRequest) GRPCMethod() string {
+	return grpchook.DecoratorCustomizeMethod
+}
+
+// Validate implements hooks.Validator interface
+func (r *CustomizeHookRequest) Validate() error {
+	if r.Parent == nil {
+		return errors.Errorf("Nil parent")
+	}
+	return nil
+}
+
+// CustomizeHookResponse is the expected format of the JSON response
+// from the customize hook.
+type CustomizeHookResponse struct {
+	// RelatedResources select the objects that are sent to sync &
+	// finalize hooks as related objects
+	RelatedResources []*v1alpha1.RelatedResourceRule `json:"relatedResources"`
+}
+
+func (c *decoratorController) callCustomizeHook(
+	request *CustomizeHookRequest,
+) (*CustomizeHookResponse, error) {
+	request.Controller = c.schema
+
+	var response CustomizeHookResponse
+	i := &HookInvoker{
+		Schema:     c.schema.Spec.Hooks.Customize,
+		Dispatcher: c.hookDispatcher,
+	}
+	err := i.InvokeCustomize(c.hookContext(hooks.CustomizeHook), request, &response)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Customize hook failed")
+	}
+	return &response, nil
+}
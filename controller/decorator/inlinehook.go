@@ -0,0 +1,237 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package decorator
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/metrics"
+)
+
+// InlineInvokeFn is the signature for all inline hook invocation functions
+type InlineInvokeFn func(req *SyncHookRequest, resp *SyncHookResponse) error
+
+// InlineCustomizeFn is the signature for all inline customize hook
+// invocation functions
+type InlineCustomizeFn func(
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error
+
+// InlineHookFunc is the context aware signature of inline hook
+// functions. These are registered with a hooks.Registry via
+// InlineHandler.
+type InlineHookFunc func(
+	ctx context.Context,
+	req *SyncHookRequest,
+	resp *SyncHookResponse,
+) error
+
+// InlineCustomizeHookFunc is the context aware signature of inline
+// customize hook functions. These are registered with a
+// hooks.Registry via InlineCustomizeHandler.
+type InlineCustomizeHookFunc func(
+	ctx context.Context,
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error
+
+// InlineHandler returns the given inline hook function as a
+// handler that can be registered with a hooks.Registry
+func InlineHandler(fn InlineHookFunc) hooks.Handler {
+	return func(ctx context.Context, request, response interface{}) error {
+		req, isReq := request.(*SyncHookRequest)
+		resp, isResp := response.(*SyncHookResponse)
+		if !isReq || !isResp {
+			return invalidInlineHookError(request, response)
+		}
+		return fn(ctx, req, resp)
+	}
+}
+
+// InlineCustomizeHandler returns the given inline customize hook
+// function as a handler that can be registered with a
+// hooks.Registry
+func InlineCustomizeHandler(fn InlineCustomizeHookFunc) hooks.Handler {
+	return func(ctx context.Context, request, response interface{}) error {
+		req, isReq := request.(*CustomizeHookRequest)
+		resp, isResp := response.(*CustomizeHookResponse)
+		if !isReq || !isResp {
+			return invalidInlineHookError(request, response)
+		}
+		return fn(ctx, req, resp)
+	}
+}
+
+// invalidInlineHookError returns the error when an inline hook is
+// invoked with the request & response of another hook
+func invalidInlineHookError(request, response interface{}) error {
+	return errors.Errorf(
+		"Invalid decorator controller inline hook: Got request %T & response %T",
+		request,
+		response,
+	)
+}
+
+type inlineHookRegistry struct {
+	sync.Mutex
+	invokeFuncs    map[string]InlineInvokeFn
+	customizeFuncs map[string]InlineCustomizeFn
+}
+
+var inlineHookRegistryInstance = &inlineHookRegistry{
+	invokeFuncs:    make(map[string]InlineInvokeFn),
+	customizeFuncs: make(map[string]InlineCustomizeFn),
+}
+
+// AddToInlineRegistry will add function name and correponding
+// function to inline hook registry
+func AddToInlineRegistry(funcName string, fn InlineInvokeFn) {
+	inlineHookRegistryInstance.Lock()
+	defer inlineHookRegistryInstance.Unlock()
+	inlineHookRegistryInstance.invokeFuncs[funcName] = fn
+}
+
+// AddCustomizeToInlineRegistry will add function name and
+// correponding customize function to inline hook registry
+func AddCustomizeToInlineRegistry(funcName string, fn InlineCustomizeFn) {
+	inlineHookRegistryInstance.Lock()
+	defer inlineHookRegistryInstance.Unlock()
+	inlineHookRegistryInstance.customizeFuncs[funcName] = fn
+}
+
+// InlineHookInvoker manages invocation of inline hook
+type InlineHookInvoker struct {
+	FuncName string
+}
+
+// NewInlineHookInvoker returns a new instance of inline hook invoker
+func NewInlineHookInvoker(funcName string) (*InlineHookInvoker, error) {
+	if funcName == "" {
+		return nil,
+			errors.Errorf("Inline invoker function name can't be empty")
+	}
+	return &InlineHookInvoker{FuncName: funcName}, nil
+}
+
+// Invoke this inline hook by passing the given request
+// and fill up the given response with the hook's response
+func (i *InlineHookInvoker) Invoke(req *SyncHookRequest, resp *SyncHookResponse) error {
+	inlineHookRegistryInstance.Lock()
+	fn := inlineHookRegistryInstance.invokeFuncs[i.FuncName]
+	inlineHookRegistryInstance.Unlock()
+	if fn == nil {
+		return errors.Errorf(
+			"Inline hook function not found for %s", i.FuncName,
+		)
+	}
+	start := time.Now()
+	err := fn(req, resp)
+	metrics.RecordHook(i.FuncName, start, err)
+	return err
+}
+
+// InvokeCustomize invokes this inline customize hook by passing the
+// given request and fill up the given response with the hook's
+// response
+func (i *InlineHookInvoker) InvokeCustomize(
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error {
+	inlineHookRegistryInstance.Lock()
+	fn := inlineHookRegistryInstance.customizeFuncs[i.FuncName]
+	inlineHookRegistryInstance.Unlock()
+	if fn == nil {
+		return errors.Errorf(
+			"Inline customize hook function not found for %s", i.FuncName,
+		)
+	}
+	start := time.Now()
+	err := fn(req, resp)
+	metrics.RecordHook(i.FuncName, start, err)
+	return err
+}
+
+// HookInvoker manages invocation of hook. This understands inline
+// hook invocation that is supported by decorator controller
+type HookInvoker struct {
+	Schema *v1alpha1.Hook
+
+	// Dispatcher if set resolves the inline hooks from its registry
+	// & wraps the invocation with its middlewares
+	Dispatcher *common.HookDispatcher
+}
+
+// Invoke invokes the hook based on the given request & fills the
+// response post successful invocation
+func (i *HookInvoker) Invoke(req *SyncHookRequest, resp *SyncHookResponse) error {
+	return i.InvokeContext(context.Background(), req, resp)
+}
+
+// InvokeContext invokes the hook with the given context based on
+// the given request & fills the response post successful invocation
+func (i *HookInvoker) InvokeContext(
+	ctx context.Context,
+	req *SyncHookRequest,
+	resp *SyncHookResponse,
+) error {
+	return i.Dispatcher.Dispatch(
+		ctx,
+		i.Schema,
+		req,
+		resp,
+		func(_ context.Context, _, _ interface{}) error {
+			// create a new instance of decorator controller based
+			// inline hook invoker
+			ihi, err := NewInlineHookInvoker(*i.Schema.Inline.FuncName)
+			if err != nil {
+				return err
+			}
+			return ihi.Invoke(req, resp)
+		},
+	)
+}
+
+// InvokeCustomize invokes the customize hook with the given context
+// based on the given request & fills the response post successful
+// invocation
+func (i *HookInvoker) InvokeCustomize(
+	ctx context.Context,
+	req *CustomizeHookRequest,
+	resp *CustomizeHookResponse,
+) error {
+	return i.Dispatcher.Dispatch(
+		ctx,
+		i.Schema,
+		req,
+		resp,
+		func(_ context.Context, _, _ interface{}) error {
+			ihi, err := NewInlineHookInvoker(*i.Schema.Inline.FuncName)
+			if err != nil {
+				return err
+			}
+			return ihi.InvokeCustomize(req, resp)
+		},
+	)
+}
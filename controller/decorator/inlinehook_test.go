@@ -0,0 +1,179 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package decorator
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks"
+)
+
+func TestHookInvokerInvokeInline(t *testing.T) {
+	AddToInlineRegistry(
+		"sync/test-decorator",
+		func(req *SyncHookRequest, resp *SyncHookResponse) error {
+			name := req.Object.GetName()
+			resp.Labels = map[string]*string{"decorated": &name}
+			return nil
+		},
+	)
+	syncFunc := "sync/test-decorator"
+	junkFunc := "junk/test-decorator"
+	emptyFunc := ""
+
+	var tests = map[string]struct {
+		funcName    *string
+		expectLabel string
+		isErr       bool
+	}{
+		"registered function": {
+			funcName:    &syncFunc,
+			expectLabel: "test",
+		},
+		"function not registered": {
+			funcName: &junkFunc,
+			isErr:    true,
+		},
+		"empty function name": {
+			funcName: &emptyFunc,
+			isErr:    true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			object := &unstructured.Unstructured{}
+			object.SetName("test")
+			invoker := &HookInvoker{
+				Schema: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: mock.funcName},
+				},
+			}
+			resp := &SyncHookResponse{}
+			err := invoker.Invoke(&SyncHookRequest{Object: object}, resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			label := resp.Labels["decorated"]
+			if label == nil || *label != mock.expectLabel {
+				t.Fatalf("Expected label %q got %v", mock.expectLabel, label)
+			}
+		})
+	}
+}
+
+func TestHookInvokerInvokeCustomizeInline(t *testing.T) {
+	AddCustomizeToInlineRegistry(
+		"customize/test-decorator",
+		func(req *CustomizeHookRequest, resp *CustomizeHookResponse) error {
+			resp.RelatedResources = []*v1alpha1.RelatedResourceRule{
+				{Names: []string{req.Parent.GetName()}},
+			}
+			return nil
+		},
+	)
+	registry := hooks.NewRegistry()
+	err := registry.Register(
+		"customize/test-decorator-v2",
+		InlineCustomizeHandler(func(
+			_ context.Context,
+			req *CustomizeHookRequest,
+			resp *CustomizeHookResponse,
+		) error {
+			resp.RelatedResources = []*v1alpha1.RelatedResourceRule{
+				{Names: []string{req.Parent.GetName() + "-v2"}},
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	customizeFunc := "customize/test-decorator"
+	customizeFuncV2 := "customize/test-decorator-v2"
+	syncFunc := "sync/test-decorator"
+
+	var tests = map[string]struct {
+		funcName   *string
+		dispatcher *common.HookDispatcher
+		expectName string
+		isErr      bool
+	}{
+		"function from package registry": {
+			funcName:   &customizeFunc,
+			expectName: "test",
+		},
+		"function from dispatcher registry": {
+			funcName:   &customizeFuncV2,
+			dispatcher: &common.HookDispatcher{InlineHooks: registry},
+			expectName: "test-v2",
+		},
+		"sync function is not a customize function": {
+			funcName: &syncFunc,
+			isErr:    true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			parent := &unstructured.Unstructured{}
+			parent.SetName("test")
+			invoker := &HookInvoker{
+				Schema: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: mock.funcName},
+				},
+				Dispatcher: mock.dispatcher,
+			}
+			resp := &CustomizeHookResponse{}
+			err := invoker.InvokeCustomize(
+				context.Background(),
+				&CustomizeHookRequest{Parent: parent},
+				resp,
+			)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if len(resp.RelatedResources) != 1 ||
+				len(resp.RelatedResources[0].Names) != 1 ||
+				resp.RelatedResources[0].Names[0] != mock.expectName {
+				t.Fatalf(
+					"Expected related name %q got %+v",
+					mock.expectName,
+					resp.RelatedResources,
+				)
+			}
+		})
+	}
+}
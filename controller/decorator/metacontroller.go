@@ -18,6 +18,7 @@ package decorator
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/golang/glog"
@@ -26,15 +27,18 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	mcinformers "openebs.io/metac/client/generated/informers/externalversions"
 	mclisters "openebs.io/metac/client/generated/listers/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
+	"openebs.io/metac/debug"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicinformer "openebs.io/metac/dynamic/informer"
+	"openebs.io/metac/health"
 	k8s "openebs.io/metac/third_party/kubernetes"
 )
 
@@ -42,6 +46,7 @@ type Metacontroller struct {
 	resourceManager *dynamicdiscovery.APIResourceDiscovery
 	clientset       *dynamicclientset.Clientset
 	informerFactory *dynamicinformer.SharedInformerFactory
+	eventRecorder   record.EventRecorder
 
 	lister   mclisters.DecoratorControllerLister
 	informer cache.SharedIndexInformer
@@ -50,7 +55,16 @@ type Metacontroller struct {
 	queue                workqueue.RateLimitingInterface
 	decoratorControllers map[string]*decoratorController
 
+	// guards decoratorControllers against concurrent readers e.g.
+	// readiness checks
+	mutex sync.RWMutex
+
 	stopCh, doneCh chan struct{}
+
+	// HookDispatcher if set resolves the inline hooks of the
+	// decorator controllers & wraps their hook invocations with
+	// middlewares
+	HookDispatcher *common.HookDispatcher
 }
 
 // NewMetacontroller returns a new instance of Metacontroller
@@ -59,6 +73,7 @@ func NewMetacontroller(
 	clientset *dynamicclientset.Clientset,
 	dynInformers *dynamicinformer.SharedInformerFactory,
 	mcInformerFactory mcinformers.SharedInformerFactory,
+	recorder record.EventRecorder,
 	workerCount int,
 ) *Metacontroller {
 
@@ -66,6 +81,7 @@ func NewMetacontroller(
 		resourceManager: resourceMgr,
 		clientset:       clientset,
 		informerFactory: dynInformers,
+		eventRecorder:   recorder,
 
 		lister:   mcInformerFactory.Metacontroller().V1alpha1().DecoratorControllers().Lister(),
 		informer: mcInformerFactory.Metacontroller().V1alpha1().DecoratorControllers().Informer(),
@@ -158,7 +174,7 @@ func (mc *Metacontroller) sync(key string) error {
 		// Stop and remove the controller if it exists.
 		if c, ok := mc.decoratorControllers[name]; ok {
 			c.Stop()
-			delete(mc.decoratorControllers, name)
+			mc.deleteDecoratorController(name)
 		}
 		return nil
 	}
@@ -177,18 +193,87 @@ func (mc *Metacontroller) syncDecoratorController(dc *v1alpha1.DecoratorControll
 		}
 		// Stop and remove the controller so it can be recreated.
 		c.Stop()
-		delete(mc.decoratorControllers, dc.Name)
+		mc.deleteDecoratorController(dc.Name)
 	}
 
-	c, err := newDecoratorController(mc.resourceManager, mc.clientset, mc.informerFactory, dc)
+	c, err := newDecoratorController(mc.resourceManager, mc.clientset, mc.informerFactory, mc.eventRecorder, dc)
 	if err != nil {
 		return err
 	}
+	c.hookDispatcher = mc.HookDispatcher
 	c.Start(mc.workerCount)
-	mc.decoratorControllers[dc.Name] = c
+	mc.setDecoratorController(dc.Name, c)
 	return nil
 }
 
+func (mc *Metacontroller) setDecoratorController(name string, c *decoratorController) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	mc.decoratorControllers[name] = c
+}
+
+func (mc *Metacontroller) deleteDecoratorController(name string) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	delete(mc.decoratorControllers, name)
+}
+
+// decoratorControllerNames returns the sorted names of the
+// running decorator controllers
+//
+// NOTE:
+//	This must be invoked with the lock held
+func (mc *Metacontroller) decoratorControllerNames() []string {
+	var names []string
+	for name := range mc.decoratorControllers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DebugInfo returns the runtime state of the running decorator
+// controllers
+func (mc *Metacontroller) DebugInfo() []debug.ControllerInfo {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+
+	var infos []debug.ControllerInfo
+	for _, name := range mc.decoratorControllerNames() {
+		infos = append(infos, mc.decoratorControllers[name].debugInfo())
+	}
+	return infos
+}
+
+// ReadinessChecks returns the readiness checks of the informer
+// of DecoratorController resources as well as the running
+// decorator controllers
+func (mc *Metacontroller) ReadinessChecks() []health.Check {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+
+	checks := []health.Check{
+		health.NewCheck(
+			"DecoratorController informer",
+			mc.informer.HasSynced,
+			"Informer has not synced",
+		),
+	}
+	for _, name := range mc.decoratorControllerNames() {
+		checks = append(
+			checks,
+			health.NewCheck(
+				"DecoratorController "+name,
+				mc.decoratorControllers[name].HasSynced,
+				"Informers have not synced",
+			),
+		)
+	}
+	return checks
+}
+
 func (mc *Metacontroller) enqueueDecoratorController(obj interface{}) {
 	key, err := common.KeyFunc(obj)
 	if err != nil {
@@ -0,0 +1,316 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"openebs.io/metac/controller/common"
+	dynamiclister "openebs.io/metac/dynamic/lister"
+)
+
+// addAttachmentEventHandlers registers event handlers against
+// the informers of attachments that have not opted out of
+// resyncing their watches
+func (mgr *WatchController) addAttachmentEventHandlers() {
+	handlers := cache.ResourceEventHandlerFuncs{
+		AddFunc:    mgr.onAttachmentAdd,
+		UpdateFunc: mgr.onAttachmentUpdate,
+		DeleteFunc: mgr.onAttachmentDelete,
+	}
+	// an informer may be declared more than once
+	registered := map[string]bool{}
+	for _, a := range mgr.GCtlConfig.Spec.Attachments {
+		if a.SkipWatchResync != nil && *a.SkipWatchResync {
+			glog.V(4).Infof(
+				"Won't resync watches on changes to attachment %q with version %q: %s",
+				a.Resource,
+				a.APIVersion,
+				mgr,
+			)
+			continue
+		}
+		key := a.APIVersion + ":" + a.Resource
+		if registered[key] {
+			continue
+		}
+		informer := mgr.attachmentInformers.Get(a.APIVersion, a.Resource)
+		if informer == nil {
+			// this should not happen since informers for all the
+			// attachments are created during initialisation
+			continue
+		}
+		informer.Informer().AddEventHandler(handlers)
+		registered[key] = true
+	}
+}
+
+// onAttachmentAdd enqueues the watches affected by the added
+// attachment
+func (mgr *WatchController) onAttachmentAdd(obj interface{}) {
+	attachment, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	mgr.enqueueWatchesOfAttachment(attachment)
+}
+
+// onAttachmentUpdate enqueues the watches affected by the old as
+// well as the updated state of the attachment
+func (mgr *WatchController) onAttachmentUpdate(old, cur interface{}) {
+	oldAttachment, ok := old.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	curAttachment, ok := cur.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	// Don't sync if it's a no-op update (probably a relist/resync).
+	// Watches are resynced by their own resync period.
+	if oldAttachment.GetResourceVersion() == curAttachment.GetResourceVersion() {
+		return
+	}
+	// NOTE:
+	//	Old state is considered since the update might have
+	// resulted in this attachment no longer matching some
+	// of the watches
+	mgr.enqueueWatchesOfAttachment(oldAttachment)
+	mgr.enqueueWatchesOfAttachment(curAttachment)
+}
+
+// onAttachmentDelete enqueues the watches affected by the deleted
+// attachment
+func (mgr *WatchController) onAttachmentDelete(obj interface{}) {
+	attachment, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			utilruntime.HandleError(
+				errors.Errorf(
+					"Can't get attachment from tombstone %+v: %s",
+					obj,
+					mgr,
+				),
+			)
+			return
+		}
+		attachment, ok = tombstone.Obj.(*unstructured.Unstructured)
+		if !ok {
+			utilruntime.HandleError(
+				errors.Errorf(
+					"Tombstone is not *unstructured.Unstructured %+v: %s",
+					obj,
+					mgr,
+				),
+			)
+			return
+		}
+	}
+	mgr.enqueueWatchesOfAttachment(attachment)
+}
+
+// enqueueWatchesOfAttachment enqueues all the watches that are
+// related to the provided attachment
+func (mgr *WatchController) enqueueWatchesOfAttachment(
+	attachment *unstructured.Unstructured,
+) {
+	watches, err := mgr.findWatchesOfAttachment(attachment)
+	if err != nil {
+		utilruntime.HandleError(
+			errors.Wrapf(
+				err,
+				"Can't find watches of attachment %s: %s",
+				common.DescObjectAsKey(attachment),
+				mgr,
+			),
+		)
+		return
+	}
+	for _, watch := range watches {
+		glog.V(6).Infof(
+			"Attachment %s changed: Will enqueue watch %s: %s",
+			common.DescObjectAsKey(attachment),
+			common.DescObjectAsKey(watch),
+			mgr,
+		)
+		mgr.enqueueWatch(watch)
+	}
+}
+
+// findWatchesOfAttachment returns the watches related to the
+// provided attachment. A watch is related to the attachment if
+// either of the following is true:
+//
+// - watch is an owner of this attachment,
+// - attachment was created or updated due to this watch,
+// - attachment matches the attachment selector against this watch
+func (mgr *WatchController) findWatchesOfAttachment(
+	attachment *unstructured.Unstructured,
+) ([]*unstructured.Unstructured, error) {
+	watchAPIVersion := mgr.GCtlConfig.Spec.Watch.APIVersion
+	watchResource := mgr.GCtlConfig.Spec.Watch.Resource
+	watchInformer := mgr.watchInformers.Get(watchAPIVersion, watchResource)
+	if watchInformer == nil {
+		return nil, errors.Errorf(
+			"Can't find informer for watch %q with version %q",
+			watchResource,
+			watchAPIVersion,
+		)
+	}
+	watches, err := findRelatedWatches(
+		watchInformer.Lister(),
+		mgr.attachmentSelector,
+		attachment,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't find watch %q with version %q",
+			watchResource,
+			watchAPIVersion,
+		)
+	}
+	return watches, nil
+}
+
+// findRelatedWatches returns the watches that are related to the
+// provided attachment either by UID or by the attachment selector
+//
+// NOTE:
+//	Watches referred to by the UIDs set in the attachment's owner
+// references & annotations are merged with the watches matching the
+// attachment selector. Watches are matched only if the attachment
+// passes the label, annotation & name selectors. In addition, only
+// the watches from the namespace that is referred to by the advanced
+// selector are matched.
+func findRelatedWatches(
+	lister *dynamiclister.Lister,
+	sel *Selection,
+	attachment *unstructured.Unstructured,
+) ([]*unstructured.Unstructured, error) {
+	watches, err := findWatchesByUID(lister, getRelatedWatchUIDs(attachment))
+	if err != nil {
+		return nil, errors.Wrapf(err, "Get by uid failed")
+	}
+	// label, annotation & name selectors don't depend on
+	// the watch & hence are evaluated once
+	isLANMatch, err := sel.MatchLAN(attachment)
+	if err != nil || !isLANMatch {
+		if err != nil {
+			glog.V(4).Infof(
+				"Match failed for attachment %s: %v",
+				common.DescObjectAsKey(attachment),
+				err,
+			)
+		}
+		return watches, nil
+	}
+	var candidates []*unstructured.Unstructured
+	if namespace, ok := sel.WatchNamespaceOfAttachment(attachment); ok {
+		candidates, err = lister.ListNamespace(namespace, labels.Everything())
+	} else {
+		candidates, err = lister.List(labels.Everything())
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "List failed")
+	}
+	found := map[types.UID]bool{}
+	for _, watch := range watches {
+		found[watch.GetUID()] = true
+	}
+	for _, watch := range candidates {
+		if found[watch.GetUID()] {
+			continue
+		}
+		isMatch, err := sel.MatchAttachmentAgainstWatch(attachment, watch)
+		if err != nil {
+			// ignore this watch since a selector error
+			// is not expected to recover till the next
+			// change to this attachment
+			glog.V(4).Infof(
+				"Match failed for attachment %s against watch %s: %v",
+				common.DescObjectAsKey(attachment),
+				common.DescObjectAsKey(watch),
+				err,
+			)
+			continue
+		}
+		if isMatch {
+			watches = append(watches, watch)
+		}
+	}
+	return watches, nil
+}
+
+// findWatchesByUID returns the watches with the provided UIDs. UIDs
+// that don't refer to any watch are ignored since these may refer to
+// other owners of the attachment or to watches that no longer exist.
+func findWatchesByUID(
+	lister *dynamiclister.Lister,
+	uids map[string]bool,
+) ([]*unstructured.Unstructured, error) {
+	// sort the UIDs to return the watches in a deterministic order
+	var sortedUIDs []string
+	for uid := range uids {
+		sortedUIDs = append(sortedUIDs, uid)
+	}
+	sort.Strings(sortedUIDs)
+
+	var watches []*unstructured.Unstructured
+	for _, uid := range sortedUIDs {
+		watch, err := lister.GetByUID(uid)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		watches = append(watches, watch)
+	}
+	return watches, nil
+}
+
+// getRelatedWatchUIDs returns the UIDs of watches that own the
+// provided attachment or were responsible to create or update
+// this attachment
+func getRelatedWatchUIDs(attachment *unstructured.Unstructured) map[string]bool {
+	uids := map[string]bool{}
+	for _, ref := range attachment.GetOwnerReferences() {
+		uids[string(ref.UID)] = true
+	}
+	for key, value := range attachment.GetAnnotations() {
+		if key == common.AttachmentCreateAnnotationKey {
+			uids[value] = true
+			continue
+		}
+		if strings.HasSuffix(key, common.AttachmentUpdateAnnotationKeySuffix) {
+			uids[strings.TrimSuffix(key, common.AttachmentUpdateAnnotationKeySuffix)] = true
+		}
+	}
+	return uids
+}
@@ -0,0 +1,272 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+	dynamiclister "openebs.io/metac/dynamic/lister"
+)
+
+func TestGetRelatedWatchUIDs(t *testing.T) {
+	var tests = map[string]struct {
+		ownerUIDs   []string
+		annotations map[string]string
+		expect      []string
+	}{
+		"no owners & no annotations": {},
+		"owned by watch": {
+			ownerUIDs: []string{"w1"},
+			expect:    []string{"w1"},
+		},
+		"created due to watch": {
+			annotations: map[string]string{
+				common.AttachmentCreateAnnotationKey: "w1",
+			},
+			expect: []string{"w1"},
+		},
+		"updated due to watches": {
+			annotations: map[string]string{
+				"w1" + common.AttachmentUpdateAnnotationKeySuffix: "1",
+				"w2" + common.AttachmentUpdateAnnotationKeySuffix: "1",
+				"app": "metac",
+			},
+			expect: []string{"w1", "w2"},
+		},
+		"owned, created & updated due to watches": {
+			ownerUIDs: []string{"w1"},
+			annotations: map[string]string{
+				common.AttachmentCreateAnnotationKey:              "w1",
+				"w3" + common.AttachmentUpdateAnnotationKeySuffix: "1",
+			},
+			expect: []string{"w1", "w3"},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			attachment := &unstructured.Unstructured{}
+			var refs []metav1.OwnerReference
+			for _, uid := range mock.ownerUIDs {
+				refs = append(refs, metav1.OwnerReference{UID: types.UID(uid)})
+			}
+			attachment.SetOwnerReferences(refs)
+			attachment.SetAnnotations(mock.annotations)
+
+			got := getRelatedWatchUIDs(attachment)
+			if len(got) != len(mock.expect) {
+				t.Fatalf("Expected uids %v got %v", mock.expect, got)
+			}
+			for _, uid := range mock.expect {
+				if !got[uid] {
+					t.Fatalf("Expected uid %q in %v", uid, got)
+				}
+			}
+		})
+	}
+}
+
+func TestFindWatchesByUID(t *testing.T) {
+	indexer := cache.NewIndexer(
+		cache.MetaNamespaceKeyFunc,
+		cache.Indexers{
+			dynamiclister.UIDIndex: dynamiclister.UIDIndexFunc,
+		},
+	)
+	for _, name := range []string{"w1", "w2", "w3"} {
+		watch := &unstructured.Unstructured{}
+		watch.SetNamespace("ns")
+		watch.SetName(name)
+		watch.SetUID(types.UID(name))
+		err := indexer.Add(watch)
+		if err != nil {
+			t.Fatalf("Can't add watch %s: %v", name, err)
+		}
+	}
+	lister := dynamiclister.New(schema.GroupResource{Resource: "watches"}, indexer)
+
+	var tests = map[string]struct {
+		uids   map[string]bool
+		expect []string
+	}{
+		"no uids": {},
+		"uids of watches": {
+			uids:   map[string]bool{"w3": true, "w1": true},
+			expect: []string{"w1", "w3"},
+		},
+		"uids of other owners": {
+			uids:   map[string]bool{"deploy": true, "w2": true},
+			expect: []string{"w2"},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := findWatchesByUID(lister, mock.uids)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if len(got) != len(mock.expect) {
+				t.Fatalf("Expected watches %v got %d watches", mock.expect, len(got))
+			}
+			for i, watch := range got {
+				if watch.GetName() != mock.expect[i] {
+					t.Fatalf("Expected watch %q got %q", mock.expect[i], watch.GetName())
+				}
+			}
+		})
+	}
+}
+
+func TestFindRelatedWatches(t *testing.T) {
+	indexer := cache.NewIndexer(
+		cache.MetaNamespaceKeyFunc,
+		cache.Indexers{
+			cache.NamespaceIndex:   cache.MetaNamespaceIndexFunc,
+			dynamiclister.UIDIndex: dynamiclister.UIDIndexFunc,
+		},
+	)
+	for _, nsName := range [][]string{
+		{"ns-a", "watch-a"},
+		{"ns-b", "watch-b"},
+		{"ns-c", "watch-c"},
+	} {
+		watch := &unstructured.Unstructured{}
+		watch.SetNamespace(nsName[0])
+		watch.SetName(nsName[1])
+		watch.SetUID(types.UID(nsName[1]))
+		err := indexer.Add(watch)
+		if err != nil {
+			t.Fatalf("Can't add watch %s: %v", nsName[1], err)
+		}
+	}
+	lister := dynamiclister.New(schema.GroupResource{Resource: "watches"}, indexer)
+
+	discoveryMgr := &dynamicdiscovery.APIResourceDiscovery{
+		GetAPIForAPIVersionAndResourceFn: func(apiVer, resource string) *dynamicdiscovery.APIResource {
+			return &dynamicdiscovery.APIResource{
+				APIVersion: "v1",
+				APIResource: metav1.APIResource{
+					Name: "pods",
+					Kind: "Pod",
+				},
+			}
+		},
+	}
+	// pods labelled app=metac match the watches from their namespace
+	sel, err := NewSelectorForAttachments(
+		discoveryMgr,
+		[]v1alpha1.GenericControllerAttachment{
+			{
+				GenericControllerResource: v1alpha1.GenericControllerResource{
+					ResourceRule: v1alpha1.ResourceRule{
+						APIVersion: "v1",
+						Resource:   "pods",
+					},
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"app": "metac",
+						},
+					},
+					AdvancedSelector: &v1alpha1.ResourceSelector{
+						SelectorTerms: []*v1alpha1.SelectorTerm{
+							{
+								MatchReference: []string{"metadata.namespace"},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+
+	var tests = map[string]struct {
+		namespace string
+		ownerUID  string
+		labels    map[string]string
+		expect    []string
+	}{
+		"owned by watch a & selected by watch b": {
+			namespace: "ns-b",
+			ownerUID:  "watch-a",
+			labels:    map[string]string{"app": "metac"},
+			expect:    []string{"watch-a", "watch-b"},
+		},
+		"owned & selected by watch a": {
+			namespace: "ns-a",
+			ownerUID:  "watch-a",
+			labels:    map[string]string{"app": "metac"},
+			expect:    []string{"watch-a"},
+		},
+		"owned by watch a & not selected": {
+			namespace: "ns-b",
+			ownerUID:  "watch-a",
+			expect:    []string{"watch-a"},
+		},
+		"selected by watch c": {
+			namespace: "ns-c",
+			labels:    map[string]string{"app": "metac"},
+			expect:    []string{"watch-c"},
+		},
+		"neither owned nor selected": {
+			namespace: "ns-c",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			attachment := &unstructured.Unstructured{}
+			attachment.SetAPIVersion("v1")
+			attachment.SetKind("Pod")
+			attachment.SetNamespace(mock.namespace)
+			attachment.SetName("pod")
+			attachment.SetLabels(mock.labels)
+			if mock.ownerUID != "" {
+				attachment.SetOwnerReferences([]metav1.OwnerReference{
+					{UID: types.UID(mock.ownerUID)},
+				})
+			}
+			got, err := findRelatedWatches(lister, sel, attachment)
+			if err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if len(got) != len(mock.expect) {
+				t.Fatalf("Expected watches %v got %d watches", mock.expect, len(got))
+			}
+			for i, watch := range got {
+				if watch.GetName() != mock.expect[i] {
+					t.Fatalf("Expected watch %q got %q", mock.expect[i], watch.GetName())
+				}
+			}
+		})
+	}
+}
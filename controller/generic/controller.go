@@ -17,6 +17,7 @@ limitations under the License.
 package generic
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strings"
@@ -24,6 +25,7 @@ import (
 	"time"
 
 	"github.com/golang/glog"
+	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -33,15 +35,22 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
 	"openebs.io/metac/controller/common/finalizer"
+	"openebs.io/metac/debug"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicinformer "openebs.io/metac/dynamic/informer"
 	dynamicobject "openebs.io/metac/dynamic/object"
+	"openebs.io/metac/events"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/hooks/webhook"
+	"openebs.io/metac/metrics"
+	"openebs.io/metac/sharding"
 	k8s "openebs.io/metac/third_party/kubernetes"
 )
 
@@ -86,6 +95,35 @@ type WatchController struct {
 	// instance that deals with this controller's finalizer
 	// if any
 	finalizer *finalizer.Finalizer
+
+	// recorder if set emits events against the watch
+	eventRecorder record.EventRecorder
+
+	// UpdateStatusFn if set is invoked periodically to update
+	// the status of GenericController
+	UpdateStatusFn func(v1alpha1.GenericControllerStatus) error
+
+	// tracks the runtime state of this controller that gets
+	// reflected as the status of GenericController as well as
+	// the keys being reconciled
+	status *statusTracker
+
+	// shard if set restricts this controller to reconcile only
+	// the watches owned by this shard
+	shard *sharding.Shard
+
+	// namespaces that the watches & attachments of this controller
+	// are restricted to; these are not restricted if this is empty
+	namespaces []string
+
+	// DryRun if true computes the changes to the watches &
+	// attachments without applying them. This applies irrespective
+	// of the GenericController's spec.dryRun.
+	DryRun bool
+
+	// HookDispatcher if set resolves the inline hooks from its
+	// registry & wraps the hook invocations with its middlewares
+	HookDispatcher *common.HookDispatcher
 }
 
 // String implements Stringer interface
@@ -102,14 +140,33 @@ func (mgr *WatchController) String() string {
 
 // NewWatchController returns a new instance of watch controller
 // with required watch & child informers, selectors, update
-// strategy & so on.
+// strategy & so on. Events are not emitted if the provided
+// recorder is nil. All the watches are reconciled if the provided
+// shard is nil.
 func NewWatchController(
 	dynDiscovery *dynamicdiscovery.APIResourceDiscovery,
 	dynClientset *dynamicclientset.Clientset,
 	dynInformerFactory *dynamicinformer.SharedInformerFactory,
+	recorder record.EventRecorder,
+	shard *sharding.Shard,
 	config *v1alpha1.GenericController,
 ) (wCtl *WatchController, newErr error) {
 
+	// use a dedicated clientset if this controller declares its
+	// own client rate limits
+	dynClientset, err := common.NewClientsetForRateLimit(
+		dynClientset,
+		config.Spec.ClientRateLimit,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"GenericController %q / %q",
+			config.Namespace,
+			config.Name,
+		)
+	}
+
 	ctl := &WatchController{
 		GCtlConfig:       config,
 		DynamicDiscovery: dynDiscovery,
@@ -121,7 +178,7 @@ func NewWatchController(
 		attachmentInformers: make(common.ResourceInformerRegistrar),
 
 		watchQ: workqueue.NewNamedRateLimitingQueue(
-			workqueue.DefaultControllerRateLimiter(),
+			common.NewWorkqueueRateLimiter(config.Spec.RateLimiter),
 			"WatchGCtl-"+config.Namespace+"-"+config.Name,
 		),
 
@@ -135,10 +192,22 @@ func NewWatchController(
 
 			// Enable if Finalize field is set in the generic controller
 			Enabled: config.Spec.Hooks.Finalize != nil,
+
+			Recorder: recorder,
 		},
+
+		eventRecorder: recorder,
+
+		status: newStatusTracker(),
+		shard:  shard,
 	}
 
-	var err error
+	ctl.namespaces, err = dynInformerFactory.ResolveNamespaces(
+		config.Spec.Namespaces,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", ctl)
+	}
 
 	// build watch & attachment selectors
 	ctl.watchSelector, ctl.attachmentSelector, err = makeAllSelectors(
@@ -193,9 +262,14 @@ func NewWatchController(
 		}
 	}()
 	// init watch informer
-	informer, err := dynInformerFactory.GetOrCreate(
+	//
+	// NOTE:
+	//	Informers are restricted to the namespaces of this config,
+	// if any
+	informer, err := dynInformerFactory.GetOrCreateForNamespaces(
 		config.Spec.Watch.APIVersion,
 		config.Spec.Watch.Resource,
+		config.Spec.Namespaces,
 	)
 	if err != nil {
 		return nil,
@@ -220,9 +294,10 @@ func NewWatchController(
 	)
 	// initialise the informers for attachments
 	for _, a := range config.Spec.Attachments {
-		informer, err := dynInformerFactory.GetOrCreate(
+		informer, err := dynInformerFactory.GetOrCreateForNamespaces(
 			a.APIVersion,
 			a.Resource,
+			config.Spec.Namespaces,
 		)
 		if err != nil {
 			return nil,
@@ -280,6 +355,12 @@ func (mgr *WatchController) Start(workerCount int) {
 			informer.Informer().AddEventHandler(watchHandlers)
 		}
 	}
+	// changes to attachments resync their watches
+	mgr.addAttachmentEventHandlers()
+	workerCount = common.GetWorkerCount(
+		mgr.GCtlConfig.Spec.WorkerCount,
+		workerCount,
+	)
 	if workerCount <= 0 {
 		// set a reasonable worker count value
 		workerCount = 5
@@ -293,6 +374,18 @@ func (mgr *WatchController) Start(workerCount int) {
 		glog.Infof("Starting %s", mgr)
 		defer glog.Infof("Shutting down %s", mgr)
 
+		// update status periodically till this controller is
+		// stopped
+		var statusWG sync.WaitGroup
+		if mgr.UpdateStatusFn != nil {
+			statusWG.Add(1)
+			go func() {
+				defer statusWG.Done()
+				wait.Until(mgr.updateStatus, statusUpdateInterval, mgr.stopCh)
+			}()
+		}
+		defer statusWG.Wait()
+
 		// Wait for dynamic client and all informers.
 		glog.V(7).Infof("Waiting for caches to sync: %s", mgr)
 		syncFuncs := make(
@@ -315,6 +408,13 @@ func (mgr *WatchController) Start(workerCount int) {
 			glog.Warningf("Cache sync never finished: %s", mgr)
 			return
 		}
+		mgr.status.setInformersSynced()
+
+		// watches move across the members of the shard when
+		// these members change
+		removeShardListener := mgr.shard.AddListener(mgr.enqueueAllWatches)
+		defer removeShardListener()
+
 		glog.V(5).Infof("Starting %d workers: %s", workerCount, mgr)
 		var wg sync.WaitGroup
 		for i := 0; i < workerCount; i++ {
@@ -328,6 +428,24 @@ func (mgr *WatchController) Start(workerCount int) {
 	}()
 }
 
+// HasSynced returns true if the watch & attachment informers of
+// this controller have synced
+func (mgr *WatchController) HasSynced() bool {
+	return mgr.status.hasInformersSynced()
+}
+
+// DebugInfo returns the runtime state of this controller
+func (mgr *WatchController) DebugInfo() debug.ControllerInfo {
+	return debug.ControllerInfo{
+		Kind:         "GenericController",
+		Name:         mgr.GCtlConfig.AsNamespaceNameKey(),
+		HasSynced:    mgr.HasSynced(),
+		QueueLength:  mgr.watchQ.Len(),
+		InFlightKeys: mgr.status.inFlightKeys(),
+		SyncErrors:   mgr.status.debugSyncErrors(),
+	}
+}
+
 // Stop will stop this controller
 func (mgr *WatchController) Stop() {
 	// closing stopCh will unblock all the logics where this
@@ -357,6 +475,15 @@ func (mgr *WatchController) Stop() {
 	}
 }
 
+// updateStatus updates the status of GenericController with
+// the state tracked by this controller
+func (mgr *WatchController) updateStatus() {
+	err := mgr.UpdateStatusFn(mgr.status.Status())
+	if err != nil {
+		glog.Warningf("Failed to update status: %s: %v", mgr, err)
+	}
+}
+
 // worker works for ever. Its only work is to process the
 // workitem i.e. the watch
 func (mgr *WatchController) worker() {
@@ -382,8 +509,41 @@ func (mgr *WatchController) processNextWorkItem() bool {
 	}
 	defer mgr.watchQ.Done(key)
 
+	// the watch may have moved to another member of the shard
+	// after it was queued
+	if !mgr.shard.Owns(key.(string)) {
+		glog.V(4).Infof(
+			"Will skip %q: Not owned by %s: %s",
+			key,
+			mgr.shard,
+			mgr,
+		)
+		mgr.watchQ.Forget(key)
+		return true
+	}
+
+	// changes are collected instead of being applied in case
+	// of dry run
+	var dryRun *common.DryRun
+	if mgr.isDryRun() {
+		dryRun = common.NewDryRun()
+	}
+
 	// actual reconcile logic is invoked here
-	err := mgr.syncWatch(key.(string))
+	mgr.status.setSyncStarted(key.(string))
+	start := time.Now()
+	err := mgr.syncWatch(key.(string), dryRun)
+	metrics.RecordSync(
+		"WatchGCtl-"+mgr.GCtlConfig.Namespace+"-"+mgr.GCtlConfig.Name,
+		start,
+		err,
+	)
+	mgr.status.setSyncResult(key.(string), err)
+	if dryRun.IsEnabled() {
+		mgr.status.setDryRunChanges(key.(string), dryRun.Changes())
+	} else {
+		mgr.status.clearDryRunChanges()
+	}
 	if err != nil {
 		utilruntime.HandleError(
 			errors.Wrapf(
@@ -393,6 +553,12 @@ func (mgr *WatchController) processNextWorkItem() bool {
 				mgr,
 			),
 		)
+		if circuitErr, ok := errors.Cause(err).(*webhook.CircuitOpenError); ok {
+			// retry once the circuit allows a trial invocation
+			// instead of adding to the retry storm
+			mgr.watchQ.AddAfter(key, circuitErr.RetryAfter)
+			return true
+		}
 		mgr.watchQ.AddRateLimited(key)
 		return true
 	}
@@ -449,6 +615,15 @@ func (mgr *WatchController) enqueueWatch(obj interface{}) {
 		)
 		return
 	}
+	if !mgr.shard.Owns(key) {
+		glog.V(7).Infof(
+			"Will not enqueue %s: Not owned by %s: %s",
+			key,
+			mgr.shard,
+			mgr,
+		)
+		return
+	}
 	glog.V(7).Infof(
 		"Will enqueue %s: %s",
 		key,
@@ -457,6 +632,24 @@ func (mgr *WatchController) enqueueWatch(obj interface{}) {
 	mgr.watchQ.Add(key)
 }
 
+// enqueueAllWatches enqueues all the watches found in the local
+// cache. Watches not owned by the shard of this controller are
+// skipped.
+func (mgr *WatchController) enqueueAllWatches() {
+	for _, informer := range mgr.watchInformers {
+		watches, err := informer.Lister().List(labels.Everything())
+		if err != nil {
+			utilruntime.HandleError(
+				errors.Wrapf(err, "Can't list watches: %s", mgr),
+			)
+			continue
+		}
+		for _, watch := range watches {
+			mgr.enqueueWatch(watch)
+		}
+	}
+}
+
 func (mgr *WatchController) enqueueWatchAfter(obj interface{}, delay time.Duration) {
 	key, err := makeWatchQueueKey(obj)
 	if err != nil {
@@ -470,6 +663,9 @@ func (mgr *WatchController) enqueueWatchAfter(obj interface{}, delay time.Durati
 		)
 		return
 	}
+	if !mgr.shard.Owns(key) {
+		return
+	}
 	mgr.watchQ.AddAfter(key, delay)
 }
 
@@ -487,7 +683,7 @@ func (mgr *WatchController) updateWatch(old, cur interface{}) {
 //
 // TODO (@amitkumardas):
 // - Unit Tests
-func (mgr *WatchController) syncWatch(key string) error {
+func (mgr *WatchController) syncWatch(key string, dryRun *common.DryRun) error {
 	var err error
 	defer func() {
 		if err != nil {
@@ -545,7 +741,7 @@ func (mgr *WatchController) syncWatch(key string) error {
 	// remember we use a defer statement to intercept error as
 	// warning log.
 	// Hence, we dont return below invocation directly.
-	err = mgr.syncWatchObj(watchObj)
+	err = mgr.syncWatchObj(watchObj, dryRun)
 	return err
 }
 
@@ -555,7 +751,10 @@ func (mgr *WatchController) syncWatch(key string) error {
 //
 // TODO (@amitkumardas):
 // - Unit Tests
-func (mgr *WatchController) syncWatchObj(watch *unstructured.Unstructured) error {
+func (mgr *WatchController) syncWatchObj(
+	watch *unstructured.Unstructured,
+	dryRun *common.DryRun,
+) error {
 	// if watch doesn't match the configured selector, and doesn't have
 	// our finalizer, then **ignore it**.
 	isMatch, err := mgr.watchSelector.MatchLAN(watch)
@@ -604,14 +803,19 @@ func (mgr *WatchController) syncWatchObj(watch *unstructured.Unstructured) error
 
 	// Add or Remove our finalizer **if desired**.
 	// This ensures we have a chance to clean up after any action we later take.
-	watchCopy, err := mgr.finalizer.SyncObject(watchClient, watch)
-	if err != nil {
-		return errors.Wrapf(
-			err,
-			"Can't sync finalizer for watch %s: %s",
-			common.DescObjectAsKey(watch),
-			mgr,
-		)
+	var watchCopy *unstructured.Unstructured
+	if dryRun.IsEnabled() {
+		watchCopy = mgr.dryRunSyncFinalizer(watch, dryRun)
+	} else {
+		watchCopy, err = mgr.finalizer.SyncObject(watchClient, watch)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"Can't sync finalizer for watch %s: %s",
+				common.DescObjectAsKey(watch),
+				mgr,
+			)
+		}
 	}
 	watch = watchCopy
 
@@ -675,6 +879,35 @@ func (mgr *WatchController) syncWatchObj(watch *unstructured.Unstructured) error
 		)
 	}
 
+	// validate the response before applying any of it
+	//
+	// NOTE:
+	//	This reports all the mistakes of the hook at once instead of
+	// failing midway while attachments are being applied
+	validator := newResponseValidator(
+		mgr.DynamicDiscovery,
+		watch,
+		mgr.GCtlConfig.Spec.Attachments,
+		mgr.namespaces,
+	)
+	err = validator.Validate(syncResponse)
+	if err != nil {
+		events.Warningf(
+			mgr.eventRecorder,
+			watch,
+			events.ReasonInvalidHookResponse,
+			"%s: %v",
+			mgr,
+			err,
+		)
+		return errors.Wrapf(
+			err,
+			"Can't apply hook response for watch %s: %s",
+			common.DescObjectAsKey(watch),
+			mgr,
+		)
+	}
+
 	// build various attachments _(received from the sync hook call)_
 	// in a registry format
 	desiredAttachments := common.MakeAnyUnstructRegistry(
@@ -755,67 +988,31 @@ func (mgr *WatchController) syncWatchObj(watch *unstructured.Unstructured) error
 			syncResponse.Status,
 			"status",
 		)
-		// check if watch resource has a subresource
-		hasSubResourceStatus := watchClient.HasSubresource("status")
-		glog.V(7).Infof(
-			"Watch %s has status as subresource=%t: %s",
-			common.DescObjectAsKey(watch),
-			hasSubResourceStatus,
-			mgr,
-		)
-		if statusChanged && hasSubResourceStatus {
-			// NOTE:
-			// 	regular update below will **ignore** changes to **.status**
-			// so we do it separately
-			result, err :=
-				watchClient.
-					Namespace(watch.GetNamespace()).
-					UpdateStatus(
-						watchCopy,
-						metav1.UpdateOptions{},
-					)
-			if err != nil {
-				return errors.Wrapf(
-					err,
-					"Failed to update status for watch %s: %s",
-					common.DescObjectAsKey(watch),
-					mgr,
-				)
+		if dryRun.IsEnabled() {
+			if syncResponse.Finalized {
+				mgr.finalizer.RemoveFinalizer(watchCopy)
 			}
-			// to proceed with next update due to metadata related changes
-			// it needs to use the latest ResourceVersion from this status
-			// update
-			watchCopy.SetResourceVersion(result.GetResourceVersion())
-		}
-		// check if its time to remove its finalizer
-		if syncResponse.Finalized {
-			mgr.finalizer.RemoveFinalizer(watchCopy)
-		}
-		glog.V(7).Infof(
-			"Updating watch %s: %s",
-			common.DescObjectAsKey(watch),
-			mgr,
-		)
-		// this update is meant to work for updating metadata
-		_, err = watchClient.
-			Namespace(watch.GetNamespace()).
-			Update(
-				watchCopy,
-				metav1.UpdateOptions{},
+			mgr.recordDryRun(
+				dryRun,
+				metrics.OperationUpdate,
+				watch,
+				cmp.Diff(
+					watch.UnstructuredContent(),
+					watchCopy.UnstructuredContent(),
+				),
 			)
-		if err != nil {
-			return errors.Wrapf(
-				err,
-				"Failed to update watch %s: %s",
-				common.DescObjectAsKey(watch),
-				mgr,
+		} else {
+			err = mgr.updateWatchObj(
+				watchClient,
+				watch,
+				watchCopy,
+				statusChanged,
+				syncResponse.Finalized,
 			)
+			if err != nil {
+				return err
+			}
 		}
-		glog.V(7).Infof(
-			"Updated watch %s: %s",
-			common.DescObjectAsKey(watch),
-			mgr,
-		)
 	}
 	// Check if desired attachments should be reconciled? There will
 	// be cases when we do not want to reconcile the attachments.
@@ -898,6 +1095,8 @@ func (mgr *WatchController) syncWatchObj(watch *unstructured.Unstructured) error
 			// processed by finalize hook. In other words, this is set
 			// to true during finalize hook invocation.
 			UpdateDuringPendingDelete: k8s.BoolPtr(syncRequest.Finalizing),
+			EventRecorder:             mgr.eventRecorder,
+			DryRun:                    dryRun,
 		},
 		DynamicClientSet: mgr.DynamicClientSet,
 		Observed:         observedAttachments,
@@ -908,6 +1107,142 @@ func (mgr *WatchController) syncWatchObj(watch *unstructured.Unstructured) error
 	return clusterStatesCtrl.Apply()
 }
 
+// isDryRun returns true if changes should be computed without
+// applying them
+func (mgr *WatchController) isDryRun() bool {
+	if mgr.DryRun {
+		return true
+	}
+	return mgr.GCtlConfig.Spec.DryRun != nil && *mgr.GCtlConfig.Spec.DryRun
+}
+
+// recordDryRun remembers the provided operation against the watch
+// that was not executed due to dry run & emits an event against
+// this watch
+func (mgr *WatchController) recordDryRun(
+	dryRun *common.DryRun,
+	operation string,
+	watch *unstructured.Unstructured,
+	diff string,
+) {
+	dryRun.Record(operation, watch, diff)
+	events.Normalf(
+		mgr.eventRecorder,
+		watch,
+		events.ReasonDryRun,
+		"Skipped %s of %s",
+		operation,
+		common.DescObjectAsKey(watch),
+	)
+}
+
+// dryRunSyncFinalizer adds or removes this controller's finalizer
+// against a copy of the provided watch & records this change. The
+// provided watch is returned if its finalizer is in sync.
+func (mgr *WatchController) dryRunSyncFinalizer(
+	watch *unstructured.Unstructured,
+	dryRun *common.DryRun,
+) *unstructured.Unstructured {
+	if dynamicobject.HasFinalizer(watch, mgr.finalizer.Name) == mgr.finalizer.Enabled {
+		return watch
+	}
+	if mgr.finalizer.Enabled && watch.GetDeletionTimestamp() != nil {
+		// finalizer is not added to a watch pending deletion
+		return watch
+	}
+	watchCopy := watch.DeepCopy()
+	if mgr.finalizer.Enabled {
+		dynamicobject.AddFinalizer(watchCopy, mgr.finalizer.Name)
+	} else {
+		dynamicobject.RemoveFinalizer(watchCopy, mgr.finalizer.Name)
+	}
+	mgr.recordDryRun(
+		dryRun,
+		metrics.OperationUpdate,
+		watch,
+		cmp.Diff(
+			watch.UnstructuredContent(),
+			watchCopy.UnstructuredContent(),
+		),
+	)
+	return watchCopy
+}
+
+// updateWatchObj updates the provided copy of the watch at the
+// cluster. Status is updated separately if the watch resource has
+// a status subresource. The finalizer is removed from the watch
+// if the watch is finalized.
+func (mgr *WatchController) updateWatchObj(
+	watchClient *dynamicclientset.ResourceClient,
+	watch *unstructured.Unstructured,
+	watchCopy *unstructured.Unstructured,
+	statusChanged bool,
+	finalized bool,
+) error {
+	// check if watch resource has a subresource
+	hasSubResourceStatus := watchClient.HasSubresource("status")
+	glog.V(7).Infof(
+		"Watch %s has status as subresource=%t: %s",
+		common.DescObjectAsKey(watch),
+		hasSubResourceStatus,
+		mgr,
+	)
+	if statusChanged && hasSubResourceStatus {
+		// NOTE:
+		// 	regular update below will **ignore** changes to **.status**
+		// so we do it separately
+		result, err :=
+			watchClient.
+				Namespace(watch.GetNamespace()).
+				UpdateStatus(
+					watchCopy,
+					metav1.UpdateOptions{},
+				)
+		if err != nil {
+			return errors.Wrapf(
+				err,
+				"Failed to update status for watch %s: %s",
+				common.DescObjectAsKey(watch),
+				mgr,
+			)
+		}
+		// to proceed with next update due to metadata related changes
+		// it needs to use the latest ResourceVersion from this status
+		// update
+		watchCopy.SetResourceVersion(result.GetResourceVersion())
+	}
+	// check if its time to remove its finalizer
+	if finalized {
+		mgr.finalizer.RemoveFinalizer(watchCopy)
+	}
+	glog.V(7).Infof(
+		"Updating watch %s: %s",
+		common.DescObjectAsKey(watch),
+		mgr,
+	)
+	// this update is meant to work for updating metadata
+	_, err := watchClient.
+		Namespace(watch.GetNamespace()).
+		Update(
+			watchCopy,
+			metav1.UpdateOptions{},
+		)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Failed to update watch %s: %s",
+			common.DescObjectAsKey(watch),
+			mgr,
+		)
+	}
+	glog.V(7).Infof(
+		"Updated watch %s: %s",
+		common.DescObjectAsKey(watch),
+		mgr,
+	)
+	return nil
+}
+
 // isReconcileAttachments returns true if controller should
 // reconcile attachments. It returns true if either of the
 // following conditions succeed:
@@ -939,6 +1274,15 @@ func (mgr *WatchController) isReconcileAttachments(
 	return false
 }
 
+// hookContext returns the context to invoke the hook of the
+// given type
+func (mgr *WatchController) hookContext(hook string) context.Context {
+	return hooks.WithInfo(context.Background(), hooks.Info{
+		Controller: mgr.String(),
+		Hook:       hook,
+	})
+}
+
 // getObservedAttachments returns the attachments as declared
 // in GenericController resource
 //
@@ -1086,10 +1430,21 @@ func (mgr *WatchController) callSyncHook(
 		// set finalizing to true since this is finalize hook invocation
 		request.Finalizing = true
 		hi := &HookInvoker{
-			Schema: mgr.GCtlConfig.Spec.Hooks.Finalize,
+			Schema:     mgr.GCtlConfig.Spec.Hooks.Finalize,
+			Dispatcher: mgr.HookDispatcher,
 		}
-		err := hi.Invoke(request, &response)
+		err := hi.InvokeContext(mgr.hookContext(hooks.FinalizeHook), request, &response)
+		mgr.status.setHookCircuitOpen(webhook.IsCircuitOpenError(err))
 		if err != nil {
+			mgr.status.incHookFailureCount()
+			events.Warningf(
+				mgr.eventRecorder,
+				request.Watch,
+				events.ReasonHookFailed,
+				"Finalize hook failed: %s: %v",
+				mgr,
+				err,
+			)
 			return nil, errors.Wrapf(err, "Finalize hook failed")
 		}
 		glog.V(7).Infof(
@@ -1115,10 +1470,21 @@ func (mgr *WatchController) callSyncHook(
 		// set finalizing to false since this is sync hook invocation
 		request.Finalizing = false
 		hi := &HookInvoker{
-			Schema: mgr.GCtlConfig.Spec.Hooks.Sync,
+			Schema:     mgr.GCtlConfig.Spec.Hooks.Sync,
+			Dispatcher: mgr.HookDispatcher,
 		}
-		err := hi.Invoke(request, &response)
+		err := hi.InvokeContext(mgr.hookContext(hooks.SyncHook), request, &response)
+		mgr.status.setHookCircuitOpen(webhook.IsCircuitOpenError(err))
 		if err != nil {
+			mgr.status.incHookFailureCount()
+			events.Warningf(
+				mgr.eventRecorder,
+				request.Watch,
+				events.ReasonHookFailed,
+				"Sync hook failed: %s: %v",
+				mgr,
+				err,
+			)
 			return nil, errors.Wrapf(err, "Sync hook failed")
 		}
 		glog.V(7).Infof(
@@ -17,10 +17,14 @@ limitations under the License.
 package generic
 
 import (
+	"context"
+
+	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks/grpchook"
 )
 
 // SyncHookRequest is the object sent as JSON to the sync hook.
@@ -44,6 +48,23 @@ type SyncHookRequest struct {
 	Finalizing bool `json:"finalizing"`
 }
 
+// GRPCMethod returns the method of the gRPC hook that gets
+// invoked for this request
+func (r *SyncHookRequest) GRPCMethod() string {
+	if r.Finalizing {
+		return grpchook.GenericFinalizeMethod
+	}
+	return grpchook.GenericSyncMethod
+}
+
+// Validate implements hooks.Validator interface
+func (r *SyncHookRequest) Validate() error {
+	if r.Watch == nil {
+		return errors.Errorf("Nil watch")
+	}
+	return nil
+}
+
 // SyncHookResponse is the expected format of the JSON response
 // from the sync hook.
 type SyncHookResponse struct {
@@ -105,20 +126,38 @@ type SyncHookResponse struct {
 // hook invocation that is supported by generic controller
 type HookInvoker struct {
 	Schema *v1alpha1.Hook
+
+	// Dispatcher if set resolves the inline hooks from its registry
+	// & wraps the invocation with its middlewares
+	Dispatcher *common.HookDispatcher
 }
 
 // Invoke invokes the hook based on the given request & fills the
 // response post successful invocation
 func (i *HookInvoker) Invoke(req *SyncHookRequest, resp *SyncHookResponse) error {
-	// if inline call then set appropriate call func
-	if i.Schema.Inline != nil && i.Schema.Inline.FuncName != nil {
-		// create a new instance of generic controller based inline hook invoker
-		ihi, err := NewInlineHookInvoker(*i.Schema.Inline.FuncName)
-		if err != nil {
-			return err
-		}
-		return ihi.Invoke(req, resp)
-	}
-	// this is one of the commonly supported hooks
-	return common.InvokeHook(i.Schema, req, resp)
+	return i.InvokeContext(context.Background(), req, resp)
+}
+
+// InvokeContext invokes the hook with the given context based on
+// the given request & fills the response post successful invocation
+func (i *HookInvoker) InvokeContext(
+	ctx context.Context,
+	req *SyncHookRequest,
+	resp *SyncHookResponse,
+) error {
+	return i.Dispatcher.Dispatch(
+		ctx,
+		i.Schema,
+		req,
+		resp,
+		func(_ context.Context, _, _ interface{}) error {
+			// create a new instance of generic controller based inline
+			// hook invoker
+			ihi, err := NewInlineHookInvoker(*i.Schema.Inline.FuncName)
+			if err != nil {
+				return err
+			}
+			return ihi.Invoke(req, resp)
+		},
+	)
 }
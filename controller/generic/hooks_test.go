@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/hooks"
+)
+
+func TestHookInvokerInvokeStarlark(t *testing.T) {
+	source := `
+def sync(request):
+    return {"labels": {"phase": "synced"}}
+
+def finalize(request):
+    return {
+        "labels": {"phase": request["watch"]["metadata"]["name"]},
+        "finalized": True,
+    }
+`
+	invoker := &HookInvoker{
+		Schema: &v1alpha1.Hook{
+			Starlark: &v1alpha1.Starlark{
+				Source: &source,
+			},
+		},
+	}
+
+	var tests = map[string]struct {
+		finalizing      bool
+		expectPhase     string
+		expectFinalized bool
+	}{
+		"sync": {
+			expectPhase: "synced",
+		},
+		"finalize": {
+			finalizing:      true,
+			expectPhase:     "test",
+			expectFinalized: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			watch := &unstructured.Unstructured{}
+			watch.SetName("test")
+			req := &SyncHookRequest{
+				Watch:      watch,
+				Finalizing: mock.finalizing,
+			}
+			resp := &SyncHookResponse{}
+			err := invoker.Invoke(req, resp)
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			phase := resp.Labels["phase"]
+			if phase == nil || *phase != mock.expectPhase {
+				t.Fatalf("Expected phase %q got %v", mock.expectPhase, phase)
+			}
+			if resp.Finalized != mock.expectFinalized {
+				t.Fatalf(
+					"Expected finalized %t got %t",
+					mock.expectFinalized,
+					resp.Finalized,
+				)
+			}
+		})
+	}
+}
+
+func TestHookInvokerInvokeContextInline(t *testing.T) {
+	registry := hooks.NewRegistry()
+	err := registry.Register(
+		"sync/test-generic-v2",
+		InlineHandler(func(
+			ctx context.Context,
+			req *SyncHookRequest,
+			resp *SyncHookResponse,
+		) error {
+			phase := hooks.InfoFrom(ctx).Controller
+			resp.Labels = map[string]*string{"phase": &phase}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	err = registry.Register(
+		"sync/test-generic-panic",
+		func(_ context.Context, _, _ interface{}) error {
+			panic("boom")
+		},
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	AddToInlineRegistry(
+		"sync/test-generic-v1",
+		func(req *SyncHookRequest, resp *SyncHookResponse) error {
+			phase := "v1"
+			resp.Labels = map[string]*string{"phase": &phase}
+			return nil
+		},
+	)
+	dispatcher := &common.HookDispatcher{
+		InlineHooks: registry,
+		Middlewares: hooks.DefaultMiddlewares(),
+	}
+	ctx := hooks.WithInfo(context.Background(), hooks.Info{
+		Controller: "test-ctl",
+		Hook:       hooks.SyncHook,
+	})
+
+	var tests = map[string]struct {
+		funcName    string
+		watch       *unstructured.Unstructured
+		expectPhase string
+		isErr       bool
+	}{
+		"v2 inline hook": {
+			funcName:    "sync/test-generic-v2",
+			watch:       &unstructured.Unstructured{},
+			expectPhase: "test-ctl",
+		},
+		"v1 inline hook": {
+			funcName:    "sync/test-generic-v1",
+			watch:       &unstructured.Unstructured{},
+			expectPhase: "v1",
+		},
+		"panic is recovered": {
+			funcName: "sync/test-generic-panic",
+			watch:    &unstructured.Unstructured{},
+			isErr:    true,
+		},
+		"invalid request": {
+			funcName: "sync/test-generic-v2",
+			isErr:    true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			funcName := mock.funcName
+			invoker := &HookInvoker{
+				Schema: &v1alpha1.Hook{
+					Inline: &v1alpha1.Inline{FuncName: &funcName},
+				},
+				Dispatcher: dispatcher,
+			}
+			resp := &SyncHookResponse{}
+			err := invoker.InvokeContext(
+				ctx,
+				&SyncHookRequest{Watch: mock.watch},
+				resp,
+			)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			phase := resp.Labels["phase"]
+			if phase == nil || *phase != mock.expectPhase {
+				t.Fatalf("Expected phase %q got %v", mock.expectPhase, phase)
+			}
+		})
+	}
+}
+
+func TestInlineHandlerInvalidTypes(t *testing.T) {
+	handler := InlineHandler(func(
+		_ context.Context,
+		_ *SyncHookRequest,
+		_ *SyncHookResponse,
+	) error {
+		return nil
+	})
+	err := handler(context.Background(), map[string]string{}, nil)
+	if err == nil {
+		t.Fatalf("Expected error got none")
+	}
+}
@@ -17,14 +17,45 @@ limitations under the License.
 package generic
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
+
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/metrics"
 )
 
 // InlineInvokeFn is the signature for all inline hook invocation functions
 type InlineInvokeFn func(req *SyncHookRequest, resp *SyncHookResponse) error
 
+// InlineHookFunc is the context aware signature of inline hook
+// functions. These are registered with a hooks.Registry via
+// InlineHandler.
+type InlineHookFunc func(
+	ctx context.Context,
+	req *SyncHookRequest,
+	resp *SyncHookResponse,
+) error
+
+// InlineHandler returns the given inline hook function as a
+// handler that can be registered with a hooks.Registry
+func InlineHandler(fn InlineHookFunc) hooks.Handler {
+	return func(ctx context.Context, request, response interface{}) error {
+		req, isReq := request.(*SyncHookRequest)
+		resp, isResp := response.(*SyncHookResponse)
+		if !isReq || !isResp {
+			return errors.Errorf(
+				"Invalid generic controller inline hook: Got request %T & response %T",
+				request,
+				response,
+			)
+		}
+		return fn(ctx, req, resp)
+	}
+}
+
 type inlineHookRegistry struct {
 	sync.Mutex
 	invokeFuncs map[string]InlineInvokeFn
@@ -65,5 +96,8 @@ func (i *InlineHookInvoker) Invoke(req *SyncHookRequest, resp *SyncHookResponse)
 			"Inline hook function not found for %s", i.FuncName,
 		)
 	}
-	return fn(req, resp)
+	start := time.Now()
+	err := fn(req, resp)
+	metrics.RecordHook(i.FuncName, start, err)
+	return err
 }
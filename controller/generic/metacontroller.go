@@ -18,6 +18,7 @@ package generic
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -28,17 +29,24 @@ import (
 	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	metaclientset "openebs.io/metac/client/generated/clientset/versioned"
 	metainformers "openebs.io/metac/client/generated/informers/externalversions"
 	metalisters "openebs.io/metac/client/generated/listers/metacontroller/v1alpha1"
 	"openebs.io/metac/config"
 	"openebs.io/metac/controller/common"
+	"openebs.io/metac/debug"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicinformer "openebs.io/metac/dynamic/informer"
+	"openebs.io/metac/events"
+	"openebs.io/metac/health"
+	"openebs.io/metac/sharding"
 	k8s "openebs.io/metac/third_party/kubernetes"
 )
 
@@ -57,9 +65,86 @@ type BaseMetaController struct {
 	WatchControllers map[string]*WatchController
 	WorkerCount      int
 
+	// EventRecorder if set emits events against the watches
+	EventRecorder record.EventRecorder
+
+	// Shard if set restricts the watch controllers to reconcile
+	// only the watches owned by this shard
+	Shard *sharding.Shard
+
+	// DryRun if true lets the watch controllers compute the
+	// changes without applying them
+	DryRun bool
+
+	// HookDispatcher if set resolves the inline hooks of the watch
+	// controllers & wraps their hook invocations with middlewares
+	HookDispatcher *common.HookDispatcher
+
+	// guards WatchControllers against concurrent readers e.g.
+	// readiness checks
+	mutex sync.RWMutex
+
 	doneCh chan struct{}
 }
 
+// setWatchController adds the watch controller anchored by the
+// given key
+func (mc *BaseMetaController) setWatchController(key string, wc *WatchController) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	if mc.WatchControllers == nil {
+		mc.WatchControllers = make(map[string]*WatchController)
+	}
+	mc.WatchControllers[key] = wc
+}
+
+// deleteWatchController removes the watch controller anchored by
+// the given key
+func (mc *BaseMetaController) deleteWatchController(key string) {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+
+	delete(mc.WatchControllers, key)
+}
+
+// watchControllerKeys returns the sorted keys of the running
+// watch controllers
+//
+// NOTE:
+//	This must be invoked with the lock held
+func (mc *BaseMetaController) watchControllerKeys() []string {
+	var keys []string
+	for key := range mc.WatchControllers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// DebugInfo returns the runtime state of the running watch
+// controllers
+func (mc *BaseMetaController) DebugInfo() []debug.ControllerInfo {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+
+	var infos []debug.ControllerInfo
+	for _, key := range mc.watchControllerKeys() {
+		infos = append(infos, mc.WatchControllers[key].DebugInfo())
+	}
+	return infos
+}
+
+// newWatchControllerCheck returns the readiness check of the
+// provided watch controller
+func newWatchControllerCheck(key string, wc *WatchController) health.Check {
+	return health.NewCheck(
+		"GenericController "+key,
+		wc.HasSynced,
+		"Informers have not synced",
+	)
+}
+
 // ConfigMetaController represents a MetaController that
 // is based on config files that. This config schema is based
 // on GenericController api. Configs are provided to this binary
@@ -94,8 +179,14 @@ type ConfigMetaController struct {
 	// Interval between retries to start all watch controllers
 	WaitIntervalBetweenRestarts time.Duration
 
-	opts []ConfigMetaControllerOption
-	err  error
+	// Interval at which the configs are reloaded. Watch controllers
+	// are started, stopped or restarted based on the added, removed
+	// or changed configs. Configs are not reloaded if this is zero.
+	ReloadInterval time.Duration
+
+	opts   []ConfigMetaControllerOption
+	err    error
+	stopCh chan struct{}
 }
 
 // ConfigMetaControllerOption is a functional option to
@@ -143,6 +234,56 @@ func SetMetacConfigPath(path string) ConfigMetaControllerOption {
 	}
 }
 
+// SetMetacConfigEventRecorder sets the recorder to emit events
+// against the watches
+func SetMetacConfigEventRecorder(
+	recorder record.EventRecorder,
+) ConfigMetaControllerOption {
+	return func(c *ConfigMetaController) error {
+		c.EventRecorder = recorder
+		return nil
+	}
+}
+
+// SetMetacConfigShard sets the shard that restricts the watch
+// controllers to reconcile only the watches owned by this shard
+func SetMetacConfigShard(shard *sharding.Shard) ConfigMetaControllerOption {
+	return func(c *ConfigMetaController) error {
+		c.Shard = shard
+		return nil
+	}
+}
+
+// SetMetacConfigDryRun sets the watch controllers to compute the
+// changes without applying them
+func SetMetacConfigDryRun(dryRun bool) ConfigMetaControllerOption {
+	return func(c *ConfigMetaController) error {
+		c.DryRun = dryRun
+		return nil
+	}
+}
+
+// SetMetacConfigHookDispatcher sets the dispatcher that resolves
+// the inline hooks of the watch controllers & wraps their hook
+// invocations with middlewares
+func SetMetacConfigHookDispatcher(
+	dispatcher *common.HookDispatcher,
+) ConfigMetaControllerOption {
+	return func(c *ConfigMetaController) error {
+		c.HookDispatcher = dispatcher
+		return nil
+	}
+}
+
+// SetMetacConfigReloadInterval sets the interval at which the
+// configs are reloaded
+func SetMetacConfigReloadInterval(interval time.Duration) ConfigMetaControllerOption {
+	return func(c *ConfigMetaController) error {
+		c.ReloadInterval = interval
+		return nil
+	}
+}
+
 // NewConfigMetaController returns a new instance of ConfigMetaController
 func NewConfigMetaController(
 	resourceMgr *dynamicdiscovery.APIResourceDiscovery,
@@ -206,11 +347,16 @@ func (mc *ConfigMetaController) loadConfigs() {
 	// NOTE:
 	// 	ConfigPath has **higher priority** to load GenericController
 	// instance(s) as config(s) to run Metac
+	mc.Configs, mc.err = mc.loadConfigsByPathOrFn()
+}
+
+// loadConfigsByPathOrFn loads the configs either from the config
+// path or via the config load function
+func (mc *ConfigMetaController) loadConfigsByPathOrFn() ([]*v1alpha1.GenericController, error) {
 	if mc.ConfigPath != "" {
-		mc.Configs, mc.err = mc.loadConfigsByPath()
-	} else {
-		mc.Configs, mc.err = mc.ConfigLoadFn()
+		return mc.loadConfigsByPath()
 	}
+	return mc.ConfigLoadFn()
 }
 
 func (mc *ConfigMetaController) loadConfigsByPath() ([]*v1alpha1.GenericController, error) {
@@ -224,27 +370,36 @@ func (mc *ConfigMetaController) loadConfigsByPath() ([]*v1alpha1.GenericControll
 // isDuplicateConfig returns error if any duplicate config
 // is found
 func (mc *ConfigMetaController) isDuplicateConfig() {
+	mc.err = mc.verifyNoDuplicateConfigs(mc.Configs)
+}
+
+// verifyNoDuplicateConfigs returns error if any duplicate config
+// is found in the provided configs
+func (mc *ConfigMetaController) verifyNoDuplicateConfigs(
+	configs []*v1alpha1.GenericController,
+) error {
 	var allconfigs = map[string]bool{}
-	for _, conf := range mc.Configs {
+	for _, conf := range configs {
 		key := conf.AsNamespaceNameKey()
 		if allconfigs[key] {
-			mc.err = errors.Errorf(
+			return errors.Errorf(
 				"Duplicate %s was found: %s",
 				key,
 				mc,
 			)
-			return
 		}
 		// add it to check for possible duplicates in
 		// next iterations
 		allconfigs[key] = true
 	}
+	return nil
 }
 
 // Start generic meta controller by starting watch controllers
 // corresponding to the provided config
 func (mc *ConfigMetaController) Start() {
 	mc.doneCh = make(chan struct{})
+	mc.stopCh = make(chan struct{})
 
 	go func() {
 		defer close(mc.doneCh)
@@ -259,9 +414,163 @@ func (mc *ConfigMetaController) Start() {
 		if err != nil {
 			glog.Fatalf("Failed to start %s: %+v", mc, err)
 		}
+
+		if mc.ReloadInterval <= 0 {
+			return
+		}
+		glog.Infof(
+			"Will reload configs every %s: %s",
+			mc.ReloadInterval,
+			mc,
+		)
+		// reload the configs till this controller is stopped
+		wait.Until(mc.reloadConfigs, mc.ReloadInterval, mc.stopCh)
 	}()
 }
 
+// reloadConfigs loads the configs again & starts, stops or
+// restarts the watch controllers corresponding to the added,
+// removed or changed configs
+//
+// NOTE:
+//	Running watch controllers are left untouched if the configs
+// can't be loaded or are invalid. A changed config that fails to
+// initialise does not stop its watch controller that is already
+// running.
+//
+// NOTE:
+//	Config path without any config file is considered an error.
+// This prevents stopping all the watch controllers when a mounted
+// ConfigMap is being updated.
+func (mc *ConfigMetaController) reloadConfigs() {
+	configs, err := mc.loadConfigsByPathOrFn()
+	if err == nil {
+		err = mc.verifyNoDuplicateConfigs(configs)
+	}
+	if err != nil {
+		glog.Errorf(
+			"Failed to reload configs: Will retain running controllers: %s: %+v",
+			mc,
+			err,
+		)
+		return
+	}
+	running := map[string]*v1alpha1.GenericController{}
+	for key, wc := range mc.WatchControllers {
+		running[key] = wc.GCtlConfig
+	}
+	added, changed, removed := diffConfigs(running, configs)
+
+	for _, key := range removed {
+		glog.Infof("Will stop gctl %s: Config was removed: %s", key, mc)
+		mc.WatchControllers[key].Stop()
+		mc.deleteWatchController(key)
+	}
+	var errs []string
+	for _, conf := range append(added, changed...) {
+		key := conf.AsNamespaceNameKey()
+		wc, err := NewWatchController(
+			mc.ResourceManager,
+			mc.DynClientset,
+			mc.DynInformerFactory,
+			mc.EventRecorder,
+			mc.Shard,
+			conf,
+		)
+		if err != nil {
+			errs = append(
+				errs,
+				fmt.Sprintf(
+					"Failed to init gctl %s: %s",
+					key,
+					err.Error(),
+				),
+			)
+			events.Warningf(
+				mc.EventRecorder,
+				conf,
+				events.ReasonConfigFailed,
+				"Failed to start controller: Will retain running controller if any: %v",
+				err,
+			)
+			// continue with the remaining configs & retain the
+			// running controller of this config if any
+			continue
+		}
+		if old, found := mc.WatchControllers[key]; found {
+			glog.Infof("Will restart gctl %s: Config was changed: %s", key, mc)
+			old.Stop()
+		} else {
+			glog.Infof("Will start gctl %s: Config was added: %s", key, mc)
+		}
+		wc.DryRun = mc.DryRun
+		wc.HookDispatcher = mc.HookDispatcher
+		wc.Start(mc.WorkerCount)
+		mc.setWatchController(key, wc)
+	}
+	mc.mutex.Lock()
+	mc.Configs = configs
+	mc.mutex.Unlock()
+	if len(errs) != 0 {
+		glog.Errorf(
+			"Failed to reload %d config(s): %s: %s",
+			len(errs),
+			strings.Join(errs, ": "),
+			mc,
+		)
+	}
+}
+
+// diffConfigs compares the running configs anchored by their
+// keys against the desired configs. It returns the desired configs
+// that are not running, the desired configs whose specs differ from
+// their running ones & the keys of running configs that are no
+// longer desired.
+func diffConfigs(
+	running map[string]*v1alpha1.GenericController,
+	desired []*v1alpha1.GenericController,
+) (added, changed []*v1alpha1.GenericController, removed []string) {
+	desiredKeys := map[string]bool{}
+	for _, conf := range desired {
+		key := conf.AsNamespaceNameKey()
+		desiredKeys[key] = true
+		old, found := running[key]
+		if !found {
+			added = append(added, conf)
+			continue
+		}
+		if !apiequality.Semantic.DeepEqual(old.Spec, conf.Spec) {
+			changed = append(changed, conf)
+		}
+	}
+	for key := range running {
+		if !desiredKeys[key] {
+			removed = append(removed, key)
+		}
+	}
+	// sort to act in a deterministic order
+	sort.Strings(removed)
+	return added, changed, removed
+}
+
+// ReadinessChecks returns the readiness checks of the running
+// watch controllers
+//
+// NOTE:
+//	A config whose watch controller failed to start is not part
+// of these checks. Such failures are reported as events against
+// the config instead.
+func (mc *ConfigMetaController) ReadinessChecks() []health.Check {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+
+	var checks []health.Check
+	for _, key := range mc.watchControllerKeys() {
+		checks = append(checks, newWatchControllerCheck(key, mc.WatchControllers[key]))
+	}
+	return checks
+}
+
 // startWithRetries polls the condition until it's true, with
 // a configured interval and timeout.
 //
@@ -340,6 +649,8 @@ func (mc *ConfigMetaController) startAllWatchControllers() (bool, error) {
 			mc.ResourceManager,
 			mc.DynClientset,
 			mc.DynInformerFactory,
+			mc.EventRecorder,
+			mc.Shard,
 			conf,
 		)
 		if err != nil {
@@ -361,8 +672,10 @@ func (mc *ConfigMetaController) startAllWatchControllers() (bool, error) {
 			continue
 		}
 		// start this controller
+		wc.DryRun = mc.DryRun
+		wc.HookDispatcher = mc.HookDispatcher
 		wc.Start(mc.WorkerCount)
-		mc.WatchControllers[key] = wc
+		mc.setWatchController(key, wc)
 	}
 	if len(errs) != 0 {
 		return false, errors.Errorf(
@@ -381,6 +694,7 @@ func (mc *ConfigMetaController) Stop() {
 
 	// Stop metacontroller first so there's no more changes
 	// to watch controllers.
+	close(mc.stopCh)
 	<-mc.doneCh
 
 	// Stop all its watch controllers
@@ -402,6 +716,9 @@ func (mc *ConfigMetaController) Stop() {
 type CRDMetaController struct {
 	BaseMetaController
 
+	// To update status of GenericController CRs
+	Clientset metaclientset.Interface
+
 	// To list GenericController CRs
 	Lister metalisters.GenericControllerLister
 
@@ -421,6 +738,9 @@ func NewCRDMetaController(
 	dynClientset *dynamicclientset.Clientset,
 	dynInformerFactory *dynamicinformer.SharedInformerFactory,
 	metaInformerFactory metainformers.SharedInformerFactory,
+	metaClientset metaclientset.Interface,
+	recorder record.EventRecorder,
+	shard *sharding.Shard,
 	workerCount int,
 ) *CRDMetaController {
 	// initialize
@@ -431,9 +751,12 @@ func NewCRDMetaController(
 			DynInformerFactory: dynInformerFactory,
 			WorkerCount:        workerCount,
 			WatchControllers:   make(map[string]*WatchController),
+			EventRecorder:      recorder,
+			Shard:              shard,
 		},
-		Lister:   metaInformerFactory.Metacontroller().V1alpha1().GenericControllers().Lister(),
-		Informer: metaInformerFactory.Metacontroller().V1alpha1().GenericControllers().Informer(),
+		Clientset: metaClientset,
+		Lister:    metaInformerFactory.Metacontroller().V1alpha1().GenericControllers().Lister(),
+		Informer:  metaInformerFactory.Metacontroller().V1alpha1().GenericControllers().Informer(),
 		Queue: workqueue.NewNamedRateLimitingQueue(
 			workqueue.DefaultControllerRateLimiter(), "CRD GenericController",
 		),
@@ -476,6 +799,25 @@ func (mc *CRDMetaController) Start() {
 	}()
 }
 
+// ReadinessChecks returns the readiness checks of the informer of
+// GenericController resources & the running watch controllers
+func (mc *CRDMetaController) ReadinessChecks() []health.Check {
+	mc.mutex.RLock()
+	defer mc.mutex.RUnlock()
+
+	checks := []health.Check{
+		health.NewCheck(
+			"GenericController informer",
+			mc.Informer.HasSynced,
+			"Informer has not synced",
+		),
+	}
+	for _, key := range mc.watchControllerKeys() {
+		checks = append(checks, newWatchControllerCheck(key, mc.WatchControllers[key]))
+	}
+	return checks
+}
+
 // Stop stops this MetaController
 func (mc *CRDMetaController) Stop() {
 	// Stop this instance first so no changes to GenericController(s)
@@ -547,7 +889,7 @@ func (mc *CRDMetaController) sync(key string) error {
 		// cleanup this GenericController instance if exists
 		if c, ok := mc.WatchControllers[key]; ok {
 			c.Stop()
-			delete(mc.WatchControllers, key)
+			mc.deleteWatchController(key)
 		}
 		// return as non error case
 		return nil
@@ -572,7 +914,7 @@ func (mc *CRDMetaController) syncGenericController(gctl *v1alpha1.GenericControl
 		// If changed, then apply this new desired state of GenericController
 		// resource. In other words stop & recreate.
 		c.Stop()
-		delete(mc.WatchControllers, gctl.AsNamespaceNameKey())
+		mc.deleteWatchController(gctl.AsNamespaceNameKey())
 	}
 
 	// init the controller for the watch resource specified in
@@ -581,18 +923,81 @@ func (mc *CRDMetaController) syncGenericController(gctl *v1alpha1.GenericControl
 		mc.ResourceManager,
 		mc.DynClientset,
 		mc.DynInformerFactory,
+		mc.EventRecorder,
+		mc.Shard,
 		gctl,
 	)
 	if err != nil {
+		// report the failure in GenericController status
+		statusErr := mc.updateStatus(
+			gctl.Namespace,
+			gctl.Name,
+			newDiscoveryFailedStatus(err),
+		)
+		if statusErr != nil {
+			glog.Warningf(
+				"Failed to update status of %q: %s: %v",
+				gctl.AsNamespaceNameKey(),
+				mc,
+				statusErr,
+			)
+		}
 		return err
 	}
+	wc.UpdateStatusFn = func(status v1alpha1.GenericControllerStatus) error {
+		return mc.updateStatus(gctl.Namespace, gctl.Name, status)
+	}
+	wc.DryRun = mc.DryRun
+	wc.HookDispatcher = mc.HookDispatcher
 	// start this watch based controller
 	wc.Start(mc.WorkerCount)
 	// add to the registry of watch based controllers
-	mc.WatchControllers[gctl.AsNamespaceNameKey()] = wc
+	mc.setWatchController(gctl.AsNamespaceNameKey(), wc)
 	return nil
 }
 
+// updateStatus updates the status of the GenericController
+// identified by the provided namespace & name
+func (mc *CRDMetaController) updateStatus(
+	namespace, name string,
+	status v1alpha1.GenericControllerStatus,
+) error {
+	// NOTE:
+	//	When sharded, only the member owning the GenericController
+	// updates its status. This avoids the status flapping between
+	// the states tracked by every member.
+	if !mc.Shard.Owns(namespace + "/" + name) {
+		return nil
+	}
+	gctl, err := mc.Lister.GenericControllers(namespace).Get(name)
+	if err != nil {
+		return err
+	}
+	if isStatusEqual(gctl.Status, status) {
+		// nothing to be updated
+		return nil
+	}
+	// do not mutate the object from cache
+	gctlCopy := gctl.DeepCopy()
+	gctlCopy.Status = status
+	_, err = mc.Clientset.MetacontrollerV1alpha1().
+		GenericControllers(namespace).
+		UpdateStatus(gctlCopy)
+	if apierrors.IsConflict(err) {
+		// cache is stale & hence status will be updated
+		// during next attempt
+		glog.V(4).Infof(
+			"Will retry status update of %s/%s: %s: %v",
+			namespace,
+			name,
+			mc,
+			err,
+		)
+		return nil
+	}
+	return err
+}
+
 func (mc *CRDMetaController) enqueueGenericController(obj interface{}) {
 	key, err := common.KeyFunc(obj)
 	if err != nil {
@@ -17,12 +17,15 @@ limitations under the License.
 package generic
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/sharding"
 )
 
 func TestNewConfigMetaController(t *testing.T) {
@@ -211,3 +214,269 @@ func TestConfigMetaControllerStartWithRetry(t *testing.T) {
 		})
 	}
 }
+
+func TestDiffConfigs(t *testing.T) {
+	newConfig := func(name string, resyncSecs int32) *v1alpha1.GenericController {
+		return &v1alpha1.GenericController{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test",
+			},
+			Spec: v1alpha1.GenericControllerSpec{
+				ResyncPeriodSeconds: &resyncSecs,
+			},
+		}
+	}
+	var tests = map[string]struct {
+		running       map[string]*v1alpha1.GenericController
+		desired       []*v1alpha1.GenericController
+		expectAdded   []string
+		expectChanged []string
+		expectRemoved []string
+	}{
+		"nothing running & nothing desired": {},
+		"all added": {
+			desired: []*v1alpha1.GenericController{
+				newConfig("one", 10),
+				newConfig("two", 10),
+			},
+			expectAdded: []string{"test/one", "test/two"},
+		},
+		"all removed": {
+			running: map[string]*v1alpha1.GenericController{
+				"test/one": newConfig("one", 10),
+				"test/two": newConfig("two", 10),
+			},
+			expectRemoved: []string{"test/one", "test/two"},
+		},
+		"no change": {
+			running: map[string]*v1alpha1.GenericController{
+				"test/one": newConfig("one", 10),
+			},
+			desired: []*v1alpha1.GenericController{
+				newConfig("one", 10),
+			},
+		},
+		"added, changed & removed": {
+			running: map[string]*v1alpha1.GenericController{
+				"test/one": newConfig("one", 10),
+				"test/two": newConfig("two", 10),
+			},
+			desired: []*v1alpha1.GenericController{
+				newConfig("one", 20),
+				newConfig("three", 10),
+			},
+			expectAdded:   []string{"test/three"},
+			expectChanged: []string{"test/one"},
+			expectRemoved: []string{"test/two"},
+		},
+	}
+	keysOf := func(configs []*v1alpha1.GenericController) []string {
+		var keys []string
+		for _, conf := range configs {
+			keys = append(keys, conf.AsNamespaceNameKey())
+		}
+		return keys
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			added, changed, removed := diffConfigs(mock.running, mock.desired)
+			if !reflect.DeepEqual(keysOf(added), mock.expectAdded) {
+				t.Fatalf("Expected added %v got %v", mock.expectAdded, keysOf(added))
+			}
+			if !reflect.DeepEqual(keysOf(changed), mock.expectChanged) {
+				t.Fatalf("Expected changed %v got %v", mock.expectChanged, keysOf(changed))
+			}
+			if !reflect.DeepEqual(removed, mock.expectRemoved) {
+				t.Fatalf("Expected removed %v got %v", mock.expectRemoved, removed)
+			}
+		})
+	}
+}
+
+func TestConfigMetaControllerReloadConfigsRetainsRunning(t *testing.T) {
+	running := &v1alpha1.GenericController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test",
+			Namespace: "test",
+		},
+	}
+	var tests = map[string]struct {
+		loadFn func() ([]*v1alpha1.GenericController, error)
+	}{
+		"load error": {
+			loadFn: func() ([]*v1alpha1.GenericController, error) {
+				return nil, errors.Errorf("Err")
+			},
+		},
+		"duplicate configs": {
+			loadFn: func() ([]*v1alpha1.GenericController, error) {
+				return []*v1alpha1.GenericController{
+					running.DeepCopy(),
+					running.DeepCopy(),
+				}, nil
+			},
+		},
+		"unchanged config": {
+			loadFn: func() ([]*v1alpha1.GenericController, error) {
+				return []*v1alpha1.GenericController{
+					running.DeepCopy(),
+				}, nil
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			wc := &WatchController{
+				GCtlConfig: running,
+			}
+			ctl := &ConfigMetaController{
+				ConfigLoadFn: mock.loadFn,
+				BaseMetaController: BaseMetaController{
+					WatchControllers: map[string]*WatchController{
+						"test/test": wc,
+					},
+				},
+			}
+			ctl.reloadConfigs()
+			if len(ctl.WatchControllers) != 1 ||
+				ctl.WatchControllers["test/test"] != wc {
+				t.Fatalf("Expected running controller to be retained")
+			}
+		})
+	}
+}
+
+func TestBaseMetaControllerSetAndDeleteWatchController(t *testing.T) {
+	mc := &BaseMetaController{}
+	wc := &WatchController{}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		mc.setWatchController("test/test", wc)
+		if mc.WatchControllers["test/test"] != wc {
+			t.Errorf("Expected watch controller to be set")
+		}
+		mc.deleteWatchController("test/test")
+		if len(mc.WatchControllers) != 0 {
+			t.Errorf(
+				"Expected no watch controllers got %d",
+				len(mc.WatchControllers),
+			)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected set & delete to complete got deadlock")
+	}
+}
+
+func TestConfigMetaControllerReloadConfigsStopsRemoved(t *testing.T) {
+	doneCh := make(chan struct{})
+	close(doneCh)
+	wc := &WatchController{
+		GCtlConfig: &v1alpha1.GenericController{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test",
+				Namespace: "test",
+			},
+		},
+		stopCh: make(chan struct{}),
+		doneCh: doneCh,
+		watchQ: workqueue.NewRateLimitingQueue(
+			workqueue.DefaultControllerRateLimiter(),
+		),
+	}
+	ctl := &ConfigMetaController{
+		ConfigLoadFn: func() ([]*v1alpha1.GenericController, error) {
+			return nil, nil
+		},
+		BaseMetaController: BaseMetaController{
+			WatchControllers: map[string]*WatchController{
+				"test/test": wc,
+			},
+		},
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ctl.reloadConfigs()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected reload to complete got deadlock")
+	}
+	if len(ctl.WatchControllers) != 0 {
+		t.Fatalf(
+			"Expected removed controller to be deleted got %d controllers",
+			len(ctl.WatchControllers),
+		)
+	}
+	select {
+	case <-wc.stopCh:
+	default:
+		t.Fatalf("Expected removed controller to be stopped")
+	}
+}
+
+func TestConfigMetaControllerReadinessChecks(t *testing.T) {
+	running := &v1alpha1.GenericController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "running",
+			Namespace: "test",
+		},
+	}
+	failed := &v1alpha1.GenericController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "failed",
+			Namespace: "test",
+		},
+	}
+	ctl := &ConfigMetaController{
+		Configs: []*v1alpha1.GenericController{running, failed},
+		BaseMetaController: BaseMetaController{
+			WatchControllers: map[string]*WatchController{
+				"test/running": {
+					GCtlConfig: running,
+					status:     newStatusTracker(),
+				},
+			},
+		},
+	}
+	checks := ctl.ReadinessChecks()
+	if len(checks) != 1 {
+		t.Fatalf("Expected 1 check got %d", len(checks))
+	}
+	if checks[0].Name != "GenericController test/running" {
+		t.Fatalf(
+			"Expected check %q got %q",
+			"GenericController test/running",
+			checks[0].Name,
+		)
+	}
+}
+
+func TestCRDMetaControllerUpdateStatusSkipsNotOwned(t *testing.T) {
+	// a shard that has not renewed its lease owns no keys
+	shard, err := sharding.New(sharding.Config{Identity: "metac-0"}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	mc := &CRDMetaController{
+		BaseMetaController: BaseMetaController{
+			Shard: shard,
+		},
+	}
+	// lister & clientset are not set since these must not
+	// be used for a GenericController not owned by this shard
+	err = mc.updateStatus("test", "test", v1alpha1.GenericControllerStatus{})
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+}
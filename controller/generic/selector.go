@@ -417,3 +417,40 @@ func (s *Selection) MatchAttachmentAgainstWatch(
 	// All selector matches are **AND-ed**
 	return lanMatch && advanceMatch, nil
 }
+
+// WatchNamespaceOfAttachment returns the namespace of the watches
+// that may match the provided attachment. This is derived from the
+// reference expressions of the attachment's advanced selector. False
+// is returned if watches from any namespace may match the attachment.
+func (s *Selection) WatchNamespaceOfAttachment(
+	attachment *unstructured.Unstructured,
+) (string, bool) {
+	if attachment == nil {
+		return "", false
+	}
+	sel := s.advancedSelectorReg.Get(
+		attachment.GetAPIVersion(),
+		attachment.GetKind(),
+	)
+	var namespace string
+	var isNamespaced bool
+	for _, term := range sel.Terms {
+		if term == nil {
+			continue
+		}
+		refSel := selector.NewReferenceSelector(
+			selector.ReferenceSelectorConfig{
+				MatchReference:            term.MatchReference,
+				MatchReferenceExpressions: term.MatchReferenceExpressions,
+			},
+		)
+		termNamespace, ok := refSel.ReferenceNamespace(attachment)
+		// terms are OR-ed, hence every term needs to refer
+		// to the same namespace
+		if !ok || (isNamespaced && termNamespace != namespace) {
+			return "", false
+		}
+		namespace, isNamespaced = termNamespace, true
+	}
+	return namespace, isNamespaced
+}
@@ -0,0 +1,479 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/debug"
+	"openebs.io/metac/metrics"
+)
+
+const (
+	// maxSyncErrors is the maximum number of watches whose last
+	// sync error is reported in GenericController status. The
+	// oldest error is evicted when this limit is reached.
+	maxSyncErrors = 10
+
+	// maxDryRunChanges is the maximum number of dry run changes
+	// listed in GenericController status
+	maxDryRunChanges = 20
+
+	// statusUpdateInterval is the interval at which status of
+	// GenericController is updated
+	statusUpdateInterval = 5 * time.Second
+)
+
+// Condition IDs set in GenericController status
+const (
+	// ConditionIDInformersSynced is the ID of the condition that
+	// reports if watch & attachment informers have synced
+	ConditionIDInformersSynced = "InformersSynced"
+
+	// ConditionIDDiscovery is the ID of the condition that reports
+	// if watch & attachment APIs were discovered
+	ConditionIDDiscovery = "Discovery"
+
+	// ConditionIDHookCircuitOpen is the ID of the condition that
+	// reports if the invocations of the hook are short-circuited
+	ConditionIDHookCircuitOpen = "HookCircuitOpen"
+
+	// ConditionIDSyncErrorPrefix is prefixed to the watch key to
+	// form the ID of the condition that reports the last sync
+	// error of this watch
+	ConditionIDSyncErrorPrefix = "SyncError:"
+)
+
+// syncError is the last sync error observed for a watch
+type syncError struct {
+	message   string
+	timestamp metav1.Time
+}
+
+// statusTracker tracks the runtime state of a WatchController.
+// This state is reflected as the status of the corresponding
+// GenericController.
+//
+// NOTE:
+//	All the methods are safe to be invoked on a nil tracker
+type statusTracker struct {
+	mutex sync.Mutex
+
+	// time when the tracker was created i.e. when the watch &
+	// attachment APIs were discovered
+	createdAt metav1.Time
+
+	informersSynced   bool
+	informersSyncedAt metav1.Time
+
+	hookFailureCount int64
+
+	hookCircuitOpen   bool
+	hookCircuitOpenAt metav1.Time
+
+	// watch keys being reconciled
+	inFlight map[string]bool
+
+	// last sync error anchored by watch key
+	syncErrors map[string]syncError
+
+	// dryRun is true if the changes are computed without being
+	// applied
+	dryRun bool
+
+	// changes computed during the last sync anchored by watch
+	// key; these are tracked in case of dry run only
+	dryRunChanges map[string][]common.DryRunChange
+}
+
+// newStatusTracker returns a new instance of statusTracker
+func newStatusTracker() *statusTracker {
+	return &statusTracker{
+		createdAt:     metav1.Now(),
+		inFlight:      map[string]bool{},
+		syncErrors:    map[string]syncError{},
+		dryRunChanges: map[string][]common.DryRunChange{},
+	}
+}
+
+// setInformersSynced marks the informers as synced
+func (t *statusTracker) setInformersSynced() {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.informersSynced = true
+	t.informersSyncedAt = metav1.Now()
+}
+
+// hasInformersSynced returns true if the informers have synced
+func (t *statusTracker) hasInformersSynced() bool {
+	if t == nil {
+		return false
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.informersSynced
+}
+
+// incHookFailureCount increments the number of failed hook
+// invocations
+func (t *statusTracker) incHookFailureCount() {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.hookFailureCount++
+}
+
+// setHookCircuitOpen remembers if the last hook invocation was
+// short-circuited
+func (t *statusTracker) setHookCircuitOpen(isOpen bool) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if isOpen && !t.hookCircuitOpen {
+		t.hookCircuitOpenAt = metav1.Now()
+	}
+	t.hookCircuitOpen = isOpen
+}
+
+// setSyncStarted marks the given watch as being reconciled
+func (t *statusTracker) setSyncStarted(watchKey string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.inFlight[watchKey] = true
+}
+
+// setSyncResult marks the reconciliation of the given watch as
+// complete & remembers the provided error as the last sync error
+// of this watch. A nil error clears the last sync error of this
+// watch.
+func (t *statusTracker) setSyncResult(watchKey string, err error) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.inFlight, watchKey)
+	if err == nil {
+		delete(t.syncErrors, watchKey)
+		return
+	}
+	if old, found := t.syncErrors[watchKey]; found &&
+		old.message == err.Error() {
+		// retain the timestamp of this error since nothing
+		// has changed
+		return
+	}
+	if _, found := t.syncErrors[watchKey]; !found &&
+		len(t.syncErrors) >= maxSyncErrors {
+		t.evictOldestSyncError()
+	}
+	t.syncErrors[watchKey] = syncError{
+		message:   err.Error(),
+		timestamp: metav1.Now(),
+	}
+}
+
+// inFlightKeys returns the sorted keys of the watches that are
+// being reconciled
+func (t *statusTracker) inFlightKeys() []string {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var keys []string
+	for key := range t.inFlight {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// debugSyncErrors returns the last sync errors anchored by watch
+// key that are reported by the debug server
+func (t *statusTracker) debugSyncErrors() map[string]debug.SyncError {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.syncErrors) == 0 {
+		return nil
+	}
+	errs := make(map[string]debug.SyncError, len(t.syncErrors))
+	for key, se := range t.syncErrors {
+		errs[key] = debug.SyncError{
+			Error:     se.message,
+			Timestamp: se.timestamp.Time,
+		}
+	}
+	return errs
+}
+
+// setDryRunChanges remembers the provided changes as the ones
+// computed during the last sync of the given watch
+func (t *statusTracker) setDryRunChanges(
+	watchKey string,
+	changes []common.DryRunChange,
+) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.dryRun = true
+	if len(changes) == 0 {
+		delete(t.dryRunChanges, watchKey)
+		return
+	}
+	t.dryRunChanges[watchKey] = changes
+}
+
+// clearDryRunChanges forgets the changes computed during the
+// earlier syncs once a sync runs without dry run
+func (t *statusTracker) clearDryRunChanges() {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.dryRun = false
+	t.dryRunChanges = map[string][]common.DryRunChange{}
+}
+
+// dryRunStatus returns the summary of the changes computed during
+// the last sync of every watch
+//
+// NOTE:
+//	This must be invoked with the lock held
+func (t *statusTracker) dryRunStatus() *v1alpha1.GenericControllerDryRunStatus {
+	status := &v1alpha1.GenericControllerDryRunStatus{}
+	// sort the watch keys to list the changes in a deterministic
+	// order
+	var watchKeys []string
+	for key := range t.dryRunChanges {
+		watchKeys = append(watchKeys, key)
+	}
+	sort.Strings(watchKeys)
+	for _, key := range watchKeys {
+		for _, change := range t.dryRunChanges[key] {
+			switch change.Operation {
+			case metrics.OperationCreate:
+				status.Creates++
+			case metrics.OperationUpdate:
+				status.Updates++
+			case metrics.OperationDelete:
+				status.Deletes++
+			}
+			if len(status.Changes) >= maxDryRunChanges {
+				continue
+			}
+			status.Changes = append(
+				status.Changes,
+				v1alpha1.GenericControllerDryRunChange{
+					Watch:     key,
+					Operation: change.Operation,
+					Object:    change.Object,
+				},
+			)
+		}
+	}
+	return status
+}
+
+// evictOldestSyncError removes the oldest sync error
+//
+// NOTE:
+//	This must be invoked with the lock held
+func (t *statusTracker) evictOldestSyncError() {
+	var oldestKey string
+	var oldest *metav1.Time
+	for key, se := range t.syncErrors {
+		ts := se.timestamp
+		if oldest == nil || ts.Before(oldest) {
+			oldestKey = key
+			oldest = &ts
+		}
+	}
+	if oldest != nil {
+		delete(t.syncErrors, oldestKey)
+	}
+}
+
+// Status returns the GenericController status that reflects the
+// tracked state
+func (t *statusTracker) Status() v1alpha1.GenericControllerStatus {
+	if t == nil {
+		return v1alpha1.GenericControllerStatus{}
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	status := v1alpha1.GenericControllerStatus{
+		Phase:            v1alpha1.GenericControllerStatusPhaseCompleted,
+		HookFailureCount: t.hookFailureCount,
+	}
+
+	discovery := newCondition(
+		ConditionIDDiscovery,
+		v1alpha1.GenericControllerConditionStateInProgress,
+		v1alpha1.GenericControllerConditionAssertPassed,
+		t.createdAt,
+	)
+	discovery.Message = "Watch & attachment APIs were discovered"
+	status.Conditions = append(status.Conditions, discovery)
+
+	if t.informersSynced {
+		synced := newCondition(
+			ConditionIDInformersSynced,
+			v1alpha1.GenericControllerConditionStateInProgress,
+			v1alpha1.GenericControllerConditionAssertPassed,
+			t.informersSyncedAt,
+		)
+		synced.Message = "Watch & attachment informers have synced"
+		status.Conditions = append(status.Conditions, synced)
+	} else {
+		syncing := newCondition(
+			ConditionIDInformersSynced,
+			v1alpha1.GenericControllerConditionStateInProgress,
+			v1alpha1.GenericControllerConditionAssertFailed,
+			t.createdAt,
+		)
+		syncing.Message = "Waiting for watch & attachment informers to sync"
+		status.Conditions = append(status.Conditions, syncing)
+		// watches are not reconciled till the informers sync
+		status.Phase = v1alpha1.GenericControllerStatusPhaseInProgress
+	}
+
+	if t.hookCircuitOpen {
+		open := newCondition(
+			ConditionIDHookCircuitOpen,
+			v1alpha1.GenericControllerConditionStateError,
+			v1alpha1.GenericControllerConditionAssertFailed,
+			t.hookCircuitOpenAt,
+		)
+		open.Message = "Hook invocations are short-circuited"
+		open.Help = "Verify if the hook is healthy"
+		status.Conditions = append(status.Conditions, open)
+		status.Phase = v1alpha1.GenericControllerStatusPhaseError
+	}
+
+	// sort the watch keys to report the conditions in a
+	// deterministic order
+	var watchKeys []string
+	for key := range t.syncErrors {
+		watchKeys = append(watchKeys, key)
+	}
+	sort.Strings(watchKeys)
+	for _, key := range watchKeys {
+		se := t.syncErrors[key]
+		failed := newCondition(
+			ConditionIDSyncErrorPrefix+key,
+			v1alpha1.GenericControllerConditionStateError,
+			v1alpha1.GenericControllerConditionAssertFailed,
+			se.timestamp,
+		)
+		failed.Message = "Failed to sync watch " + key
+		failed.Error = se.message
+		status.Conditions = append(status.Conditions, failed)
+	}
+	if len(watchKeys) != 0 {
+		status.Phase = v1alpha1.GenericControllerStatusPhaseError
+	}
+	if t.dryRun {
+		status.DryRun = t.dryRunStatus()
+	}
+	return status
+}
+
+// newCondition returns a new GenericController condition
+func newCondition(
+	id string,
+	state v1alpha1.GenericControllerConditionState,
+	assert v1alpha1.GenericControllerConditionAssert,
+	timestamp metav1.Time,
+) v1alpha1.GenericControllerCondition {
+	return v1alpha1.GenericControllerCondition{
+		ID:                   id,
+		State:                &state,
+		Assert:               &assert,
+		LastUpdatedTimestamp: &timestamp,
+	}
+}
+
+// newDiscoveryFailedStatus returns the GenericController status
+// that reports the provided discovery error
+func newDiscoveryFailedStatus(err error) v1alpha1.GenericControllerStatus {
+	failed := newCondition(
+		ConditionIDDiscovery,
+		v1alpha1.GenericControllerConditionStateError,
+		v1alpha1.GenericControllerConditionAssertFailed,
+		metav1.Now(),
+	)
+	failed.Message = "Failed to discover watch & attachment APIs"
+	failed.Error = err.Error()
+	failed.Help = "Verify if watch & attachment APIs are served by kubernetes"
+	return v1alpha1.GenericControllerStatus{
+		Phase:      v1alpha1.GenericControllerStatusPhaseError,
+		Conditions: []v1alpha1.GenericControllerCondition{failed},
+	}
+}
+
+// isStatusEqual returns true if the provided statuses are same
+// after ignoring the timestamps of their conditions
+func isStatusEqual(old, new v1alpha1.GenericControllerStatus) bool {
+	withoutTimestamps := func(
+		status v1alpha1.GenericControllerStatus,
+	) v1alpha1.GenericControllerStatus {
+		copy := *status.DeepCopy()
+		for i := range copy.Conditions {
+			copy.Conditions[i].LastUpdatedTimestamp = nil
+		}
+		return copy
+	}
+	return apiequality.Semantic.DeepEqual(
+		withoutTimestamps(old),
+		withoutTimestamps(new),
+	)
+}
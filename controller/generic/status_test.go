@@ -0,0 +1,250 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/common"
+	"openebs.io/metac/metrics"
+)
+
+func TestStatusTrackerStatus(t *testing.T) {
+	var tests = map[string]struct {
+		track          func(t *statusTracker)
+		isNilTracker   bool
+		expectPhase    v1alpha1.GenericControllerStatusPhase
+		expectFailures int64
+		expectConds    int
+		expectSynced   bool
+		expectDryRun   *v1alpha1.GenericControllerDryRunStatus
+	}{
+		"nil tracker": {
+			track:        func(t *statusTracker) {},
+			isNilTracker: true,
+		},
+		"informers not synced": {
+			track:       func(t *statusTracker) {},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseInProgress,
+			expectConds: 2,
+		},
+		"informers synced": {
+			track: func(t *statusTracker) {
+				t.setInformersSynced()
+			},
+			expectPhase:  v1alpha1.GenericControllerStatusPhaseCompleted,
+			expectConds:  2,
+			expectSynced: true,
+		},
+		"hook failures": {
+			track: func(t *statusTracker) {
+				t.incHookFailureCount()
+				t.incHookFailureCount()
+			},
+			expectPhase:    v1alpha1.GenericControllerStatusPhaseInProgress,
+			expectFailures: 2,
+			expectConds:    2,
+		},
+		"hook circuit open": {
+			track: func(t *statusTracker) {
+				t.setHookCircuitOpen(true)
+			},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseError,
+			expectConds: 3,
+		},
+		"hook circuit closed": {
+			track: func(t *statusTracker) {
+				t.setHookCircuitOpen(true)
+				t.setHookCircuitOpen(false)
+			},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseInProgress,
+			expectConds: 2,
+		},
+		"sync error": {
+			track: func(t *statusTracker) {
+				t.setSyncResult("ns/watch", errors.Errorf("failed"))
+			},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseError,
+			expectConds: 3,
+		},
+		"sync error cleared on success": {
+			track: func(t *statusTracker) {
+				t.setSyncResult("ns/watch", errors.Errorf("failed"))
+				t.setSyncResult("ns/watch", nil)
+			},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseInProgress,
+			expectConds: 2,
+		},
+		"sync errors are bounded": {
+			track: func(t *statusTracker) {
+				for i := 0; i < maxSyncErrors+5; i++ {
+					t.setSyncResult(
+						fmt.Sprintf("ns/watch-%d", i),
+						errors.Errorf("failed"),
+					)
+				}
+			},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseError,
+			expectConds: 2 + maxSyncErrors,
+		},
+		"dry run changes": {
+			track: func(t *statusTracker) {
+				t.setDryRunChanges("ns/watch-1", []common.DryRunChange{
+					{Operation: metrics.OperationCreate, Object: "v1:Pod:ns:p1"},
+					{Operation: metrics.OperationUpdate, Object: "v1:Pod:ns:p2"},
+				})
+				t.setDryRunChanges("ns/watch-2", []common.DryRunChange{
+					{Operation: metrics.OperationDelete, Object: "v1:Pod:ns:p3"},
+				})
+			},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseInProgress,
+			expectConds: 2,
+			expectDryRun: &v1alpha1.GenericControllerDryRunStatus{
+				Creates: 1,
+				Updates: 1,
+				Deletes: 1,
+				Changes: []v1alpha1.GenericControllerDryRunChange{
+					{Watch: "ns/watch-1", Operation: "create", Object: "v1:Pod:ns:p1"},
+					{Watch: "ns/watch-1", Operation: "update", Object: "v1:Pod:ns:p2"},
+					{Watch: "ns/watch-2", Operation: "delete", Object: "v1:Pod:ns:p3"},
+				},
+			},
+		},
+		"dry run changes cleared on sync without dry run": {
+			track: func(t *statusTracker) {
+				t.setDryRunChanges("ns/watch", []common.DryRunChange{
+					{Operation: metrics.OperationCreate, Object: "v1:Pod:ns:p1"},
+				})
+				t.clearDryRunChanges()
+			},
+			expectPhase: v1alpha1.GenericControllerStatusPhaseInProgress,
+			expectConds: 2,
+		},
+		"dry run changes cleared on no change": {
+			track: func(t *statusTracker) {
+				t.setDryRunChanges("ns/watch", []common.DryRunChange{
+					{Operation: metrics.OperationCreate, Object: "v1:Pod:ns:p1"},
+				})
+				t.setDryRunChanges("ns/watch", nil)
+			},
+			expectPhase:  v1alpha1.GenericControllerStatusPhaseInProgress,
+			expectConds:  2,
+			expectDryRun: &v1alpha1.GenericControllerDryRunStatus{},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var tracker *statusTracker
+			if !mock.isNilTracker {
+				tracker = newStatusTracker()
+			}
+			mock.track(tracker)
+			if tracker.hasInformersSynced() != mock.expectSynced {
+				t.Fatalf(
+					"Expected has informers synced %t got %t",
+					mock.expectSynced,
+					tracker.hasInformersSynced(),
+				)
+			}
+			got := tracker.Status()
+			if got.Phase != mock.expectPhase {
+				t.Fatalf("Expected phase %q got %q", mock.expectPhase, got.Phase)
+			}
+			if got.HookFailureCount != mock.expectFailures {
+				t.Fatalf(
+					"Expected hook failures %d got %d",
+					mock.expectFailures,
+					got.HookFailureCount,
+				)
+			}
+			if !reflect.DeepEqual(got.DryRun, mock.expectDryRun) {
+				t.Fatalf(
+					"Expected dry run status %+v got %+v",
+					mock.expectDryRun,
+					got.DryRun,
+				)
+			}
+			if len(got.Conditions) != mock.expectConds {
+				t.Fatalf(
+					"Expected conditions %d got %d",
+					mock.expectConds,
+					len(got.Conditions),
+				)
+			}
+			for _, cond := range got.Conditions {
+				if cond.ID != ConditionIDInformersSynced {
+					continue
+				}
+				isSynced := *cond.Assert == v1alpha1.GenericControllerConditionAssertPassed
+				if isSynced != mock.expectSynced {
+					t.Fatalf(
+						"Expected informers synced %t got %t",
+						mock.expectSynced,
+						isSynced,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestStatusTrackerDebugInfo(t *testing.T) {
+	tracker := newStatusTracker()
+	tracker.setSyncStarted("ns/b")
+	tracker.setSyncStarted("ns/a")
+	if got := tracker.inFlightKeys(); !reflect.DeepEqual(got, []string{"ns/a", "ns/b"}) {
+		t.Fatalf("Expected in flight keys [ns/a ns/b] got %v", got)
+	}
+	if got := tracker.debugSyncErrors(); got != nil {
+		t.Fatalf("Expected no sync errors got %v", got)
+	}
+
+	tracker.setSyncResult("ns/a", errors.Errorf("failed"))
+	tracker.setSyncResult("ns/b", nil)
+	if got := tracker.inFlightKeys(); len(got) != 0 {
+		t.Fatalf("Expected no in flight keys got %v", got)
+	}
+	got := tracker.debugSyncErrors()
+	if len(got) != 1 || got["ns/a"].Error != "failed" {
+		t.Fatalf("Expected sync error of ns/a got %v", got)
+	}
+
+	var nilTracker *statusTracker
+	nilTracker.setSyncStarted("ns/a")
+	if nilTracker.inFlightKeys() != nil || nilTracker.debugSyncErrors() != nil {
+		t.Fatalf("Expected nil tracker to track nothing")
+	}
+}
+
+func TestIsStatusEqual(t *testing.T) {
+	old := newDiscoveryFailedStatus(errors.Errorf("failed"))
+	new := newDiscoveryFailedStatus(errors.Errorf("failed"))
+	if !isStatusEqual(old, new) {
+		t.Fatalf("Expected equal statuses when only timestamps differ")
+	}
+	new = newDiscoveryFailedStatus(errors.Errorf("failed again"))
+	if isStatusEqual(old, new) {
+		t.Fatalf("Expected unequal statuses when errors differ")
+	}
+}
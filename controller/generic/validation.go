@@ -0,0 +1,299 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/glog"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+)
+
+// InvalidHookResponseError is returned when the response of a sync
+// or finalize hook has one or more violations
+type InvalidHookResponseError struct {
+	Violations []string
+}
+
+// Error implements error interface
+func (e *InvalidHookResponseError) Error() string {
+	return fmt.Sprintf(
+		"Invalid hook response: %d violation(s): %s",
+		len(e.Violations),
+		strings.Join(e.Violations, "; "),
+	)
+}
+
+// responseValidator validates the objects of a hook response before
+// they are applied against the cluster
+//
+// NOTE:
+//	All the violations are collected instead of failing on the first
+// one. This lets the hook authors fix them all at once.
+type responseValidator struct {
+	discovery *dynamicdiscovery.APIResourceDiscovery
+
+	// watch whose hook response is being validated
+	watch *unstructured.Unstructured
+
+	// group kinds declared as attachments in the GenericController
+	declared map[schema.GroupKind]bool
+
+	// namespaces the attachments are restricted to; attachments
+	// of any namespace are allowed if this is empty
+	namespaces map[string]bool
+
+	violations []string
+}
+
+// newResponseValidator returns a new instance of responseValidator
+func newResponseValidator(
+	discovery *dynamicdiscovery.APIResourceDiscovery,
+	watch *unstructured.Unstructured,
+	attachments []v1alpha1.GenericControllerAttachment,
+	namespaces []string,
+) *responseValidator {
+	v := &responseValidator{
+		discovery:  discovery,
+		watch:      watch,
+		declared:   make(map[schema.GroupKind]bool),
+		namespaces: make(map[string]bool),
+	}
+	for _, ns := range namespaces {
+		v.namespaces[ns] = true
+	}
+	for _, attachment := range attachments {
+		// this is done to map resource name to kind name
+		api := discovery.GetAPIForAPIVersionAndResource(
+			attachment.APIVersion,
+			attachment.Resource,
+		)
+		if api == nil {
+			glog.V(4).Infof(
+				"%s: Can't find attachment resource %q %q",
+				v,
+				attachment.APIVersion,
+				attachment.Resource,
+			)
+			continue
+		}
+		// ignore API version since any served version of a
+		// declared kind can be returned by the hook
+		gk := schema.FromAPIVersionAndKind(attachment.APIVersion, api.Kind).GroupKind()
+		v.declared[gk] = true
+	}
+	return v
+}
+
+// String implements Stringer interface
+func (v *responseValidator) String() string {
+	return "Hook response validator"
+}
+
+// Validate returns InvalidHookResponseError if any of the attachments,
+// explicit updates or explicit deletes of the given response is
+// invalid
+func (v *responseValidator) Validate(response *SyncHookResponse) error {
+	v.violations = nil
+
+	desired := v.validateObjects("attachments", response.Attachments)
+	v.validateObjects("explicitUpdates", response.ExplicitUpdates)
+	deletes := v.validateObjects("explicitDeletes", response.ExplicitDeletes)
+
+	// an attachment can't be desired & deleted at the same time
+	for i, obj := range response.ExplicitDeletes {
+		if obj == nil {
+			continue
+		}
+		key := v.keyOf(obj)
+		if di, found := deletes[key]; !found || di != i {
+			// invalid or duplicate deletes are already reported
+			continue
+		}
+		if at, found := desired[key]; found {
+			v.addViolation(
+				"explicitDeletes[%d]: Can't delete desired attachments[%d]",
+				i,
+				at,
+			)
+		}
+	}
+
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &InvalidHookResponseError{Violations: v.violations}
+}
+
+// validateObjects validates the given objects of the given field &
+// returns the index of every valid object anchored by its key
+func (v *responseValidator) validateObjects(
+	field string,
+	objects []*unstructured.Unstructured,
+) map[string]int {
+	indices := make(map[string]int)
+	for i, obj := range objects {
+		path := fmt.Sprintf("%s[%d]", field, i)
+		if !v.validateObject(path, obj) {
+			continue
+		}
+		key := v.keyOf(obj)
+		if at, found := indices[key]; found {
+			v.addViolation("%s: Duplicate of %s[%d]", path, field, at)
+			continue
+		}
+		indices[key] = i
+	}
+	return indices
+}
+
+// validateObject returns true if the given object is valid. The
+// violations if any are added against the given path.
+func (v *responseValidator) validateObject(
+	path string,
+	obj *unstructured.Unstructured,
+) bool {
+	if obj == nil {
+		v.addViolation("%s: Object can't be nil", path)
+		return false
+	}
+	apiVersion, kind := obj.GetAPIVersion(), obj.GetKind()
+	isValid := true
+	if apiVersion == "" {
+		v.addViolation("%s: Missing apiVersion", path)
+		isValid = false
+	}
+	if kind == "" {
+		v.addViolation("%s: Missing kind", path)
+		isValid = false
+	}
+	if obj.GetName() == "" {
+		v.addViolation("%s: Missing name", path)
+		isValid = false
+	}
+	if apiVersion == "" || kind == "" {
+		return false
+	}
+	api := v.discovery.GetAPIForAPIVersionAndKind(apiVersion, kind)
+	if api == nil {
+		v.addViolation(
+			"%s: Can't discover kind %q of apiVersion %q",
+			path,
+			kind,
+			apiVersion,
+		)
+		return false
+	}
+	gk := schema.FromAPIVersionAndKind(apiVersion, kind).GroupKind()
+	if !v.declared[gk] {
+		v.addViolation(
+			"%s: Kind %q of apiVersion %q is not declared in attachments",
+			path,
+			kind,
+			apiVersion,
+		)
+		isValid = false
+	}
+	if !api.Namespaced && obj.GetNamespace() != "" {
+		v.addViolation(
+			"%s: Namespace %q can't be set for cluster scoped kind %q",
+			path,
+			obj.GetNamespace(),
+			kind,
+		)
+		isValid = false
+	}
+	// namespace of a namespaced object defaults to watch's namespace
+	//
+	// NOTE:
+	//	This is how these objects get created or updated
+	if api.Namespaced && obj.GetNamespace() == "" && v.watch.GetNamespace() == "" {
+		v.addViolation(
+			"%s: Namespace is required for namespaced kind %q of cluster scoped watch",
+			path,
+			kind,
+		)
+		isValid = false
+	}
+	if api.Namespaced && !v.validateNamespace(path, obj.GetNamespace()) {
+		isValid = false
+	}
+	return isValid
+}
+
+// validateNamespace returns true if the given namespace of a
+// namespaced object is allowed. The violations if any are added
+// against the given path.
+//
+// NOTE:
+//	Attachments may belong to any namespace irrespective of the
+// watch's namespace. These must belong to the namespaces this
+// controller is restricted to, if any.
+func (v *responseValidator) validateNamespace(path string, namespace string) bool {
+	if namespace == "" {
+		// defaults to watch's namespace
+		return true
+	}
+	if len(v.namespaces) != 0 && !v.namespaces[namespace] {
+		v.addViolation(
+			"%s: Namespace %q is not one of the namespaces %q of this controller",
+			path,
+			namespace,
+			v.sortedNamespaces(),
+		)
+		return false
+	}
+	return true
+}
+
+// sortedNamespaces returns the namespaces the attachments are
+// restricted to in sorted order
+func (v *responseValidator) sortedNamespaces() []string {
+	var namespaces []string
+	for ns := range v.namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// keyOf returns the key that identifies the given object after its
+// namespace is defaulted to watch's namespace
+func (v *responseValidator) keyOf(obj *unstructured.Unstructured) string {
+	ns := obj.GetNamespace()
+	if ns == "" {
+		ns = v.watch.GetNamespace()
+	}
+	return fmt.Sprintf(
+		"%s:%s:%s:%s",
+		obj.GetAPIVersion(),
+		obj.GetKind(),
+		ns,
+		obj.GetName(),
+	)
+}
+
+// addViolation adds the given formatted message to the violations
+func (v *responseValidator) addViolation(format string, args ...interface{}) {
+	v.violations = append(v.violations, fmt.Sprintf(format, args...))
+}
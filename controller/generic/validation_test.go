@@ -0,0 +1,338 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package generic
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+)
+
+// newValidationTestObject returns an unstructured instance with the
+// given api version, kind, namespace & name
+func newValidationTestObject(
+	apiVersion string,
+	kind string,
+	namespace string,
+	name string,
+) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+// newValidationTestDiscovery returns a discovery that knows about
+// namespaced ConfigMaps & Secrets and cluster scoped Namespaces
+func newValidationTestDiscovery() *dynamicdiscovery.APIResourceDiscovery {
+	apis := map[string]*dynamicdiscovery.APIResource{
+		"configmaps": {
+			APIResource: metav1.APIResource{
+				Name:       "configmaps",
+				Kind:       "ConfigMap",
+				Namespaced: true,
+			},
+			APIVersion: "v1",
+		},
+		"secrets": {
+			APIResource: metav1.APIResource{
+				Name:       "secrets",
+				Kind:       "Secret",
+				Namespaced: true,
+			},
+			APIVersion: "v1",
+		},
+		"namespaces": {
+			APIResource: metav1.APIResource{
+				Name: "namespaces",
+				Kind: "Namespace",
+			},
+			APIVersion: "v1",
+		},
+	}
+	return &dynamicdiscovery.APIResourceDiscovery{
+		GetAPIForAPIVersionAndResourceFn: func(
+			apiVersion string,
+			resource string,
+		) *dynamicdiscovery.APIResource {
+			if apiVersion != "v1" {
+				return nil
+			}
+			return apis[resource]
+		},
+		GetAPIForAPIVersionAndKindFn: func(
+			apiVersion string,
+			kind string,
+		) *dynamicdiscovery.APIResource {
+			if apiVersion != "v1" {
+				return nil
+			}
+			for _, api := range apis {
+				if api.Kind == kind {
+					return api
+				}
+			}
+			return nil
+		},
+	}
+}
+
+func TestResponseValidatorValidate(t *testing.T) {
+	var tests = map[string]struct {
+		watchNamespace   string
+		namespaces       []string
+		response         *SyncHookResponse
+		expectViolations []string
+	}{
+		"empty response is valid": {
+			response: &SyncHookResponse{},
+		},
+		"declared attachments are valid": {
+			watchNamespace: "ns1",
+			namespaces:     []string{"ns1"},
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "", "cm1"),
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm3"),
+					newValidationTestObject("v1", "Namespace", "", "ns3"),
+				},
+				ExplicitUpdates: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm1"),
+				},
+				ExplicitDeletes: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm2"),
+				},
+			},
+		},
+		"nil object is invalid": {
+			response: &SyncHookResponse{
+				ExplicitUpdates: []*unstructured.Unstructured{nil},
+			},
+			expectViolations: []string{
+				"explicitUpdates[0]: Object can't be nil",
+			},
+		},
+		"missing apiVersion, kind & name are invalid": {
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("", "", "ns1", ""),
+				},
+			},
+			expectViolations: []string{
+				"attachments[0]: Missing apiVersion",
+				"attachments[0]: Missing kind",
+				"attachments[0]: Missing name",
+			},
+		},
+		"undiscovered kind is invalid": {
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "Pod", "ns1", "pod1"),
+				},
+			},
+			expectViolations: []string{
+				`attachments[0]: Can't discover kind "Pod" of apiVersion "v1"`,
+			},
+		},
+		"undeclared kind is invalid": {
+			response: &SyncHookResponse{
+				ExplicitDeletes: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "Secret", "ns1", "s1"),
+				},
+			},
+			expectViolations: []string{
+				`explicitDeletes[0]: Kind "Secret" of apiVersion "v1" is not declared in attachments`,
+			},
+		},
+		"namespace of cluster scoped kind is invalid": {
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "Namespace", "ns1", "ns2"),
+				},
+			},
+			expectViolations: []string{
+				`attachments[0]: Namespace "ns1" can't be set for cluster scoped kind "Namespace"`,
+			},
+		},
+		"missing namespace of cluster scoped watch is invalid": {
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "", "cm1"),
+				},
+			},
+			expectViolations: []string{
+				`attachments[0]: Namespace is required for namespaced kind "ConfigMap" of cluster scoped watch`,
+			},
+		},
+		"attachments outside watch's namespace are valid": {
+			watchNamespace: "ns1",
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns2", "cm1"),
+				},
+				ExplicitUpdates: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns2", "cm1"),
+				},
+				ExplicitDeletes: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns3", "cm1"),
+				},
+			},
+		},
+		"attachments of namespaced watch outside controller's namespaces are invalid": {
+			watchNamespace: "ns1",
+			namespaces:     []string{"ns1", "ns2"},
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns2", "cm1"),
+					newValidationTestObject("v1", "ConfigMap", "ns3", "cm1"),
+				},
+			},
+			expectViolations: []string{
+				`attachments[1]: Namespace "ns3" is not one of the namespaces ["ns1" "ns2"] of this controller`,
+			},
+		},
+		"attachments outside controller's namespaces are invalid": {
+			namespaces: []string{"ns2", "ns1"},
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm1"),
+					newValidationTestObject("v1", "ConfigMap", "ns3", "cm1"),
+				},
+				ExplicitUpdates: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "Namespace", "", "ns3"),
+				},
+			},
+			expectViolations: []string{
+				`attachments[1]: Namespace "ns3" is not one of the namespaces ["ns1" "ns2"] of this controller`,
+			},
+		},
+		"duplicates after defaulting namespace are invalid": {
+			watchNamespace: "ns1",
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "", "cm1"),
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm1"),
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm1"),
+				},
+			},
+			expectViolations: []string{
+				"attachments[1]: Duplicate of attachments[0]",
+				"attachments[2]: Duplicate of attachments[0]",
+			},
+		},
+		"delete of desired attachment is invalid": {
+			watchNamespace: "ns1",
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "", "cm1"),
+					newValidationTestObject("v1", "ConfigMap", "", "cm2"),
+				},
+				ExplicitDeletes: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm2"),
+					newValidationTestObject("v1", "ConfigMap", "ns1", "cm2"),
+				},
+			},
+			expectViolations: []string{
+				"explicitDeletes[1]: Duplicate of explicitDeletes[0]",
+				"explicitDeletes[0]: Can't delete desired attachments[1]",
+			},
+		},
+		"all violations are reported": {
+			response: &SyncHookResponse{
+				Attachments: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "Secret", "ns1", ""),
+				},
+				ExplicitUpdates: []*unstructured.Unstructured{
+					newValidationTestObject("v1", "ConfigMap", "", "cm1"),
+				},
+			},
+			expectViolations: []string{
+				"attachments[0]: Missing name",
+				`attachments[0]: Kind "Secret" of apiVersion "v1" is not declared in attachments`,
+				`explicitUpdates[0]: Namespace is required for namespaced kind "ConfigMap" of cluster scoped watch`,
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			watch := newValidationTestObject("test.io/v1", "Watch", mock.watchNamespace, "watch")
+			attachments := []v1alpha1.GenericControllerAttachment{
+				{
+					GenericControllerResource: v1alpha1.GenericControllerResource{
+						ResourceRule: v1alpha1.ResourceRule{
+							APIVersion: "v1",
+							Resource:   "configmaps",
+						},
+					},
+				},
+				{
+					GenericControllerResource: v1alpha1.GenericControllerResource{
+						ResourceRule: v1alpha1.ResourceRule{
+							APIVersion: "v1",
+							Resource:   "namespaces",
+						},
+					},
+				},
+				{
+					// undiscovered attachments are ignored
+					GenericControllerResource: v1alpha1.GenericControllerResource{
+						ResourceRule: v1alpha1.ResourceRule{
+							APIVersion: "test.io/v1",
+							Resource:   "unknowns",
+						},
+					},
+				},
+			}
+			v := newResponseValidator(
+				newValidationTestDiscovery(),
+				watch,
+				attachments,
+				mock.namespaces,
+			)
+			err := v.Validate(mock.response)
+			if len(mock.expectViolations) == 0 {
+				if err != nil {
+					t.Fatalf("Expected no error got %+v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			invalidErr, ok := err.(*InvalidHookResponseError)
+			if !ok {
+				t.Fatalf("Expected InvalidHookResponseError got %T", err)
+			}
+			if !reflect.DeepEqual(invalidErr.Violations, mock.expectViolations) {
+				t.Fatalf(
+					"Expected violations %q got %q",
+					mock.expectViolations,
+					invalidErr.Violations,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/pprof"
+
+	"github.com/golang/glog"
+)
+
+// ControllerInfo is the runtime state of a controller e.g. a
+// WatchController, a CompositeController's parent controller, etc.
+type ControllerInfo struct {
+	// Kind of the meta controller e.g. GenericController
+	Kind string `json:"kind"`
+
+	// Name of the controller
+	Name string `json:"name"`
+
+	// HasSynced is true if the informers of this controller
+	// have synced
+	HasSynced bool `json:"hasSynced"`
+
+	// QueueLength is the number of keys waiting to be reconciled
+	QueueLength int `json:"queueLength"`
+
+	// InFlightKeys are the keys being reconciled currently
+	InFlightKeys []string `json:"inFlightKeys,omitempty"`
+
+	// SyncErrors are the last sync errors anchored by key
+	SyncErrors map[string]SyncError `json:"syncErrors,omitempty"`
+}
+
+// ShardInfo is the membership of a metac replica in its shard
+// group
+type ShardInfo struct {
+	// Enabled is true if watches are split amongst the replicas
+	Enabled bool `json:"enabled"`
+
+	// Identity of this replica in its shard group
+	Identity string `json:"identity,omitempty"`
+
+	// Members are the live replicas of this shard group
+	Members []string `json:"members,omitempty"`
+}
+
+// Reporter is implemented by the components that report the
+// runtime state of their controllers e.g. a meta controller
+type Reporter interface {
+	DebugInfo() []ControllerInfo
+}
+
+// NewJSONHandler returns a http handler that responds with the
+// JSON encoding of the value returned by the provided function
+func NewJSONHandler(valueFn func() interface{}) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := json.MarshalIndent(valueFn(), "", "  ")
+		if err != nil {
+			glog.Errorf("Can't encode debug info: %v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(raw)
+	})
+}
+
+// RegisterPprof registers the net/http/pprof handlers against
+// the provided mux
+//
+// NOTE:
+//	These handlers are not registered against the default mux
+// since the debug server uses its own mux
+func RegisterPprof(mux *http.ServeMux) {
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// MaxSyncErrors is the maximum number of keys whose last sync
+// error is tracked. The oldest error is evicted when this limit
+// is reached.
+const MaxSyncErrors = 100
+
+// SyncError is the last sync error observed for a key
+type SyncError struct {
+	Error     string    `json:"error"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// KeyTracker tracks the keys being reconciled by a controller as
+// well as the last sync error of its keys
+//
+// NOTE:
+//	All the methods are safe to be invoked on a nil tracker.
+// GenericController does not use this tracker since its status
+// tracker already tracks these keys & their sync errors.
+type KeyTracker struct {
+	mutex sync.Mutex
+
+	inFlight   map[string]bool
+	syncErrors map[string]trackedSyncError
+
+	// incremented for every tracked sync error; this orders the
+	// sync errors even if their timestamps are same
+	seq uint64
+}
+
+// trackedSyncError is a sync error along with its order
+type trackedSyncError struct {
+	SyncError
+	seq uint64
+}
+
+// NewKeyTracker returns a new instance of KeyTracker
+func NewKeyTracker() *KeyTracker {
+	return &KeyTracker{
+		inFlight:   map[string]bool{},
+		syncErrors: map[string]trackedSyncError{},
+	}
+}
+
+// Start marks the provided key as being reconciled
+func (t *KeyTracker) Start(key string) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.inFlight[key] = true
+}
+
+// Done marks the reconciliation of the provided key as complete.
+// The provided error is remembered as the last sync error of this
+// key. A nil error clears the last sync error of this key.
+func (t *KeyTracker) Done(key string, err error) {
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.inFlight, key)
+	if err == nil {
+		delete(t.syncErrors, key)
+		return
+	}
+	if _, found := t.syncErrors[key]; !found &&
+		len(t.syncErrors) >= MaxSyncErrors {
+		t.evictOldestSyncError()
+	}
+	t.seq++
+	t.syncErrors[key] = trackedSyncError{
+		SyncError: SyncError{
+			Error:     err.Error(),
+			Timestamp: time.Now(),
+		},
+		seq: t.seq,
+	}
+}
+
+// evictOldestSyncError removes the oldest sync error
+//
+// NOTE:
+//	This must be invoked with the lock held
+func (t *KeyTracker) evictOldestSyncError() {
+	var oldestKey string
+	var oldest uint64
+	for key, se := range t.syncErrors {
+		if oldestKey == "" || se.seq < oldest {
+			oldestKey = key
+			oldest = se.seq
+		}
+	}
+	delete(t.syncErrors, oldestKey)
+}
+
+// InFlightKeys returns the sorted keys that are being reconciled
+func (t *KeyTracker) InFlightKeys() []string {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var keys []string
+	for key := range t.inFlight {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SyncErrors returns a copy of the last sync errors anchored by
+// key
+func (t *KeyTracker) SyncErrors() map[string]SyncError {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.syncErrors) == 0 {
+		return nil
+	}
+	errs := make(map[string]SyncError, len(t.syncErrors))
+	for key, se := range t.syncErrors {
+		errs[key] = se.SyncError
+	}
+	return errs
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package debug
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestKeyTracker(t *testing.T) {
+	var tests = map[string]struct {
+		track          func(t *KeyTracker)
+		isNilTracker   bool
+		expectInFlight []string
+		expectErrors   []string
+	}{
+		"nil tracker": {
+			track:        func(t *KeyTracker) { t.Start("ns/a") },
+			isNilTracker: true,
+		},
+		"in flight keys": {
+			track: func(t *KeyTracker) {
+				t.Start("ns/b")
+				t.Start("ns/a")
+			},
+			expectInFlight: []string{"ns/a", "ns/b"},
+		},
+		"done key is not in flight": {
+			track: func(t *KeyTracker) {
+				t.Start("ns/a")
+				t.Start("ns/b")
+				t.Done("ns/a", nil)
+			},
+			expectInFlight: []string{"ns/b"},
+		},
+		"sync error": {
+			track: func(t *KeyTracker) {
+				t.Start("ns/a")
+				t.Done("ns/a", errors.Errorf("failed"))
+			},
+			expectErrors: []string{"ns/a"},
+		},
+		"sync error cleared on success": {
+			track: func(t *KeyTracker) {
+				t.Done("ns/a", errors.Errorf("failed"))
+				t.Done("ns/a", nil)
+			},
+		},
+		"sync errors are bounded": {
+			track: func(t *KeyTracker) {
+				for i := 0; i < MaxSyncErrors+5; i++ {
+					t.Done(fmt.Sprintf("ns/%03d", i), errors.Errorf("failed"))
+				}
+			},
+			expectErrors: func() []string {
+				var keys []string
+				for i := 5; i < MaxSyncErrors+5; i++ {
+					keys = append(keys, fmt.Sprintf("ns/%03d", i))
+				}
+				return keys
+			}(),
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var tracker *KeyTracker
+			if !mock.isNilTracker {
+				tracker = NewKeyTracker()
+			}
+			mock.track(tracker)
+			got := tracker.InFlightKeys()
+			if !reflect.DeepEqual(got, mock.expectInFlight) {
+				t.Fatalf("Expected in flight keys %v got %v", mock.expectInFlight, got)
+			}
+			errs := tracker.SyncErrors()
+			if len(errs) != len(mock.expectErrors) {
+				t.Fatalf(
+					"Expected sync errors %d got %d",
+					len(mock.expectErrors),
+					len(errs),
+				)
+			}
+			for _, key := range mock.expectErrors {
+				if _, found := errs[key]; !found {
+					t.Fatalf("Expected sync error for key %q", key)
+				}
+			}
+		})
+	}
+}
@@ -59,6 +59,23 @@ func New(
 	}, nil
 }
 
+// ForRateLimit returns a new instance of Clientset that shares the
+// discovery of this clientset but uses a dedicated client that is
+// limited to the provided qps & burst. QPS & burst of this clientset
+// are retained if the provided values are not positive.
+func (cs *Clientset) ForRateLimit(qps float32, burst int) (*Clientset, error) {
+	config := cs.config
+	if qps > 0 {
+		config.QPS = qps
+	}
+	if burst > 0 {
+		config.Burst = burst
+	}
+	// a rate limiter if set takes precedence over qps & burst
+	config.RateLimiter = nil
+	return New(&config, cs.discoveryManager)
+}
+
 // HasSynced returns true if all the discovered resources
 // are synced _i.e. are refreshed from the API server_
 func (cs *Clientset) HasSynced() bool {
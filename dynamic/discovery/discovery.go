@@ -109,6 +109,14 @@ type APIResourceDiscovery struct {
 	// unit test
 	GetAPIForAPIVersionAndResourceFn func(apiVersion, resource string) *APIResource
 
+	// GetAPIForAPIVersionAndKindFn is a functional type to get
+	// discovered API resource from provided apiVersion & kind
+	//
+	// NOTE:
+	//	This can be used to mock GetAPIForAPIVersionAndKind during
+	// unit test
+	GetAPIForAPIVersionAndKindFn func(apiVersion, kind string) *APIResource
+
 	mutex sync.RWMutex
 
 	// discovered resources anchored by **apiVersion**
@@ -178,6 +186,9 @@ func (d *APIResourceDiscovery) GetAPIForAPIVersionAndKind(
 	apiVersion string,
 	kind string,
 ) *APIResource {
+	if d.GetAPIForAPIVersionAndKindFn != nil {
+		return d.GetAPIForAPIVersionAndKindFn(apiVersion, kind)
+	}
 	d.mutex.RLock()
 	defer d.mutex.RUnlock()
 
@@ -18,6 +18,7 @@ package informer
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -33,6 +34,10 @@ type SharedInformerFactory struct {
 	clientset     *dynamicclientset.Clientset
 	defaultResync time.Duration
 
+	// namespaces the informers of namespaced resources are
+	// restricted to; informers are cluster wide if this is empty
+	namespaces []string
+
 	mutex           sync.Mutex
 	refCount        map[string]int
 	sharedInformers map[string]*sharedResourceInformer
@@ -40,13 +45,18 @@ type SharedInformerFactory struct {
 
 // NewSharedInformerFactory creates a new factory for shared, dynamic informers.
 // Usually there is only one of these for the whole process, created in main().
+//
+// Informers of namespaced resources are restricted to the provided namespaces,
+// if any. This lets metac run with namespaced Roles instead of ClusterRoles.
 func NewSharedInformerFactory(
 	clientset *dynamicclientset.Clientset,
 	defaultResync time.Duration,
+	namespaces ...string,
 ) *SharedInformerFactory {
 	return &SharedInformerFactory{
 		clientset:       clientset,
 		defaultResync:   defaultResync,
+		namespaces:      dedupeNamespaces(namespaces),
 		refCount:        make(map[string]int),
 		sharedInformers: make(map[string]*sharedResourceInformer),
 	}
@@ -61,21 +71,117 @@ func NewSharedInformerFactory(
 // Shared informers that become unused will be stopped to minimize our load on
 // the API server.
 func (f *SharedInformerFactory) GetOrCreate(apiVersion, resource string) (*ResourceInformer, error) {
+	return f.GetOrCreateForNamespaces(apiVersion, resource, nil)
+}
+
+// GetOrCreateForNamespaces returns a dynamic informer and lister for the
+// given resource restricted to the provided namespaces. The namespaces of
+// this factory are used if no namespaces are provided. The provided
+// namespaces must be a subset of the namespaces of this factory, if any.
+//
+// Namespaces are ignored if the resource is cluster scoped.
+func (f *SharedInformerFactory) GetOrCreateForNamespaces(
+	apiVersion string,
+	resource string,
+	namespaces []string,
+) (*ResourceInformer, error) {
+	namespaces, err := resolveNamespaces(f.namespaces, namespaces)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"Failed to subscribe shared informer %v: %v",
+			resourceKey(apiVersion, resource),
+			err,
+		)
+	}
+	if len(namespaces) != 0 {
+		client, err := f.clientset.GetClientForAPIVersionAndResource(apiVersion, resource)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"Failed to subscribe shared informer %v: %v",
+				resourceKey(apiVersion, resource),
+				err,
+			)
+		}
+		if !client.Namespaced {
+			// cluster scoped resources can't be restricted
+			namespaces = nil
+		}
+	}
+
+	if len(namespaces) == 0 {
+		// cluster wide informer
+		namespaces = []string{""}
+	}
+	sharedInformers, err := f.getOrCreateForNamespaces(apiVersion, resource, namespaces)
+	if err != nil {
+		// unsubscribe from the ones that were subscribed
+		for _, sri := range sharedInformers {
+			sri.close()
+		}
+		return nil, err
+	}
+	return newResourceInformer(sharedInformers...), nil
+}
+
+// ResolveNamespaces returns the namespaces that the informers of
+// namespaced resources are restricted to given the requested
+// namespaces. The returned namespaces are empty if these informers
+// are cluster wide. An error is returned if any of the requested
+// namespaces is not one of the namespaces of this factory.
+func (f *SharedInformerFactory) ResolveNamespaces(requested []string) ([]string, error) {
+	namespaces, err := resolveNamespaces(f.namespaces, requested)
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), namespaces...), nil
+}
+
+// getOrCreateForNamespaces returns the shared informers for the given
+// resource, one per provided namespace. The shared informers that were
+// subscribed to are returned along with the error, if any.
+func (f *SharedInformerFactory) getOrCreateForNamespaces(
+	apiVersion string,
+	resource string,
+	namespaces []string,
+) ([]*sharedResourceInformer, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
+	var sharedInformers []*sharedResourceInformer
+	for _, namespace := range namespaces {
+		sharedInformer, err := f.getOrCreate(apiVersion, resource, namespace)
+		if err != nil {
+			return sharedInformers, err
+		}
+		sharedInformers = append(sharedInformers, sharedInformer)
+	}
+	return sharedInformers, nil
+}
+
+// getOrCreate returns the shared informer for the given resource in the
+// given namespace after incrementing its ref count. The shared informer is
+// cluster wide if namespace is empty.
+//
+// NOTE:
+//	This must be invoked with the factory mutex held
+func (f *SharedInformerFactory) getOrCreate(
+	apiVersion string,
+	resource string,
+	namespace string,
+) (*sharedResourceInformer, error) {
+	key := namespacedResourceKey(apiVersion, resource, namespace)
+	desc := describeResource(apiVersion, resource, namespace)
+
 	// Return existing informer if there is one.
-	key := resourceKey(apiVersion, resource)
 	if sharedInformer, ok := f.sharedInformers[key]; ok {
 		count := f.refCount[key] + 1
 		f.refCount[key] = count
 		glog.V(4).Infof(
-			"Subscribed to shared informer for %v in %v (total subscribers now %v)",
-			resource,
-			apiVersion,
+			"Subscribed to shared informer for %v (total subscribers now %v)",
+			desc,
 			count,
 		)
-		return newResourceInformer(sharedInformer), nil
+		return sharedInformer, nil
 	}
 
 	// Create one if it doesn't exist.
@@ -85,6 +191,9 @@ func (f *SharedInformerFactory) GetOrCreate(apiVersion, resource string) (*Resou
 			"Failed to subscribe shared informer %v: %v", key, err,
 		)
 	}
+	if namespace != "" {
+		client = client.Namespace(namespace)
+	}
 	stopCh := make(chan struct{})
 
 	// closeFn is called by users of the shared informer (via Close())
@@ -97,9 +206,8 @@ func (f *SharedInformerFactory) GetOrCreate(apiVersion, resource string) (*Resou
 
 		count := f.refCount[key] - 1
 		glog.V(4).Infof(
-			"Unsubscribed from shared informer %v in %v (total subscribers now %v)",
-			resource,
-			apiVersion,
+			"Unsubscribed from shared informer %v (total subscribers now %v)",
+			desc,
 			count,
 		)
 
@@ -111,16 +219,15 @@ func (f *SharedInformerFactory) GetOrCreate(apiVersion, resource string) (*Resou
 
 		// We're the last ones using it.
 		glog.V(4).Infof(
-			"Stopping shared informer for %v in %v (no more subscribers)",
-			resource,
-			apiVersion,
+			"Stopping shared informer for %v (no more subscribers)",
+			desc,
 		)
 		close(stopCh)
 		delete(f.refCount, key)
 		delete(f.sharedInformers, key)
 	}
 
-	glog.V(4).Infof("Starting shared informer for %v in %v", resource, apiVersion)
+	glog.V(4).Infof("Starting shared informer for %v", desc)
 	sharedInformer := newSharedResourceInformer(client, f.defaultResync, closeFn)
 	f.sharedInformers[key] = sharedInformer
 	f.refCount[key] = 1
@@ -129,9 +236,85 @@ func (f *SharedInformerFactory) GetOrCreate(apiVersion, resource string) (*Resou
 	// Users should check HasSynced() before using it.
 	go sharedInformer.informer.Run(stopCh)
 
-	return newResourceInformer(sharedInformer), nil
+	return sharedInformer, nil
+}
+
+// SubscriberCounts returns the number of subscribers of every
+// shared informer anchored by its resource & api version
+func (f *SharedInformerFactory) SubscriberCounts() map[string]int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	counts := make(map[string]int, len(f.refCount))
+	for key, count := range f.refCount {
+		counts[key] = count
+	}
+	return counts
 }
 
 func resourceKey(apiVersion, resource string) string {
 	return fmt.Sprintf("%s.%s", resource, apiVersion)
 }
+
+// namespacedResourceKey returns the key of the shared informer of the
+// given resource in the given namespace
+func namespacedResourceKey(apiVersion, resource, namespace string) string {
+	if namespace == "" {
+		return resourceKey(apiVersion, resource)
+	}
+	return fmt.Sprintf("%s/%s", namespace, resourceKey(apiVersion, resource))
+}
+
+// describeResource returns a human readable description of the given
+// resource in the given namespace
+func describeResource(apiVersion, resource, namespace string) string {
+	if namespace == "" {
+		return fmt.Sprintf("%v in %v", resource, apiVersion)
+	}
+	return fmt.Sprintf("%v in %v in namespace %v", resource, apiVersion, namespace)
+}
+
+// dedupeNamespaces returns the sorted namespaces without duplicates
+// or empty values
+func dedupeNamespaces(namespaces []string) []string {
+	found := map[string]bool{}
+	var deduped []string
+	for _, ns := range namespaces {
+		if ns == "" || found[ns] {
+			continue
+		}
+		found[ns] = true
+		deduped = append(deduped, ns)
+	}
+	sort.Strings(deduped)
+	return deduped
+}
+
+// resolveNamespaces returns the namespaces an informer is restricted to
+// given the allowed & requested namespaces. Allowed namespaces are used
+// if none are requested. An error is returned if any of the requested
+// namespaces is not allowed. Informer is not restricted if the returned
+// namespaces are empty.
+func resolveNamespaces(allowed, requested []string) ([]string, error) {
+	requested = dedupeNamespaces(requested)
+	if len(requested) == 0 {
+		return allowed, nil
+	}
+	if len(allowed) == 0 {
+		return requested, nil
+	}
+	isAllowed := map[string]bool{}
+	for _, ns := range allowed {
+		isAllowed[ns] = true
+	}
+	for _, ns := range requested {
+		if !isAllowed[ns] {
+			return nil, fmt.Errorf(
+				"Namespace %q is not one of the allowed namespaces %v",
+				ns,
+				allowed,
+			)
+		}
+	}
+	return requested, nil
+}
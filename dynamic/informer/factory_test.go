@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package informer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolveNamespaces(t *testing.T) {
+	var tests = map[string]struct {
+		allowed   []string
+		requested []string
+		expect    []string
+		isErr     bool
+	}{
+		"nothing allowed & nothing requested": {},
+		"nothing requested": {
+			allowed: []string{"ns1", "ns2"},
+			expect:  []string{"ns1", "ns2"},
+		},
+		"nothing allowed": {
+			requested: []string{"ns2", "ns1", "ns2", ""},
+			expect:    []string{"ns1", "ns2"},
+		},
+		"requested is a subset of allowed": {
+			allowed:   []string{"ns1", "ns2"},
+			requested: []string{"ns2"},
+			expect:    []string{"ns2"},
+		},
+		"requested is not a subset of allowed": {
+			allowed:   []string{"ns1", "ns2"},
+			requested: []string{"ns2", "ns3"},
+			isErr:     true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := resolveNamespaces(mock.allowed, mock.requested)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %v", err)
+			}
+			if !reflect.DeepEqual(got, mock.expect) {
+				t.Fatalf("Expected namespaces %v got %v", mock.expect, got)
+			}
+		})
+	}
+}
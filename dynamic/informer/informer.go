@@ -25,6 +25,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/tools/cache"
 
@@ -57,18 +58,36 @@ type SharedIndexInformer interface {
 // When you're done with a ResourceInformer, you should call Close() on it.
 // Once all ResourceInformers for a shared informer are closed, the shared
 // informer is stopped.
+//
+// A ResourceInformer that is restricted to a set of namespaces subscribes
+// to one shared informer per namespace.
 type ResourceInformer struct {
-	sharedResourceInformer *sharedResourceInformer
-	informerWrapper        *informerWrapper
+	sharedResourceInformers []*sharedResourceInformer
+	informer                SharedIndexInformer
+	lister                  *dynamiclister.Lister
 }
 
-func newResourceInformer(sri *sharedResourceInformer) *ResourceInformer {
+func newResourceInformer(sris ...*sharedResourceInformer) *ResourceInformer {
+	if len(sris) == 1 {
+		return &ResourceInformer{
+			sharedResourceInformers: sris,
+			informer:                newInformerWrapper(sris[0]),
+			lister:                  sris[0].lister,
+		}
+	}
+	var wrappers []*informerWrapper
+	var indexers []cache.Indexer
+	for _, sri := range sris {
+		wrappers = append(wrappers, newInformerWrapper(sri))
+		indexers = append(indexers, sri.informer.GetIndexer())
+	}
 	return &ResourceInformer{
-		sharedResourceInformer: sri,
-		informerWrapper: &informerWrapper{
-			SharedIndexInformer:    sri.informer,
-			sharedResourceInformer: sri,
+		sharedResourceInformers: sris,
+		informer: &multiInformerWrapper{
+			informerWrapper: wrappers[0],
+			wrappers:        wrappers,
 		},
+		lister: dynamiclister.NewForIndexers(sris[0].groupResource, indexers...),
 	}
 }
 
@@ -77,13 +96,13 @@ func newResourceInformer(sri *sharedResourceInformer) *ResourceInformer {
 // arrange to call RemoveEventHandlers() when you want to stop
 // receiving events.
 func (ri *ResourceInformer) Informer() SharedIndexInformer {
-	return ri.informerWrapper
+	return ri.informer
 }
 
 // Lister returns a shared, dynamic lister that's analogous to the
 // static listers generated for static types.
 func (ri *ResourceInformer) Lister() *dynamiclister.Lister {
-	return ri.sharedResourceInformer.lister
+	return ri.lister
 }
 
 // Close marks this ResourceInformer as unused, allowing the underlying
@@ -91,8 +110,10 @@ func (ri *ResourceInformer) Lister() *dynamiclister.Lister {
 // You should call this when you no longer need the informer, so the watches
 // and relists can be stopped.
 func (ri *ResourceInformer) Close() {
-	// Decrement the reference count for the sharedResourceInformer.
-	ri.sharedResourceInformer.close()
+	// Decrement the reference count for the sharedResourceInformers.
+	for _, sri := range ri.sharedResourceInformers {
+		sri.close()
+	}
 }
 
 // sharedResourceInformer is the actual, single informer that's shared by
@@ -102,6 +123,8 @@ type sharedResourceInformer struct {
 	informer cache.SharedIndexInformer
 	// lister to the specific API resource
 	lister *dynamiclister.Lister
+	// group resource of the specific API resource
+	groupResource schema.GroupResource
 
 	defaultResyncPeriod time.Duration
 
@@ -125,7 +148,8 @@ func newSharedResourceInformer(
 		&unstructured.Unstructured{},
 		defaultResyncPeriod,
 		cache.Indexers{
-			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+			cache.NamespaceIndex:   cache.MetaNamespaceIndexFunc,
+			dynamiclister.UIDIndex: dynamiclister.UIDIndexFunc,
 		},
 	)
 	sri := &sharedResourceInformer{
@@ -133,7 +157,8 @@ func newSharedResourceInformer(
 		informer:            informer,
 		defaultResyncPeriod: defaultResyncPeriod,
 
-		lister: dynamiclister.New(client.GetGroupResource(), informer.GetIndexer()),
+		lister:        dynamiclister.New(client.GetGroupResource(), informer.GetIndexer()),
+		groupResource: client.GetGroupResource(),
 	}
 	sri.eventHandlers = newSharedEventHandler(sri.lister, defaultResyncPeriod)
 	informer.AddEventHandler(sri.eventHandlers)
@@ -306,6 +331,13 @@ type informerWrapper struct {
 	sharedResourceInformer *sharedResourceInformer
 }
 
+func newInformerWrapper(sri *sharedResourceInformer) *informerWrapper {
+	return &informerWrapper{
+		SharedIndexInformer:    sri.informer,
+		sharedResourceInformer: sri,
+	}
+}
+
 func (iw *informerWrapper) AddEventHandler(handler cache.ResourceEventHandler) {
 	iw.sharedResourceInformer.eventHandlers.addHandler(iw, handler, iw.sharedResourceInformer.defaultResyncPeriod)
 }
@@ -317,3 +349,44 @@ func (iw *informerWrapper) AddEventHandlerWithResyncPeriod(handler cache.Resourc
 func (iw *informerWrapper) RemoveEventHandlers() {
 	iw.sharedResourceInformer.eventHandlers.removeHandlers(iw)
 }
+
+// multiInformerWrapper fans out the event handlers to the informers of
+// all the namespaces a ResourceInformer is restricted to.
+//
+// NOTE:
+//	GetStore(), GetIndexer() & GetController() refer to the informer of
+// the first namespace only. Use the ResourceInformer's Lister() to read
+// the resources across all the namespaces.
+type multiInformerWrapper struct {
+	*informerWrapper
+	wrappers []*informerWrapper
+}
+
+func (mw *multiInformerWrapper) AddEventHandler(handler cache.ResourceEventHandler) {
+	for _, iw := range mw.wrappers {
+		iw.AddEventHandler(handler)
+	}
+}
+
+func (mw *multiInformerWrapper) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) {
+	for _, iw := range mw.wrappers {
+		iw.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	}
+}
+
+func (mw *multiInformerWrapper) RemoveEventHandlers() {
+	for _, iw := range mw.wrappers {
+		iw.RemoveEventHandlers()
+	}
+}
+
+// HasSynced returns true if the informers of all the namespaces
+// have synced
+func (mw *multiInformerWrapper) HasSynced() bool {
+	for _, iw := range mw.wrappers {
+		if !iw.HasSynced() {
+			return false
+		}
+	}
+	return true
+}
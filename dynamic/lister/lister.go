@@ -20,33 +20,67 @@ import (
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/tools/cache"
 )
 
+// UIDIndex is the name of the index that anchors the API resources
+// by their UID
+const UIDIndex = "uid"
+
+// UIDIndexFunc is the index function that indexes the API resources
+// by their UID
+func UIDIndexFunc(obj interface{}) ([]string, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, err
+	}
+	return []string{string(accessor.GetUID())}, nil
+}
+
 // Lister manages listing API resources
+//
+// NOTE:
+//	Lister may be backed by more than one indexer e.g. one indexer
+// per namespace. These indexers are expected to hold disjoint sets
+// of resources.
 type Lister struct {
-	indexer       cache.Indexer
+	indexers      []cache.Indexer
 	groupResource schema.GroupResource
 }
 
 // New returns a new instance of Lister
 func New(groupResource schema.GroupResource, indexer cache.Indexer) *Lister {
+	return NewForIndexers(groupResource, indexer)
+}
+
+// NewForIndexers returns a new instance of Lister that lists API
+// resources across all the provided indexers
+func NewForIndexers(
+	groupResource schema.GroupResource,
+	indexers ...cache.Indexer,
+) *Lister {
 	return &Lister{
 		groupResource: groupResource,
-		indexer:       indexer,
+		indexers:      indexers,
 	}
 }
 
 // List returns a list of API resources based on the provided
 // selector
 func (l *Lister) List(selector labels.Selector) (ret []*unstructured.Unstructured, err error) {
-	err = cache.ListAll(l.indexer, selector, func(obj interface{}) {
-		ret = append(ret, obj.(*unstructured.Unstructured))
-	})
-	return ret, err
+	for _, indexer := range l.indexers {
+		err = cache.ListAll(indexer, selector, func(obj interface{}) {
+			ret = append(ret, obj.(*unstructured.Unstructured))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
 }
 
 // ListNamespace returns a list of API resources based on the
@@ -55,10 +89,15 @@ func (l *Lister) ListNamespace(
 	namespace string,
 	selector labels.Selector,
 ) (ret []*unstructured.Unstructured, err error) {
-	err = cache.ListAllByNamespace(l.indexer, namespace, selector, func(obj interface{}) {
-		ret = append(ret, obj.(*unstructured.Unstructured))
-	})
-	return ret, err
+	for _, indexer := range l.indexers {
+		err = cache.ListAllByNamespace(indexer, namespace, selector, func(obj interface{}) {
+			ret = append(ret, obj.(*unstructured.Unstructured))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
 }
 
 // Get returns the specific instance of API resource as an unstructured
@@ -68,12 +107,31 @@ func (l *Lister) Get(namespace, name string) (*unstructured.Unstructured, error)
 	if namespace != "" {
 		key = fmt.Sprintf("%s/%s", namespace, name)
 	}
-	obj, exists, err := l.indexer.GetByKey(key)
-	if err != nil {
-		return nil, err
+	for _, indexer := range l.indexers {
+		obj, exists, err := indexer.GetByKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return obj.(*unstructured.Unstructured), nil
+		}
 	}
-	if !exists {
-		return nil, errors.NewNotFound(l.groupResource, name)
+	return nil, errors.NewNotFound(l.groupResource, name)
+}
+
+// GetByUID returns the API resource with the provided UID
+//
+// NOTE:
+//	Indexers of this lister must be indexed by UIDIndex
+func (l *Lister) GetByUID(uid string) (*unstructured.Unstructured, error) {
+	for _, indexer := range l.indexers {
+		objs, err := indexer.ByIndex(UIDIndex, uid)
+		if err != nil {
+			return nil, err
+		}
+		if len(objs) != 0 {
+			return objs[0].(*unstructured.Unstructured), nil
+		}
 	}
-	return obj.(*unstructured.Unstructured), nil
+	return nil, errors.NewNotFound(l.groupResource, uid)
 }
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lister
+
+import (
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+)
+
+func newIndexer(t *testing.T, namespace string, names ...string) cache.Indexer {
+	indexer := cache.NewIndexer(
+		cache.MetaNamespaceKeyFunc,
+		cache.Indexers{
+			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+			UIDIndex:             UIDIndexFunc,
+		},
+	)
+	for _, name := range names {
+		obj := &unstructured.Unstructured{}
+		obj.SetNamespace(namespace)
+		obj.SetName(name)
+		obj.SetUID(types.UID(namespace + "-" + name))
+		err := indexer.Add(obj)
+		if err != nil {
+			t.Fatalf("Can't add %s/%s to indexer: %v", namespace, name, err)
+		}
+	}
+	return indexer
+}
+
+func TestListerForIndexers(t *testing.T) {
+	lister := NewForIndexers(
+		schema.GroupResource{Resource: "pods"},
+		newIndexer(t, "ns1", "a", "b"),
+		newIndexer(t, "ns2", "c"),
+	)
+
+	all, err := lister.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 objects got %d", len(all))
+	}
+
+	inNS2, err := lister.ListNamespace("ns2", labels.Everything())
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(inNS2) != 1 || inNS2[0].GetName() != "c" {
+		t.Fatalf("Expected object ns2/c got %v", inNS2)
+	}
+
+	got, err := lister.Get("ns2", "c")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if got.GetNamespace() != "ns2" || got.GetName() != "c" {
+		t.Fatalf(
+			"Expected object ns2/c got %s/%s",
+			got.GetNamespace(),
+			got.GetName(),
+		)
+	}
+
+	_, err = lister.Get("ns1", "c")
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("Expected not found error got %v", err)
+	}
+
+	got, err = lister.GetByUID("ns2-c")
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if got.GetNamespace() != "ns2" || got.GetName() != "c" {
+		t.Fatalf(
+			"Expected object ns2/c got %s/%s",
+			got.GetNamespace(),
+			got.GetName(),
+		)
+	}
+
+	_, err = lister.GetByUID("ns1-c")
+	if !apierrors.IsNotFound(err) {
+		t.Fatalf("Expected not found error got %v", err)
+	}
+}
@@ -0,0 +1,315 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Component is the source component of events emitted by metac
+const Component = "metac"
+
+// Reasons of events emitted by metac
+const (
+	// ReasonHookFailed is the reason of the event emitted when a
+	// hook invocation fails
+	ReasonHookFailed = "HookFailed"
+
+	// ReasonInvalidHookResponse is the reason of the event emitted
+	// when a hook response fails validation
+	ReasonInvalidHookResponse = "InvalidHookResponse"
+
+	// ReasonCreated is the reason of the event emitted when a
+	// resource is created
+	ReasonCreated = "Created"
+
+	// ReasonCreateFailed is the reason of the event emitted when
+	// creation of a resource fails
+	ReasonCreateFailed = "CreateFailed"
+
+	// ReasonUpdated is the reason of the event emitted when a
+	// resource is updated
+	ReasonUpdated = "Updated"
+
+	// ReasonUpdateFailed is the reason of the event emitted when
+	// update of a resource fails
+	ReasonUpdateFailed = "UpdateFailed"
+
+	// ReasonDeleted is the reason of the event emitted when a
+	// resource is deleted
+	ReasonDeleted = "Deleted"
+
+	// ReasonDeleteFailed is the reason of the event emitted when
+	// deletion of a resource fails
+	ReasonDeleteFailed = "DeleteFailed"
+
+	// ReasonFinalizerAdded is the reason of the event emitted when
+	// metac adds its finalizer to a resource
+	ReasonFinalizerAdded = "FinalizerAdded"
+
+	// ReasonFinalizerRemoved is the reason of the event emitted
+	// when metac removes its finalizer from a resource
+	ReasonFinalizerRemoved = "FinalizerRemoved"
+
+	// ReasonFinalizerFailed is the reason of the event emitted
+	// when metac fails to add or remove its finalizer
+	ReasonFinalizerFailed = "FinalizerFailed"
+
+	// ReasonRolloutProgressing is the reason of the event emitted
+	// when a rolling update moves a child to the latest revision
+	ReasonRolloutProgressing = "RolloutProgressing"
+
+	// ReasonChildNotReady is the reason of the event emitted when
+	// the postUpdateChild hook reports an updated child as not ready
+	ReasonChildNotReady = "ChildNotReady"
+
+	// ReasonDryRun is the reason of the event emitted when a
+	// create, update or delete is skipped due to dry run
+	ReasonDryRun = "DryRun"
+
+	// ReasonConfigFailed is the reason of the event emitted when
+	// a reloaded config fails to start its controller
+	ReasonConfigFailed = "ConfigFailed"
+)
+
+// Defaults used to rate limit & aggregate the events
+//
+// NOTE:
+//	Events are rate limited per source & involved object via a
+// token bucket. Similar events are aggregated into a single event
+// once MaxEvents similar events are seen within the interval.
+const (
+	// DefaultBurst is the burst of events per involved object
+	DefaultBurst = 25
+
+	// DefaultQPS is the refill rate of events per involved object
+	// i.e. one event every 5 minutes after the burst is consumed
+	DefaultQPS float32 = 1. / 300.
+
+	// DefaultMaxEvents is the number of similar events after which
+	// these events get aggregated
+	DefaultMaxEvents = 10
+
+	// DefaultMaxIntervalInSeconds is the interval within which
+	// similar events are aggregated
+	DefaultMaxIntervalInSeconds = 600
+
+	// DefaultNormalEventInterval is the interval within which an
+	// identical Normal event against the same object is emitted
+	// only once
+	DefaultNormalEventInterval = 5 * time.Minute
+
+	// maxThrottledEvents is the maximum number of Normal events
+	// remembered to throttle the identical ones
+	maxThrottledEvents = 4096
+)
+
+// NewRecorder returns a new event recorder that sends events
+// to kubernetes via the provided client. It returns the function
+// that stops the recorder as well.
+func NewRecorder(
+	client typedcorev1.EventsGetter,
+) (recorder record.EventRecorder, stop func()) {
+	broadcaster := record.NewBroadcasterWithCorrelatorOptions(
+		record.CorrelatorOptions{
+			BurstSize:            DefaultBurst,
+			QPS:                  DefaultQPS,
+			MaxEvents:            DefaultMaxEvents,
+			MaxIntervalInSeconds: DefaultMaxIntervalInSeconds,
+		},
+	)
+	broadcaster.StartLogging(glog.V(4).Infof)
+	broadcaster.StartRecordingToSink(
+		&typedcorev1.EventSinkImpl{
+			Interface: client.Events(""),
+		},
+	)
+	recorder = newThrottledRecorder(
+		broadcaster.NewRecorder(
+			scheme.Scheme,
+			corev1.EventSource{Component: Component},
+		),
+		DefaultNormalEventInterval,
+	)
+	return recorder, broadcaster.Shutdown
+}
+
+// throttledRecorder is an event recorder that drops the Normal
+// events that are identical to the ones emitted against the same
+// object within the interval
+//
+// NOTE:
+//	Busy controllers emit Normal events for the operations of
+// every sync. This keeps these events from flooding the events API.
+// Warning events are never dropped.
+type throttledRecorder struct {
+	record.EventRecorder
+
+	interval time.Duration
+
+	// mutex guards the emitted events
+	mutex sync.Mutex
+
+	// time at which the Normal events were last emitted anchored
+	// by the event's object, reason & message
+	emitted map[string]time.Time
+
+	// returns the current time; useful for testing
+	now func() time.Time
+}
+
+// newThrottledRecorder returns a new instance of throttledRecorder
+func newThrottledRecorder(
+	recorder record.EventRecorder,
+	interval time.Duration,
+) *throttledRecorder {
+	return &throttledRecorder{
+		EventRecorder: recorder,
+		interval:      interval,
+		emitted:       map[string]time.Time{},
+		now:           time.Now,
+	}
+}
+
+// Event implements record.EventRecorder interface
+func (r *throttledRecorder) Event(
+	obj runtime.Object,
+	eventType string,
+	reason string,
+	message string,
+) {
+	if r.isThrottled(obj, eventType, reason, message) {
+		return
+	}
+	r.EventRecorder.Event(obj, eventType, reason, message)
+}
+
+// Eventf implements record.EventRecorder interface
+func (r *throttledRecorder) Eventf(
+	obj runtime.Object,
+	eventType string,
+	reason string,
+	messageFmt string,
+	args ...interface{},
+) {
+	r.Event(obj, eventType, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// isThrottled returns true if the provided event needs to be
+// dropped since an identical one was emitted within the interval
+func (r *throttledRecorder) isThrottled(
+	obj runtime.Object,
+	eventType string,
+	reason string,
+	message string,
+) bool {
+	if eventType != corev1.EventTypeNormal {
+		return false
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	key := fmt.Sprintf(
+		"%s/%s/%s:%s:%s",
+		accessor.GetUID(),
+		accessor.GetNamespace(),
+		accessor.GetName(),
+		reason,
+		message,
+	)
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.now()
+	if last, found := r.emitted[key]; found && now.Sub(last) < r.interval {
+		return true
+	}
+	if len(r.emitted) >= maxThrottledEvents {
+		r.evictExpired(now)
+	}
+	r.emitted[key] = now
+	return false
+}
+
+// evictExpired forgets the events that were emitted before the
+// interval. All the events are forgotten if none has expired.
+//
+// NOTE:
+//	This must be invoked with the lock held
+func (r *throttledRecorder) evictExpired(now time.Time) {
+	for key, last := range r.emitted {
+		if now.Sub(last) >= r.interval {
+			delete(r.emitted, key)
+		}
+	}
+	if len(r.emitted) >= maxThrottledEvents {
+		r.emitted = map[string]time.Time{}
+	}
+}
+
+// isNil returns true if the provided object is nil or is a nil
+// pointer wrapped in the runtime.Object interface
+func isNil(obj runtime.Object) bool {
+	if obj == nil {
+		return true
+	}
+	value := reflect.ValueOf(obj)
+	return value.Kind() == reflect.Ptr && value.IsNil()
+}
+
+// Normalf emits an event of type Normal against the provided
+// object. This is a no-op if recorder or object is nil.
+func Normalf(
+	recorder record.EventRecorder,
+	obj runtime.Object,
+	reason string,
+	messageFmt string,
+	args ...interface{},
+) {
+	if recorder == nil || isNil(obj) {
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeNormal, reason, messageFmt, args...)
+}
+
+// Warningf emits an event of type Warning against the provided
+// object. This is a no-op if recorder or object is nil.
+func Warningf(
+	recorder record.EventRecorder,
+	obj runtime.Object,
+	reason string,
+	messageFmt string,
+	args ...interface{},
+) {
+	if recorder == nil || isNil(obj) {
+		return
+	}
+	recorder.Eventf(obj, corev1.EventTypeWarning, reason, messageFmt, args...)
+}
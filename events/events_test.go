@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestNormalfAndWarningf(t *testing.T) {
+	obj := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata": map[string]interface{}{
+				"name":      "test",
+				"namespace": "test",
+			},
+		},
+	}
+	var tests = map[string]struct {
+		emit   func(recorder record.EventRecorder)
+		expect string
+	}{
+		"normal event": {
+			emit: func(recorder record.EventRecorder) {
+				Normalf(recorder, obj, ReasonCreated, "Created %s", "test")
+			},
+			expect: "Normal Created Created test",
+		},
+		"warning event": {
+			emit: func(recorder record.EventRecorder) {
+				Warningf(recorder, obj, ReasonHookFailed, "Sync hook failed: %s", "err")
+			},
+			expect: "Warning HookFailed Sync hook failed: err",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			mock.emit(recorder)
+			select {
+			case got := <-recorder.Events:
+				if got != mock.expect {
+					t.Fatalf("Expected event %q got %q", mock.expect, got)
+				}
+			default:
+				t.Fatalf("Expected event %q got none", mock.expect)
+			}
+		})
+	}
+}
+
+func TestNilRecorder(t *testing.T) {
+	// must not panic
+	Normalf(nil, nil, ReasonCreated, "Created")
+	Warningf(nil, nil, ReasonHookFailed, "Hook failed")
+}
+
+func TestNilObject(t *testing.T) {
+	recorder := record.NewFakeRecorder(2)
+	var obj *unstructured.Unstructured
+	// must not panic
+	Normalf(recorder, obj, ReasonCreated, "Created")
+	Warningf(recorder, obj, ReasonHookFailed, "Hook failed")
+	if len(recorder.Events) != 0 {
+		t.Fatalf("Expected no events for nil object got %d", len(recorder.Events))
+	}
+}
+
+func TestThrottledRecorder(t *testing.T) {
+	newObj := func(name string) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion("v1")
+		obj.SetKind("Pod")
+		obj.SetNamespace("test")
+		obj.SetName(name)
+		return obj
+	}
+	now := time.Now()
+	fake := record.NewFakeRecorder(10)
+	recorder := newThrottledRecorder(fake, time.Minute)
+	recorder.now = func() time.Time {
+		return now
+	}
+
+	Normalf(recorder, newObj("a"), ReasonCreated, "Created %s", "x")
+	Normalf(recorder, newObj("a"), ReasonCreated, "Created %s", "x")
+	Normalf(recorder, newObj("a"), ReasonCreated, "Created %s", "y")
+	Normalf(recorder, newObj("b"), ReasonCreated, "Created %s", "x")
+	Warningf(recorder, newObj("a"), ReasonCreateFailed, "Failed")
+	Warningf(recorder, newObj("a"), ReasonCreateFailed, "Failed")
+	if len(fake.Events) != 5 {
+		t.Fatalf("Expected 5 events got %d", len(fake.Events))
+	}
+
+	now = now.Add(time.Minute)
+	Normalf(recorder, newObj("a"), ReasonCreated, "Created %s", "x")
+	if len(fake.Events) != 6 {
+		t.Fatalf("Expected event after interval got %d events", len(fake.Events))
+	}
+}
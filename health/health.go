@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/golang/glog"
+)
+
+// Check is a named health or readiness check
+type Check struct {
+	// Name of the checked component
+	Name string
+
+	// Check returns nil if the component is healthy or ready
+	Check func() error
+}
+
+// ReadinessReporter is implemented by the components that report
+// the readiness of their sub components e.g. a meta controller
+// reporting the readiness of its controllers
+type ReadinessReporter interface {
+	ReadinessChecks() []Check
+}
+
+// NewCheck returns a check that fails with the provided reason
+// if the given condition is false
+func NewCheck(name string, condition func() bool, reason string) Check {
+	return Check{
+		Name: name,
+		Check: func() error {
+			if !condition() {
+				return fmt.Errorf("%s", reason)
+			}
+			return nil
+		},
+	}
+}
+
+// NewHandler returns a http handler that executes the checks
+// returned by the provided function. It responds with status OK
+// if all the checks pass & with status InternalServerError
+// otherwise.
+//
+// NOTE:
+//	Result of every check is listed if the request has the
+// 'verbose' query parameter. Failed checks are always listed.
+func NewHandler(name string, checksFn func() []Check) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, isVerbose := r.URL.Query()["verbose"]
+
+		var out bytes.Buffer
+		var failed bool
+		for _, check := range checksFn() {
+			err := check.Check()
+			if err != nil {
+				failed = true
+				fmt.Fprintf(&out, "[-]%s failed: %v\n", check.Name, err)
+				continue
+			}
+			if isVerbose {
+				fmt.Fprintf(&out, "[+]%s ok\n", check.Name)
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if failed {
+			glog.V(4).Infof("%s check failed:\n%s", name, out.String())
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(&out, "%s check failed\n", name)
+			w.Write(out.Bytes())
+			return
+		}
+		if isVerbose {
+			fmt.Fprintf(&out, "%s check passed\n", name)
+			w.Write(out.Bytes())
+			return
+		}
+		fmt.Fprint(w, "ok")
+	})
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandler(t *testing.T) {
+	ready := NewCheck("ready", func() bool { return true }, "never")
+	notReady := NewCheck("not-ready", func() bool { return false }, "not synced")
+
+	var tests = map[string]struct {
+		checks       []Check
+		url          string
+		expectStatus int
+		expectBody   string
+	}{
+		"no checks": {
+			url:          "/readyz",
+			expectStatus: http.StatusOK,
+			expectBody:   "ok",
+		},
+		"all checks pass": {
+			checks:       []Check{ready},
+			url:          "/readyz",
+			expectStatus: http.StatusOK,
+			expectBody:   "ok",
+		},
+		"all checks pass verbose": {
+			checks:       []Check{ready},
+			url:          "/readyz?verbose",
+			expectStatus: http.StatusOK,
+			expectBody:   "[+]ready ok\nreadyz check passed\n",
+		},
+		"a check fails": {
+			checks:       []Check{ready, notReady},
+			url:          "/readyz",
+			expectStatus: http.StatusInternalServerError,
+			expectBody:   "[-]not-ready failed: not synced\nreadyz check failed\n",
+		},
+		"a check fails verbose": {
+			checks:       []Check{ready, notReady},
+			url:          "/readyz?verbose",
+			expectStatus: http.StatusInternalServerError,
+			expectBody:   "[+]ready ok\n[-]not-ready failed: not synced\nreadyz check failed\n",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			handler := NewHandler("readyz", func() []Check {
+				return mock.checks
+			})
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, mock.url, nil))
+			if rec.Code != mock.expectStatus {
+				t.Fatalf("Expected status %d got %d", mock.expectStatus, rec.Code)
+			}
+			if rec.Body.String() != mock.expectBody {
+				t.Fatalf("Expected body %q got %q", mock.expectBody, rec.Body.String())
+			}
+		})
+	}
+}
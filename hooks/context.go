@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package hooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/golang/glog"
+)
+
+// Types of hooks invoked by the meta controllers
+const (
+	SyncHook            = "sync"
+	FinalizeHook        = "finalize"
+	PreUpdateChildHook  = "preUpdateChild"
+	PostUpdateChildHook = "postUpdateChild"
+	CustomizeHook       = "customize"
+)
+
+// Info identifies a hook invocation
+type Info struct {
+	// Controller identifies the meta controller that invokes the
+	// hook e.g. 'GenericController "ns" / "name"'
+	Controller string
+
+	// Hook is the type of hook e.g. sync or finalize
+	Hook string
+
+	// Type is the implementation of the hook e.g. inline, webhook,
+	// grpc, etc.
+	Type string
+}
+
+// String implements Stringer interface
+func (i Info) String() string {
+	return fmt.Sprintf("%s: %s %s hook", i.Controller, i.Hook, i.Type)
+}
+
+// infoKey is the context key of Info
+type infoKey struct{}
+
+// WithInfo returns a copy of the given context that carries the
+// given hook info
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, infoKey{}, info)
+}
+
+// InfoFrom returns the hook info carried by the given context
+func InfoFrom(ctx context.Context) Info {
+	info, _ := ctx.Value(infoKey{}).(Info)
+	return info
+}
+
+// Logger logs the messages of a hook invocation prefixed with
+// the info of this invocation
+type Logger struct {
+	prefix string
+}
+
+// LoggerFrom returns the logger of the hook invocation whose info
+// is carried by the given context
+func LoggerFrom(ctx context.Context) *Logger {
+	return &Logger{prefix: InfoFrom(ctx).String()}
+}
+
+// Infof logs the given message if verbosity is at least the given
+// level
+func (l *Logger) Infof(level glog.Level, format string, args ...interface{}) {
+	if glog.V(level) {
+		glog.InfoDepth(1, l.prefix+": "+fmt.Sprintf(format, args...))
+	}
+}
+
+// Errorf logs the given message as an error
+func (l *Logger) Errorf(format string, args ...interface{}) {
+	glog.ErrorDepth(1, l.prefix+": "+fmt.Sprintf(format, args...))
+}
@@ -16,6 +16,6 @@ limitations under the License.
 
 // Package hooks will have logic corresponding to specific hook
 // implementations. For example, procedure to invoke a webhook
-// is coded in this package. Similarly, inline hook implementation
-// has its logic in this package.
+// is coded in this package. Similarly, gRPC, exec, jsonnet, starlark
+// & inline hook implementations have their logic in this package.
 package hooks
@@ -0,0 +1,217 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exechook
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"openebs.io/metac/metrics"
+)
+
+// MaxStderrSize is the maximum number of bytes of the command's
+// stderr that are captured. Older bytes are discarded.
+const MaxStderrSize = 1024
+
+// Invoker manages invocation of a local command as a hook
+type Invoker struct {
+	// name or path of the executable
+	Command string
+
+	// arguments passed to the command
+	Args []string
+
+	// environment variables in key=value format that are set in
+	// addition to the ones of this process
+	Env []string
+
+	// working directory of the command
+	WorkingDir string
+
+	// command is killed after this timeout
+	Timeout time.Duration
+}
+
+// InvokerOption is a typed function that is used
+// to build *Invoker instance
+//
+// NOTE:
+//	This follows "functional options" pattern
+type InvokerOption func(*Invoker) error
+
+// NewInvoker returns a new instance of Invoker
+// based on an optional list of InvokerOptions
+func NewInvoker(opts ...InvokerOption) (*Invoker, error) {
+	i := &Invoker{}
+	for _, o := range opts {
+		err := o(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if i.Command == "" {
+		return nil, errors.Errorf("%s: Command can't be empty", i)
+	}
+	return i, nil
+}
+
+// String implements Stringer interface
+func (i *Invoker) String() string {
+	return fmt.Sprintf(
+		"Exec Invoker: Command=%s: Timeout=%s",
+		i.Command,
+		i.Timeout,
+	)
+}
+
+// Invoke this command by writing the given request to its stdin
+// and fill up the given response from its stdout
+func (i *Invoker) Invoke(request, response interface{}) error {
+	return i.InvokeContext(context.Background(), request, response)
+}
+
+// InvokeContext invokes this command by writing the given request
+// to its stdin and fills up the given response from its stdout. The
+// command is killed once the given context is done.
+func (i *Invoker) InvokeContext(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	start := time.Now()
+	err := i.invoke(ctx, request, response)
+	metrics.RecordHook(i.Command, start, err)
+	return err
+}
+
+// invoke this command by writing the given request to its stdin
+// and fill up the given response from its stdout
+func (i *Invoker) invoke(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to marshal", i)
+	}
+	glog.V(8).Infof("%s: Will invoke %s", i, reqBody)
+
+	var stdout bytes.Buffer
+	stderr := &tailBuffer{max: MaxStderrSize}
+	cmd := exec.Command(i.Command, i.Args...)
+	cmd.Dir = i.WorkingDir
+	cmd.Env = append(os.Environ(), i.Env...)
+	cmd.Stdin = bytes.NewReader(reqBody)
+	cmd.Stdout = &stdout
+	cmd.Stderr = stderr
+	setProcessGroup(cmd)
+
+	err = cmd.Start()
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to start", i)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	if i.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.Timeout)
+		defer cancel()
+	}
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		// NOTE:
+		//	Process group is killed since the command's children
+		// may otherwise hold its stdout open & block the wait
+		killProcessGroup(cmd)
+		<-done
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Errorf(
+				"%s: Timed out: Stderr %q",
+				i,
+				stderr.String(),
+			)
+		}
+		return errors.Wrapf(
+			ctx.Err(),
+			"%s: Failed to invoke: Stderr %q",
+			i,
+			stderr.String(),
+		)
+	}
+	if stderr.Len() != 0 {
+		glog.V(4).Infof("%s: Stderr %q", i, stderr.String())
+	}
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"%s: Failed to invoke: Stderr %q",
+			i,
+			stderr.String(),
+		)
+	}
+	glog.V(8).Infof("%s: Got response %q", i, stdout.Bytes())
+
+	err = json.Unmarshal(stdout.Bytes(), response)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to unmarshal response", i)
+	}
+	glog.V(8).Infof("%s: Invoked successfully", i)
+	return nil
+}
+
+// tailBuffer retains the last max bytes written to it
+type tailBuffer struct {
+	max       int
+	buf       []byte
+	truncated bool
+}
+
+// Write implements io.Writer interface
+func (b *tailBuffer) Write(p []byte) (int, error) {
+	b.buf = append(b.buf, p...)
+	if len(b.buf) > b.max {
+		b.buf = b.buf[len(b.buf)-b.max:]
+		b.truncated = true
+	}
+	return len(p), nil
+}
+
+// Len returns the number of bytes retained
+func (b *tailBuffer) Len() int {
+	return len(b.buf)
+}
+
+// String returns the retained bytes without the trailing new
+// lines
+func (b *tailBuffer) String() string {
+	s := strings.TrimRight(string(b.buf), "\n")
+	if b.truncated {
+		return "..." + s
+	}
+	return s
+}
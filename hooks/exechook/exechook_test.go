@@ -0,0 +1,148 @@
+// +build !windows
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exechook
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInvoke(t *testing.T) {
+	var tests = map[string]struct {
+		script       string
+		env          []string
+		workingDir   string
+		timeout      time.Duration
+		expect       map[string]string
+		isErr        bool
+		expectErrMsg string
+	}{
+		"request is echoed": {
+			script: `cat`,
+			expect: map[string]string{"name": "test"},
+		},
+		"env is set": {
+			script: `echo "{\"name\":\"$HOOK_NAME\"}"`,
+			env:    []string{"HOOK_NAME=env"},
+			expect: map[string]string{"name": "env"},
+		},
+		"working dir is set": {
+			script:     `echo "{\"name\":\"$(pwd)\"}"`,
+			workingDir: "/",
+			expect:     map[string]string{"name": "/"},
+		},
+		"stderr is logged on success": {
+			script: `echo "warning" >&2; cat`,
+			expect: map[string]string{"name": "test"},
+		},
+		"non zero exit": {
+			script:       `echo "bad request" >&2; exit 1`,
+			isErr:        true,
+			expectErrMsg: "bad request",
+		},
+		"invalid response": {
+			script: `echo "junk"`,
+			isErr:  true,
+		},
+		"timeout": {
+			script:       `echo "stuck" >&2; sleep 10`,
+			timeout:      100 * time.Millisecond,
+			isErr:        true,
+			expectErrMsg: "stuck",
+		},
+		"timeout with background child": {
+			script:  `sleep 10 & sleep 10`,
+			timeout: 100 * time.Millisecond,
+			isErr:   true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := mock.timeout
+			if timeout == 0 {
+				timeout = 5 * time.Second
+			}
+			i := &Invoker{
+				Command:    "/bin/sh",
+				Args:       []string{"-c", mock.script},
+				Env:        mock.env,
+				WorkingDir: mock.workingDir,
+				Timeout:    timeout,
+			}
+			start := time.Now()
+			var resp map[string]string
+			err := i.Invoke(map[string]string{"name": "test"}, &resp)
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("Expected invocation to end within timeout")
+			}
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				if !strings.Contains(err.Error(), mock.expectErrMsg) {
+					t.Fatalf(
+						"Expected error with %q got %q",
+						mock.expectErrMsg,
+						err.Error(),
+					)
+				}
+				return
+			}
+			if resp["name"] != mock.expect["name"] {
+				t.Fatalf("Expected response %v got %v", mock.expect, resp)
+			}
+		})
+	}
+}
+
+func TestTailBuffer(t *testing.T) {
+	var tests = map[string]struct {
+		writes []string
+		expect string
+	}{
+		"no writes": {},
+		"within max": {
+			writes: []string{"abc", "def\n"},
+			expect: "abcdef",
+		},
+		"beyond max": {
+			writes: []string{"abcdef", "ghij"},
+			expect: "...cdefghij",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			b := &tailBuffer{max: 8}
+			for _, w := range mock.writes {
+				b.Write([]byte(w))
+			}
+			if b.String() != mock.expect {
+				t.Fatalf("Expected %q got %q", mock.expect, b.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+// +build !windows
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exechook
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup runs the provided command in a process group of
+// its own
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the provided command along with all the
+// processes it started
+func killProcessGroup(cmd *exec.Cmd) {
+	// negative pid refers to the process group
+	syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
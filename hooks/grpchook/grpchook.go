@@ -0,0 +1,439 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpchook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/encoding/gzip"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"openebs.io/metac/metrics"
+)
+
+const (
+	// DefaultMaxMessageSize is the default maximum size in bytes
+	// of the request & the response messages
+	DefaultMaxMessageSize = 64 * 1024 * 1024
+
+	// CompressionGzip compresses the messages via gzip
+	CompressionGzip = gzip.Name
+)
+
+// Invoker manages invocation of gRPC hook
+type Invoker struct {
+	// address of the gRPC server in host:port format
+	Address string
+
+	// full name of the method that gets invoked. This overrides
+	// the method decided by the request.
+	Method string
+
+	// gRPC invocation timeout
+	Timeout time.Duration
+
+	// connects to the gRPC server over TLS if true
+	TLS bool
+
+	// PEM encoded CA bundle to verify the gRPC server's
+	// certificate
+	CABundle []byte
+
+	// host name used to verify the gRPC server's certificate
+	ServerName string
+
+	// CredentialsFn returns the latest client credentials before
+	// every invocation
+	CredentialsFn func() (*Credentials, error)
+
+	// maximum size in bytes of the request & the response
+	MaxMessageSize int
+
+	// name of the compressor used to compress the messages
+	Compression string
+
+	// guards the lazily built connection & the current client
+	// certificate
+	mutex sync.Mutex
+
+	// connection reused across invocations
+	conn *grpc.ClientConn
+
+	// number of invocations that are using the connection
+	inflight int
+
+	// closed is set once this invoker is closed
+	closed bool
+
+	// current client certificate along with its PEM encoded
+	// certificate & key
+	clientCert    *tls.Certificate
+	clientCertPEM []byte
+	clientKeyPEM  []byte
+}
+
+// Credentials are used to authenticate with the gRPC server
+type Credentials struct {
+	// PEM encoded client certificate & key
+	ClientCert []byte
+	ClientKey  []byte
+
+	// bearer token
+	BearerToken string
+}
+
+// InvokerOption is a typed function that is used
+// to build *Invoker instance
+//
+// NOTE:
+//	This follows "functional options" pattern
+type InvokerOption func(*Invoker) error
+
+// NewInvoker returns a new instance of Invoker
+// based on an optional list of InvokerOptions
+func NewInvoker(opts ...InvokerOption) (*Invoker, error) {
+	i := &Invoker{
+		MaxMessageSize: DefaultMaxMessageSize,
+	}
+	for _, o := range opts {
+		err := o(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if i.Address == "" {
+		return nil, errors.Errorf("%s: Address can't be empty", i)
+	}
+	return i, nil
+}
+
+// String implements Stringer interface
+func (i *Invoker) String() string {
+	return fmt.Sprintf(
+		"gRPC Invoker: Address=%s: Timeout=%s",
+		i.Address,
+		i.Timeout,
+	)
+}
+
+// Invoke this gRPC hook by passing the given request
+// and fill up the given response with the hook's response
+func (i *Invoker) Invoke(request, response interface{}) error {
+	return i.InvokeContext(context.Background(), request, response)
+}
+
+// InvokeContext invokes this gRPC hook by passing the given request
+// and fills up the given response with the hook's response. The
+// invocation is cancelled once the given context is done.
+func (i *Invoker) InvokeContext(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	method, err := i.getMethod(request)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = i.invoke(ctx, method, request, response)
+	metrics.RecordHook(i.Address+method, start, err)
+	return err
+}
+
+// getMethod returns the full name of the method that gets
+// invoked for the provided request
+func (i *Invoker) getMethod(request interface{}) (string, error) {
+	if i.Method != "" {
+		return i.Method, nil
+	}
+	if getter, ok := request.(MethodGetter); ok && getter.GRPCMethod() != "" {
+		return getter.GRPCMethod(), nil
+	}
+	return "", errors.Errorf(
+		"%s: Can't decide method for %T: Specify method",
+		i,
+		request,
+	)
+}
+
+// invoke the provided method by passing the given request and
+// fill up the given response with the hook's response
+func (i *Invoker) invoke(
+	ctx context.Context,
+	method string,
+	request, response interface{},
+) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to marshal", i)
+	}
+	glog.V(8).Infof("%s: Will invoke %s: %s", i, method, payload)
+
+	creds, err := i.getCredentials()
+	if err != nil {
+		return err
+	}
+	err = i.setClientCertificate(creds)
+	if err != nil {
+		return err
+	}
+	conn, err := i.getConn()
+	if err != nil {
+		return err
+	}
+	defer i.releaseConn()
+
+	var callOpts []grpc.CallOption
+	if creds.BearerToken != "" {
+		callOpts = append(
+			callOpts,
+			grpc.PerRPCCredentials(bearerToken(creds.BearerToken)),
+		)
+	}
+	if i.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.Timeout)
+		defer cancel()
+	}
+	resp := &Response{}
+	err = conn.Invoke(ctx, method, &Request{Payload: payload}, resp, callOpts...)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to invoke %s", i, method)
+	}
+	glog.V(8).Infof("%s: Got response %q", i, resp.Payload)
+
+	err = json.Unmarshal(resp.Payload, response)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to unmarshal response", i)
+	}
+	glog.V(8).Infof("%s: Invoked %s successfully", i, method)
+	return nil
+}
+
+// getCredentials returns the credentials used to authenticate
+// with the gRPC server
+func (i *Invoker) getCredentials() (*Credentials, error) {
+	if i.CredentialsFn == nil {
+		return &Credentials{}, nil
+	}
+	creds, err := i.CredentialsFn()
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Can't get credentials", i)
+	}
+	if creds == nil {
+		return &Credentials{}, nil
+	}
+	return creds, nil
+}
+
+// setClientCertificate sets the client certificate presented
+// during TLS handshakes with the gRPC server
+//
+// NOTE:
+//	Certificate is parsed only when it differs from the current
+// one. Connections that are already established continue to use
+// the certificate they were established with.
+func (i *Invoker) setClientCertificate(creds *Credentials) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if len(creds.ClientCert) == 0 && len(creds.ClientKey) == 0 {
+		i.clientCert = nil
+		i.clientCertPEM = nil
+		i.clientKeyPEM = nil
+		return nil
+	}
+	if !i.TLS {
+		return errors.Errorf(
+			"%s: Client certificate can't be used without TLS",
+			i,
+		)
+	}
+	if i.clientCert != nil &&
+		bytes.Equal(i.clientCertPEM, creds.ClientCert) &&
+		bytes.Equal(i.clientKeyPEM, creds.ClientKey) {
+		return nil
+	}
+	cert, err := tls.X509KeyPair(creds.ClientCert, creds.ClientKey)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"%s: Invalid client certificate & key",
+			i,
+		)
+	}
+	i.clientCert = &cert
+	i.clientCertPEM = creds.ClientCert
+	i.clientKeyPEM = creds.ClientKey
+	return nil
+}
+
+// getClientCertificate returns the current client certificate
+// during a TLS handshake with the gRPC server
+func (i *Invoker) getClientCertificate(
+	*tls.CertificateRequestInfo,
+) (*tls.Certificate, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.clientCert == nil {
+		// no certificate is sent
+		return &tls.Certificate{}, nil
+	}
+	return i.clientCert, nil
+}
+
+// newTLSConfig returns the TLS config built from the TLS
+// settings of this invoker
+func (i *Invoker) newTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:           i.ServerName,
+		GetClientCertificate: i.getClientCertificate,
+	}
+	if len(i.CABundle) != 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(i.CABundle) {
+			return nil, errors.Errorf(
+				"%s: Invalid CA bundle: No PEM encoded certificates found",
+				i,
+			)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// getConn returns the connection used to invoke this hook.
+// Connection is built once & is reused across invocations. Every
+// successful call must be followed by releaseConn.
+//
+// NOTE:
+//	Connection is established in the background. Hence this does
+// not fail if the gRPC server is not reachable. Invocations fail
+// instead.
+func (i *Invoker) getConn() (*grpc.ClientConn, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.closed {
+		return nil, errors.Errorf("%s: Invoker is closed", i)
+	}
+	if i.conn != nil {
+		i.inflight++
+		return i.conn, nil
+	}
+	callOpts := []grpc.CallOption{
+		grpc.MaxCallRecvMsgSize(i.MaxMessageSize),
+		grpc.MaxCallSendMsgSize(i.MaxMessageSize),
+	}
+	if i.Compression != "" {
+		callOpts = append(callOpts, grpc.UseCompressor(i.Compression))
+	}
+	dialOpts := []grpc.DialOption{
+		grpc.WithDefaultCallOptions(callOpts...),
+	}
+	if i.TLS {
+		tlsConfig, err := i.newTLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		dialOpts = append(
+			dialOpts,
+			grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		)
+	} else {
+		dialOpts = append(dialOpts, grpc.WithInsecure())
+	}
+	conn, err := grpc.Dial(i.Address, dialOpts...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Failed to dial", i)
+	}
+	i.conn = conn
+	i.inflight++
+	return i.conn, nil
+}
+
+// releaseConn marks the end of an invocation that got its
+// connection via getConn
+func (i *Invoker) releaseConn() {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.inflight--
+	err := i.closeConnIfIdle()
+	if err != nil {
+		glog.Warningf("%v", err)
+	}
+}
+
+// Close closes the connection of this invoker. Invocations that
+// are in progress complete before the connection gets closed while
+// later invocations fail.
+func (i *Invoker) Close() error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	i.closed = true
+	return i.closeConnIfIdle()
+}
+
+// closeConnIfIdle closes the connection once this invoker is closed
+// & none of the invocations is using the connection
+func (i *Invoker) closeConnIfIdle() error {
+	if !i.closed || i.inflight > 0 || i.conn == nil {
+		return nil
+	}
+	conn := i.conn
+	i.conn = nil
+	err := conn.Close()
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to close connection", i)
+	}
+	return nil
+}
+
+// bearerToken sends the token in the authorization header of
+// every invocation
+type bearerToken string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials
+func (t bearerToken) GetRequestMetadata(
+	ctx context.Context,
+	uri ...string,
+) (map[string]string, error) {
+	return map[string]string{
+		"authorization": "Bearer " + string(t),
+	}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials
+//
+// NOTE:
+//	This is false to be on par with webhooks that send their
+// bearer tokens over plain HTTP as well
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
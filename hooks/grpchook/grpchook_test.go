@@ -0,0 +1,412 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpchook
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/pem"
+	"net"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+)
+
+// testHookServer echoes the method & authorization of every
+// invocation as the response
+type testHookServer struct {
+	// customize is not implemented
+	UnimplementedDecoratorHookServer
+}
+
+func (s *testHookServer) reply(
+	ctx context.Context,
+	method string,
+	req *Request,
+) (*Response, error) {
+	if strings.Contains(string(req.Payload), "fail") {
+		return nil, errors.Errorf("failed")
+	}
+	var auth string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) != 0 {
+		auth = values[0]
+	}
+	return &Response{
+		Payload: []byte(`{"method":"` + method + `","auth":"` + auth + `"}`),
+	}, nil
+}
+
+func (s *testHookServer) Sync(ctx context.Context, req *Request) (*Response, error) {
+	return s.reply(ctx, "sync", req)
+}
+
+func (s *testHookServer) Finalize(ctx context.Context, req *Request) (*Response, error) {
+	return s.reply(ctx, "finalize", req)
+}
+
+// testRequest decides its method via the finalizing flag
+type testRequest struct {
+	Finalizing bool   `json:"finalizing"`
+	Data       string `json:"data"`
+}
+
+func (r *testRequest) GRPCMethod() string {
+	if r.Finalizing {
+		return GenericFinalizeMethod
+	}
+	return GenericSyncMethod
+}
+
+// startTestServer starts a gRPC server with GenericHook service
+// & returns its address along with its stop function
+func startTestServer(t *testing.T, opts ...grpc.ServerOption) (string, func()) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Can't listen: %v", err)
+	}
+	server := grpc.NewServer(opts...)
+	RegisterGenericHookServer(server, &testHookServer{})
+	go server.Serve(listener)
+	return listener.Addr().String(), server.Stop
+}
+
+func TestInvoke(t *testing.T) {
+	address, stop := startTestServer(t)
+	defer stop()
+
+	var tests = map[string]struct {
+		invoker      *Invoker
+		request      interface{}
+		expectMethod string
+		expectAuth   string
+		isErr        bool
+	}{
+		"sync": {
+			invoker:      &Invoker{},
+			request:      &testRequest{},
+			expectMethod: "sync",
+		},
+		"finalize": {
+			invoker:      &Invoker{},
+			request:      &testRequest{Finalizing: true},
+			expectMethod: "finalize",
+		},
+		"method overrides request": {
+			invoker: &Invoker{
+				Method: GenericFinalizeMethod,
+			},
+			request:      &testRequest{},
+			expectMethod: "finalize",
+		},
+		"request without method": {
+			invoker: &Invoker{},
+			request: map[string]string{},
+			isErr:   true,
+		},
+		"unknown method": {
+			invoker: &Invoker{
+				Method: DecoratorSyncMethod,
+			},
+			request: &testRequest{},
+			isErr:   true,
+		},
+		"hook error": {
+			invoker: &Invoker{},
+			request: &testRequest{Data: "fail"},
+			isErr:   true,
+		},
+		"gzip compression": {
+			invoker: &Invoker{
+				Compression: CompressionGzip,
+			},
+			request:      &testRequest{Data: strings.Repeat("a", 1000)},
+			expectMethod: "sync",
+		},
+		"large request": {
+			invoker:      &Invoker{},
+			request:      &testRequest{Data: strings.Repeat("a", 1000*1000)},
+			expectMethod: "sync",
+		},
+		"request larger than max message size": {
+			invoker: &Invoker{
+				MaxMessageSize: 1000,
+			},
+			request: &testRequest{Data: strings.Repeat("a", 1000)},
+			isErr:   true,
+		},
+		"bearer token": {
+			invoker: &Invoker{
+				CredentialsFn: func() (*Credentials, error) {
+					return &Credentials{BearerToken: "secret"}, nil
+				},
+			},
+			request:      &testRequest{},
+			expectMethod: "sync",
+			expectAuth:   "Bearer secret",
+		},
+		"client certificate without TLS": {
+			invoker: &Invoker{
+				CredentialsFn: func() (*Credentials, error) {
+					return &Credentials{
+						ClientCert: []byte("cert"),
+						ClientKey:  []byte("key"),
+					}, nil
+				},
+			},
+			request: &testRequest{},
+			isErr:   true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			mock.invoker.Address = address
+			mock.invoker.Timeout = 5 * time.Second
+			if mock.invoker.MaxMessageSize == 0 {
+				mock.invoker.MaxMessageSize = DefaultMaxMessageSize
+			}
+
+			var resp map[string]string
+			err := mock.invoker.Invoke(mock.request, &resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if resp["method"] != mock.expectMethod {
+				t.Fatalf(
+					"Expected method %q got %q",
+					mock.expectMethod,
+					resp["method"],
+				)
+			}
+			if resp["auth"] != mock.expectAuth {
+				t.Fatalf("Expected auth %q got %q", mock.expectAuth, resp["auth"])
+			}
+		})
+	}
+}
+
+func TestInvokerClose(t *testing.T) {
+	address, stop := startTestServer(t)
+	defer stop()
+
+	i := &Invoker{
+		Address:        address,
+		Timeout:        5 * time.Second,
+		MaxMessageSize: DefaultMaxMessageSize,
+	}
+	var resp map[string]string
+	err := i.Invoke(&testRequest{}, &resp)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if i.conn == nil {
+		t.Fatalf("Expected connection got none")
+	}
+	err = i.Close()
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if i.conn != nil {
+		t.Fatalf("Expected connection to be closed")
+	}
+	err = i.Invoke(&testRequest{}, &resp)
+	if err == nil || !strings.Contains(err.Error(), "Invoker is closed") {
+		t.Fatalf("Expected closed invoker error got %v", err)
+	}
+}
+
+func TestInvokeWithTLS(t *testing.T) {
+	// borrow the self signed certificate of httptest that is
+	// valid for example.com & 127.0.0.1
+	httpServer := httptest.NewTLSServer(nil)
+	cert := httpServer.TLS.Certificates[0]
+	caBundle := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: httpServer.Certificate().Raw,
+	})
+	httpServer.Close()
+
+	address, stop := startTestServer(
+		t,
+		grpc.Creds(credentials.NewTLS(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+		})),
+	)
+	defer stop()
+
+	var tests = map[string]struct {
+		invoker *Invoker
+		isErr   bool
+	}{
+		"no CA bundle": {
+			invoker: &Invoker{
+				TLS: true,
+			},
+			isErr: true,
+		},
+		"invalid CA bundle": {
+			invoker: &Invoker{
+				TLS:      true,
+				CABundle: []byte("junk"),
+			},
+			isErr: true,
+		},
+		"CA bundle": {
+			invoker: &Invoker{
+				TLS:      true,
+				CABundle: caBundle,
+			},
+		},
+		"CA bundle & server name": {
+			invoker: &Invoker{
+				TLS:        true,
+				CABundle:   caBundle,
+				ServerName: "example.com",
+			},
+		},
+		"CA bundle & wrong server name": {
+			invoker: &Invoker{
+				TLS:        true,
+				CABundle:   caBundle,
+				ServerName: "junk.com",
+			},
+			isErr: true,
+		},
+		"no TLS": {
+			invoker: &Invoker{},
+			isErr:   true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			mock.invoker.Address = address
+			mock.invoker.Timeout = 2 * time.Second
+			mock.invoker.MaxMessageSize = DefaultMaxMessageSize
+
+			var resp map[string]string
+			err := mock.invoker.Invoke(&testRequest{}, &resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+		})
+	}
+}
+
+func TestNewInvoker(t *testing.T) {
+	_, err := NewInvoker()
+	if err == nil {
+		t.Fatalf("Expected error for empty address got none")
+	}
+	i, err := NewInvoker(func(i *Invoker) error {
+		i.Address = "localhost:80"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if i.MaxMessageSize != DefaultMaxMessageSize {
+		t.Fatalf(
+			"Expected max message size %d got %d",
+			DefaultMaxMessageSize,
+			i.MaxMessageSize,
+		)
+	}
+}
+
+// testCustomizeHookServer implements the optional customize method
+type testCustomizeHookServer struct {
+	testHookServer
+}
+
+func (s *testCustomizeHookServer) Customize(ctx context.Context, req *Request) (*Response, error) {
+	return s.reply(ctx, "customize", req)
+}
+
+func TestDecoratorHookServerCustomize(t *testing.T) {
+	var tests = map[string]struct {
+		server       DecoratorHookServer
+		expectMethod string
+		isErr        bool
+	}{
+		"customize is invoked if implemented": {
+			server:       &testCustomizeHookServer{},
+			expectMethod: "customize",
+		},
+		"customize fails if not implemented": {
+			server: &testHookServer{},
+			isErr:  true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			listener, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatalf("Can't listen: %v", err)
+			}
+			server := grpc.NewServer()
+			RegisterDecoratorHookServer(server, mock.server)
+			go server.Serve(listener)
+			defer server.Stop()
+
+			i := &Invoker{
+				Address:        listener.Addr().String(),
+				Method:         DecoratorCustomizeMethod,
+				Timeout:        5 * time.Second,
+				MaxMessageSize: DefaultMaxMessageSize,
+			}
+			var resp map[string]string
+			err = i.Invoke(map[string]string{}, &resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if resp["method"] != mock.expectMethod {
+				t.Fatalf(
+					"Expected method %q got %q",
+					mock.expectMethod,
+					resp["method"],
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,616 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: hook.proto
+
+package grpchook
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Request is sent to a hook
+type Request struct {
+	// JSON encoded request that is otherwise sent to the
+	// equivalent webhook
+	Payload              []byte   `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Request) Reset()         { *m = Request{} }
+func (m *Request) String() string { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()    {}
+func (*Request) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eef30da1c11ee1b, []int{0}
+}
+
+func (m *Request) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Request.Unmarshal(m, b)
+}
+func (m *Request) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Request.Marshal(b, m, deterministic)
+}
+func (m *Request) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Request.Merge(m, src)
+}
+func (m *Request) XXX_Size() int {
+	return xxx_messageInfo_Request.Size(m)
+}
+func (m *Request) XXX_DiscardUnknown() {
+	xxx_messageInfo_Request.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Request proto.InternalMessageInfo
+
+func (m *Request) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// Response is returned by a hook
+type Response struct {
+	// JSON encoded response that is otherwise returned by the
+	// equivalent webhook
+	Payload              []byte   `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Response) Reset()         { *m = Response{} }
+func (m *Response) String() string { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()    {}
+func (*Response) Descriptor() ([]byte, []int) {
+	return fileDescriptor_3eef30da1c11ee1b, []int{1}
+}
+
+func (m *Response) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Response.Unmarshal(m, b)
+}
+func (m *Response) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Response.Marshal(b, m, deterministic)
+}
+func (m *Response) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Response.Merge(m, src)
+}
+func (m *Response) XXX_Size() int {
+	return xxx_messageInfo_Response.Size(m)
+}
+func (m *Response) XXX_DiscardUnknown() {
+	xxx_messageInfo_Response.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Response proto.InternalMessageInfo
+
+func (m *Response) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Request)(nil), "metac.hooks.v1.Request")
+	proto.RegisterType((*Response)(nil), "metac.hooks.v1.Response")
+}
+
+func init() { proto.RegisterFile("hook.proto", fileDescriptor_3eef30da1c11ee1b) }
+
+var fileDescriptor_3eef30da1c11ee1b = []byte{
+	// 253 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xca, 0xc8, 0xcf, 0xcf,
+	0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0xcb, 0x4d, 0x2d, 0x49, 0x4c, 0xd6, 0x03, 0x89,
+	0x14, 0xeb, 0x95, 0x19, 0x2a, 0x29, 0x73, 0xb1, 0x07, 0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97, 0x08,
+	0x49, 0x70, 0xb1, 0x17, 0x24, 0x56, 0xe6, 0xe4, 0x27, 0xa6, 0x48, 0x30, 0x2a, 0x30, 0x6a, 0xf0,
+	0x04, 0xc1, 0xb8, 0x4a, 0x2a, 0x5c, 0x1c, 0x41, 0xa9, 0xc5, 0x05, 0xf9, 0x79, 0xc5, 0xa9, 0xb8,
+	0x55, 0x19, 0xb5, 0x33, 0x72, 0x71, 0xbb, 0xa7, 0xe6, 0xa5, 0x16, 0x65, 0x26, 0x7b, 0xe4, 0xe7,
+	0x67, 0x0b, 0x59, 0x72, 0xb1, 0x04, 0x57, 0xe6, 0x25, 0x0b, 0x89, 0xeb, 0xa1, 0xda, 0xa9, 0x07,
+	0xb5, 0x50, 0x4a, 0x02, 0x53, 0x02, 0x6a, 0x89, 0x2d, 0x17, 0x87, 0x5b, 0x66, 0x5e, 0x62, 0x4e,
+	0x66, 0x55, 0x2a, 0x19, 0xda, 0x8d, 0xae, 0x30, 0x71, 0xf1, 0x3a, 0xe7, 0xe7, 0x16, 0xe4, 0x17,
+	0x67, 0x96, 0xa4, 0x0e, 0xac, 0x5b, 0x84, 0x9c, 0xb9, 0xf8, 0x02, 0x8a, 0x52, 0x43, 0x0b, 0x52,
+	0x12, 0x4b, 0x52, 0x9d, 0x33, 0x32, 0x73, 0x52, 0xc8, 0x31, 0xc4, 0x85, 0x8b, 0x3f, 0x20, 0xbf,
+	0xb8, 0x84, 0x42, 0x53, 0xec, 0xb8, 0x38, 0x9d, 0x4b, 0x8b, 0x4b, 0xf2, 0x73, 0xc9, 0x0c, 0xd6,
+	0x93, 0x8c, 0x5c, 0xbc, 0x2e, 0xa9, 0xc9, 0xf9, 0x45, 0x89, 0x25, 0xf9, 0x45, 0x03, 0x1c, 0xac,
+	0x14, 0xfa, 0xc5, 0x49, 0x31, 0x4a, 0x3e, 0xbf, 0x20, 0x35, 0x2f, 0x35, 0xa9, 0x58, 0x2f, 0x33,
+	0x5f, 0x1f, 0xac, 0x4a, 0x1f, 0xac, 0x4a, 0x3f, 0xbd, 0xa8, 0x20, 0x19, 0xc4, 0x4a, 0x62, 0x03,
+	0xe7, 0x18, 0x63, 0xc0, 0x00, 0xc5, 0x51, 0x70, 0xf7, 0x3f, 0x03, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// GenericHookClient is the client API for GenericHook service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type GenericHookClient interface {
+	Sync(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	Finalize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+}
+
+type genericHookClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewGenericHookClient(cc *grpc.ClientConn) GenericHookClient {
+	return &genericHookClient{cc}
+}
+
+func (c *genericHookClient) Sync(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.GenericHook/Sync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *genericHookClient) Finalize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.GenericHook/Finalize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GenericHookServer is the server API for GenericHook service.
+type GenericHookServer interface {
+	Sync(context.Context, *Request) (*Response, error)
+	Finalize(context.Context, *Request) (*Response, error)
+}
+
+// UnimplementedGenericHookServer can be embedded to have forward compatible implementations.
+type UnimplementedGenericHookServer struct {
+}
+
+func (*UnimplementedGenericHookServer) Sync(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (*UnimplementedGenericHookServer) Finalize(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Finalize not implemented")
+}
+
+func RegisterGenericHookServer(s *grpc.Server, srv GenericHookServer) {
+	s.RegisterService(&_GenericHook_serviceDesc, srv)
+}
+
+func _GenericHook_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GenericHookServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.GenericHook/Sync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GenericHookServer).Sync(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GenericHook_Finalize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GenericHookServer).Finalize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.GenericHook/Finalize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GenericHookServer).Finalize(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _GenericHook_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "metac.hooks.v1.GenericHook",
+	HandlerType: (*GenericHookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sync",
+			Handler:    _GenericHook_Sync_Handler,
+		},
+		{
+			MethodName: "Finalize",
+			Handler:    _GenericHook_Finalize_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "hook.proto",
+}
+
+// CompositeHookClient is the client API for CompositeHook service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CompositeHookClient interface {
+	Sync(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	Finalize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	PreUpdateChild(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	PostUpdateChild(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	// Optional; implemented when the customize hook is set
+	Customize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+}
+
+type compositeHookClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCompositeHookClient(cc *grpc.ClientConn) CompositeHookClient {
+	return &compositeHookClient{cc}
+}
+
+func (c *compositeHookClient) Sync(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.CompositeHook/Sync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *compositeHookClient) Finalize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.CompositeHook/Finalize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *compositeHookClient) PreUpdateChild(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.CompositeHook/PreUpdateChild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *compositeHookClient) PostUpdateChild(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.CompositeHook/PostUpdateChild", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *compositeHookClient) Customize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.CompositeHook/Customize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CompositeHookServer is the server API for CompositeHook service.
+type CompositeHookServer interface {
+	Sync(context.Context, *Request) (*Response, error)
+	Finalize(context.Context, *Request) (*Response, error)
+	PreUpdateChild(context.Context, *Request) (*Response, error)
+	PostUpdateChild(context.Context, *Request) (*Response, error)
+	// Optional; implemented when the customize hook is set
+	Customize(context.Context, *Request) (*Response, error)
+}
+
+// UnimplementedCompositeHookServer can be embedded to have forward compatible implementations.
+type UnimplementedCompositeHookServer struct {
+}
+
+func (*UnimplementedCompositeHookServer) Sync(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (*UnimplementedCompositeHookServer) Finalize(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Finalize not implemented")
+}
+func (*UnimplementedCompositeHookServer) PreUpdateChild(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PreUpdateChild not implemented")
+}
+func (*UnimplementedCompositeHookServer) PostUpdateChild(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PostUpdateChild not implemented")
+}
+func (*UnimplementedCompositeHookServer) Customize(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Customize not implemented")
+}
+
+func RegisterCompositeHookServer(s *grpc.Server, srv CompositeHookServer) {
+	s.RegisterService(&_CompositeHook_serviceDesc, srv)
+}
+
+func _CompositeHook_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompositeHookServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.CompositeHook/Sync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompositeHookServer).Sync(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompositeHook_Finalize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompositeHookServer).Finalize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.CompositeHook/Finalize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompositeHookServer).Finalize(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompositeHook_PreUpdateChild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompositeHookServer).PreUpdateChild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.CompositeHook/PreUpdateChild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompositeHookServer).PreUpdateChild(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompositeHook_PostUpdateChild_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompositeHookServer).PostUpdateChild(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.CompositeHook/PostUpdateChild",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompositeHookServer).PostUpdateChild(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CompositeHook_Customize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CompositeHookServer).Customize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.CompositeHook/Customize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CompositeHookServer).Customize(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _CompositeHook_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "metac.hooks.v1.CompositeHook",
+	HandlerType: (*CompositeHookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sync",
+			Handler:    _CompositeHook_Sync_Handler,
+		},
+		{
+			MethodName: "Finalize",
+			Handler:    _CompositeHook_Finalize_Handler,
+		},
+		{
+			MethodName: "PreUpdateChild",
+			Handler:    _CompositeHook_PreUpdateChild_Handler,
+		},
+		{
+			MethodName: "PostUpdateChild",
+			Handler:    _CompositeHook_PostUpdateChild_Handler,
+		},
+		{
+			MethodName: "Customize",
+			Handler:    _CompositeHook_Customize_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "hook.proto",
+}
+
+// DecoratorHookClient is the client API for DecoratorHook service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DecoratorHookClient interface {
+	Sync(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	Finalize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+	// Optional; implemented when the customize hook is set
+	Customize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error)
+}
+
+type decoratorHookClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDecoratorHookClient(cc *grpc.ClientConn) DecoratorHookClient {
+	return &decoratorHookClient{cc}
+}
+
+func (c *decoratorHookClient) Sync(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.DecoratorHook/Sync", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoratorHookClient) Finalize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.DecoratorHook/Finalize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *decoratorHookClient) Customize(ctx context.Context, in *Request, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := c.cc.Invoke(ctx, "/metac.hooks.v1.DecoratorHook/Customize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DecoratorHookServer is the server API for DecoratorHook service.
+type DecoratorHookServer interface {
+	Sync(context.Context, *Request) (*Response, error)
+	Finalize(context.Context, *Request) (*Response, error)
+	// Optional; implemented when the customize hook is set
+	Customize(context.Context, *Request) (*Response, error)
+}
+
+// UnimplementedDecoratorHookServer can be embedded to have forward compatible implementations.
+type UnimplementedDecoratorHookServer struct {
+}
+
+func (*UnimplementedDecoratorHookServer) Sync(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sync not implemented")
+}
+func (*UnimplementedDecoratorHookServer) Finalize(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Finalize not implemented")
+}
+func (*UnimplementedDecoratorHookServer) Customize(ctx context.Context, req *Request) (*Response, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Customize not implemented")
+}
+
+func RegisterDecoratorHookServer(s *grpc.Server, srv DecoratorHookServer) {
+	s.RegisterService(&_DecoratorHook_serviceDesc, srv)
+}
+
+func _DecoratorHook_Sync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoratorHookServer).Sync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.DecoratorHook/Sync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoratorHookServer).Sync(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DecoratorHook_Finalize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoratorHookServer).Finalize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.DecoratorHook/Finalize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoratorHookServer).Finalize(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DecoratorHook_Customize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Request)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DecoratorHookServer).Customize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/metac.hooks.v1.DecoratorHook/Customize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DecoratorHookServer).Customize(ctx, req.(*Request))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _DecoratorHook_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "metac.hooks.v1.DecoratorHook",
+	HandlerType: (*DecoratorHookServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sync",
+			Handler:    _DecoratorHook_Sync_Handler,
+		},
+		{
+			MethodName: "Finalize",
+			Handler:    _DecoratorHook_Finalize_Handler,
+		},
+		{
+			MethodName: "Customize",
+			Handler:    _DecoratorHook_Customize_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "hook.proto",
+}
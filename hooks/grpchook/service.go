@@ -0,0 +1,52 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package grpchook
+
+// NOTE:
+//	hook.pb.go is generated from hook.proto by protoc-gen-go of
+// github.com/golang/protobuf v1.3.2. Later versions of this plugin
+// generate code that needs a later version of gRPC.
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. hook.proto
+
+// Names of the services declared in hook.proto
+const (
+	GenericHookService   = "metac.hooks.v1.GenericHook"
+	CompositeHookService = "metac.hooks.v1.CompositeHook"
+	DecoratorHookService = "metac.hooks.v1.DecoratorHook"
+)
+
+// Full names of the methods declared in hook.proto
+const (
+	GenericSyncMethod     = "/" + GenericHookService + "/Sync"
+	GenericFinalizeMethod = "/" + GenericHookService + "/Finalize"
+
+	CompositeSyncMethod            = "/" + CompositeHookService + "/Sync"
+	CompositeFinalizeMethod        = "/" + CompositeHookService + "/Finalize"
+	CompositePreUpdateChildMethod  = "/" + CompositeHookService + "/PreUpdateChild"
+	CompositePostUpdateChildMethod = "/" + CompositeHookService + "/PostUpdateChild"
+	CompositeCustomizeMethod       = "/" + CompositeHookService + "/Customize"
+
+	DecoratorSyncMethod      = "/" + DecoratorHookService + "/Sync"
+	DecoratorFinalizeMethod  = "/" + DecoratorHookService + "/Finalize"
+	DecoratorCustomizeMethod = "/" + DecoratorHookService + "/Customize"
+)
+
+// MethodGetter is implemented by hook requests to decide the gRPC
+// method that gets invoked for them
+type MethodGetter interface {
+	GRPCMethod() string
+}
@@ -18,14 +18,21 @@ limitations under the License.
 package hooks
 
 import (
+	"context"
+
 	"github.com/pkg/errors"
 )
 
 // Invoker enables invocation of appropriate hook
 type Invoker struct {
 	// InvokeFn abstracts invocation of hook. Typically specific
-	// hook implementors will have their call methods set here
-	InvokeFn func(request, response interface{}) error
+	// hook implementors will have their call methods set here.
+	// Invocation is expected to end once the given context is done.
+	InvokeFn func(ctx context.Context, request, response interface{}) error
+
+	// CloseFn releases the resources held by the hook e.g. its
+	// connections. This is optional.
+	CloseFn func() error
 }
 
 // InvokerOption is a typed function that helps in building
@@ -44,7 +51,10 @@ func NewInvoker(must InvokerOption, others ...InvokerOption) (*Invoker, error) {
 
 	i := &Invoker{}
 	for _, o := range options {
-		o(i)
+		err := o(i)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if i.InvokeFn == nil {
@@ -55,5 +65,21 @@ func NewInvoker(must InvokerOption, others ...InvokerOption) (*Invoker, error) {
 
 // Invoke invokes the hook
 func (c *Invoker) Invoke(request, response interface{}) error {
-	return c.InvokeFn(request, response)
+	return c.InvokeContext(context.Background(), request, response)
+}
+
+// InvokeContext invokes the hook till the given context is done
+func (c *Invoker) InvokeContext(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	return c.InvokeFn(ctx, request, response)
+}
+
+// Close releases the resources held by the hook
+func (c *Invoker) Close() error {
+	if c.CloseFn == nil {
+		return nil
+	}
+	return c.CloseFn()
 }
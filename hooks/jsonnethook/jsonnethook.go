@@ -0,0 +1,271 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonnethook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	jsonnet "github.com/google/go-jsonnet"
+	"github.com/google/go-jsonnet/ast"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"openebs.io/metac/metrics"
+)
+
+const (
+	// RequestArgName is the name of the top level argument that
+	// holds the hook request
+	RequestArgName = "request"
+
+	// DefaultTimeout is the default timeout of a single evaluation
+	DefaultTimeout = 10 * time.Second
+)
+
+// Invoker manages evaluation of a Jsonnet program as a hook
+type Invoker struct {
+	// name of the program used in logs & errors
+	Filename string
+
+	// SourceFn returns the Jsonnet program
+	SourceFn func() (string, error)
+
+	// Timeout after which an evaluation is abandoned. This
+	// defaults to DefaultTimeout.
+	Timeout time.Duration
+
+	// mutex guards the compiled program & the abandoned evaluations
+	mutex sync.Mutex
+
+	// number of abandoned evaluations that are still running
+	abandoned int
+
+	// source of the compiled program
+	source string
+
+	// program compiled from source
+	program ast.Node
+}
+
+// InvokerOption is a typed function that is used
+// to build *Invoker instance
+//
+// NOTE:
+//	This follows "functional options" pattern
+type InvokerOption func(*Invoker) error
+
+// NewInvoker returns a new instance of Invoker
+// based on an optional list of InvokerOptions
+func NewInvoker(opts ...InvokerOption) (*Invoker, error) {
+	i := &Invoker{
+		Timeout: DefaultTimeout,
+	}
+	for _, o := range opts {
+		err := o(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if i.Filename == "" {
+		return nil, errors.Errorf("%s: Filename can't be empty", i)
+	}
+	if i.SourceFn == nil {
+		return nil, errors.Errorf("%s: SourceFn can't be nil", i)
+	}
+	return i, nil
+}
+
+// String implements Stringer interface
+func (i *Invoker) String() string {
+	return fmt.Sprintf("Jsonnet Invoker: Filename=%s", i.Filename)
+}
+
+// Invoke this program by passing the given request as its top
+// level argument and fill up the given response from its output
+func (i *Invoker) Invoke(request, response interface{}) error {
+	return i.InvokeContext(context.Background(), request, response)
+}
+
+// InvokeContext invokes this program by passing the given request
+// as its top level argument and fills up the given response from
+// its output. The evaluation is abandoned once the given context is
+// done.
+func (i *Invoker) InvokeContext(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	start := time.Now()
+	err := i.invoke(ctx, request, response)
+	metrics.RecordHook(i.Filename, start, err)
+	return err
+}
+
+// invoke this program by passing the given request as its top
+// level argument and fill up the given response from its output
+func (i *Invoker) invoke(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	program, err := i.getProgram()
+	if err != nil {
+		return err
+	}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to marshal", i)
+	}
+	glog.V(8).Infof("%s: Will invoke %s", i, reqBody)
+
+	// NOTE:
+	//	VM is not safe for concurrent use & hence a new one is
+	// built for every invocation. Compiled program is shared.
+	vm := jsonnet.MakeVM()
+	vm.Importer(noImporter{})
+	for _, ext := range Extensions {
+		vm.NativeFunction(ext)
+	}
+	vm.TLACode(RequestArgName, string(reqBody))
+	result, err := i.evaluate(ctx, vm, program)
+	if err != nil {
+		return err
+	}
+	glog.V(8).Infof("%s: Got response %s", i, result)
+
+	err = json.Unmarshal([]byte(result), response)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to unmarshal response", i)
+	}
+	glog.V(8).Infof("%s: Invoked successfully", i)
+	return nil
+}
+
+// evaluate the given program with the given VM within this
+// invoker's timeout or till the given context is done
+//
+// NOTE:
+//	Jsonnet evaluation can't be cancelled. Hence an evaluation that
+// times out is abandoned & keeps running in the background. Further
+// evaluations are rejected till the abandoned ones complete so that
+// a runaway program can't pile up evaluations across invocations.
+func (i *Invoker) evaluate(
+	ctx context.Context,
+	vm *jsonnet.VM,
+	program ast.Node,
+) (string, error) {
+	i.mutex.Lock()
+	abandoned := i.abandoned
+	i.mutex.Unlock()
+	if abandoned > 0 {
+		return "", errors.Errorf(
+			"%s: Can't evaluate: %d evaluation(s) that timed out are still running",
+			i,
+			abandoned,
+		)
+	}
+
+	type evaluation struct {
+		result string
+		err    error
+	}
+	done := make(chan evaluation, 1)
+	go func() {
+		result, err := vm.Evaluate(program)
+		done <- evaluation{result: result, err: err}
+	}()
+
+	timeout := i.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case e := <-done:
+		if e.err != nil {
+			return "", errors.Errorf(
+				"%s: Failed to evaluate: %s",
+				i,
+				strings.TrimSpace(vm.ErrorFormatter.Format(e.err)),
+			)
+		}
+		return e.result, nil
+	case <-timer.C:
+		err = errors.Errorf("%s: Evaluation timed out after %s", i, timeout)
+	case <-ctx.Done():
+		err = errors.Wrapf(ctx.Err(), "%s: Evaluation abandoned", i)
+	}
+
+	i.mutex.Lock()
+	i.abandoned++
+	i.mutex.Unlock()
+	go func() {
+		<-done
+		i.mutex.Lock()
+		i.abandoned--
+		i.mutex.Unlock()
+	}()
+	return "", err
+}
+
+// getProgram returns the compiled program. Program is compiled
+// only when its source has changed since the last invocation.
+func (i *Invoker) getProgram() (ast.Node, error) {
+	source, err := i.SourceFn()
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Failed to get source", i)
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.program != nil && i.source == source {
+		return i.program, nil
+	}
+	program, err := jsonnet.SnippetToAST(i.Filename, source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Failed to compile", i)
+	}
+	glog.V(4).Infof("%s: Compiled program", i)
+	i.source = source
+	i.program = program
+	return program, nil
+}
+
+// noImporter rejects all the imports
+//
+// NOTE:
+//	Programs may be defined by anyone who can create a controller
+// resource. Hence these are not allowed to read the files within
+// metac's container.
+type noImporter struct{}
+
+// Import implements jsonnet.Importer interface
+func (noImporter) Import(
+	importedFrom, importedPath string,
+) (jsonnet.Contents, string, error) {
+	return jsonnet.Contents{}, "", errors.Errorf(
+		"Imports are not supported: Can't import %q",
+		importedPath,
+	)
+}
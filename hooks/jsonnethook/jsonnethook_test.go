@@ -0,0 +1,180 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package jsonnethook
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func TestInvoke(t *testing.T) {
+	var tests = map[string]struct {
+		source       string
+		sourceErr    error
+		expect       map[string]string
+		isErr        bool
+		expectErrMsg string
+	}{
+		"request is echoed": {
+			source: `function(request) request`,
+			expect: map[string]string{"name": "test"},
+		},
+		"request is transformed": {
+			source: `function(request) { name: request.name + "-child" }`,
+			expect: map[string]string{"name": "test-child"},
+		},
+		"native extension": {
+			source: `function(request) {
+				name: std.toString(std.native("parseInt")("ff", 16)),
+			}`,
+			expect: map[string]string{"name": "255"},
+		},
+		"source error": {
+			sourceErr:    errors.Errorf("configmap not found"),
+			isErr:        true,
+			expectErrMsg: "configmap not found",
+		},
+		"compile error": {
+			source: `function(request) {`,
+			isErr:  true,
+		},
+		"runtime error": {
+			source:       `function(request) error "bad request"`,
+			isErr:        true,
+			expectErrMsg: "bad request",
+		},
+		"import is rejected": {
+			source:       `function(request) import "/etc/passwd"`,
+			isErr:        true,
+			expectErrMsg: "Imports are not supported",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			i, err := NewInvoker(func(i *Invoker) error {
+				i.Filename = name
+				i.SourceFn = func() (string, error) {
+					return mock.source, mock.sourceErr
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			var resp map[string]string
+			err = i.Invoke(map[string]string{"name": "test"}, &resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				if !strings.Contains(err.Error(), mock.expectErrMsg) {
+					t.Fatalf(
+						"Expected error with %q got %q",
+						mock.expectErrMsg,
+						err.Error(),
+					)
+				}
+				return
+			}
+			if resp["name"] != mock.expect["name"] {
+				t.Fatalf("Expected response %v got %v", mock.expect, resp)
+			}
+		})
+	}
+}
+
+func TestInvokeRecompilesChangedSource(t *testing.T) {
+	source := `function(request) { name: "v1" }`
+	i := &Invoker{
+		Filename: "test",
+		SourceFn: func() (string, error) {
+			return source, nil
+		},
+	}
+	var resp map[string]string
+	err := i.Invoke(map[string]string{}, &resp)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if resp["name"] != "v1" {
+		t.Fatalf("Expected name v1 got %q", resp["name"])
+	}
+	compiled := i.program
+
+	// unchanged source reuses the compiled program
+	err = i.Invoke(map[string]string{}, &resp)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if i.program != compiled {
+		t.Fatalf("Expected compiled program to be reused")
+	}
+
+	source = `function(request) { name: "v2" }`
+	err = i.Invoke(map[string]string{}, &resp)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if resp["name"] != "v2" {
+		t.Fatalf("Expected name v2 got %q", resp["name"])
+	}
+}
+
+func TestInvokeTimeout(t *testing.T) {
+	source := `
+		local loop(n) = if n == 0 then 0 else loop(n - 1) tailstrict;
+		function(request) { name: std.toString(loop(50000)) }
+	`
+	i := &Invoker{
+		Filename: "test",
+		SourceFn: func() (string, error) {
+			return source, nil
+		},
+		Timeout: 10 * time.Millisecond,
+	}
+	var resp map[string]string
+	err := i.Invoke(map[string]string{}, &resp)
+	if err == nil || !strings.Contains(err.Error(), "Evaluation timed out") {
+		t.Fatalf("Expected timeout error got %v", err)
+	}
+
+	// abandoned evaluation blocks further evaluations
+	source = `function(request) { name: "v1" }`
+	err = i.Invoke(map[string]string{}, &resp)
+	if err == nil || !strings.Contains(err.Error(), "still running") {
+		t.Fatalf("Expected still running error got %v", err)
+	}
+
+	err = wait.PollImmediate(10*time.Millisecond, 30*time.Second, func() (bool, error) {
+		return i.Invoke(map[string]string{}, &resp) == nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected invocation to succeed once abandoned evaluation completes")
+	}
+	if resp["name"] != "v1" {
+		t.Fatalf("Expected name v1 got %q", resp["name"])
+	}
+}
@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package hooks
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/metrics"
+)
+
+// Handler invokes a hook with the given request & fills up the
+// given response with the hook's response
+//
+// NOTE:
+//	Context carries the hook's Info & the deadline of this
+// invocation if any. Inline hooks are expected to honour this
+// deadline.
+type Handler func(ctx context.Context, request, response interface{}) error
+
+// Middleware wraps a handler to add behaviour around every hook
+// invocation e.g. logging, metrics, etc.
+type Middleware func(next Handler) Handler
+
+// Validator is implemented by the hook requests that can verify
+// themselves before being sent to the hook
+type Validator interface {
+	Validate() error
+}
+
+// Chain returns the given handler wrapped by the given middlewares.
+// First middleware is the outermost one i.e. it runs first.
+func Chain(handler Handler, middlewares ...Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// DefaultMiddlewares returns the middlewares that recover from
+// panics, log, record metrics & validate requests in that order
+func DefaultMiddlewares() []Middleware {
+	return []Middleware{
+		Recovery(),
+		Logging(),
+		Metrics(),
+		Validation(),
+	}
+}
+
+// Logging logs the start, duration & result of every invocation
+func Logging() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request, response interface{}) error {
+			logger := LoggerFrom(ctx)
+			logger.Infof(4, "Will invoke")
+			start := time.Now()
+			err := next(ctx, request, response)
+			if err != nil {
+				logger.Infof(4, "Failed after %s: %v", time.Since(start), err)
+				return err
+			}
+			logger.Infof(4, "Invoked successfully in %s", time.Since(start))
+			return nil
+		}
+	}
+}
+
+// Metrics records the latency & result of every invocation
+// against the invoking controller & the type of hook
+func Metrics() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request, response interface{}) error {
+			info := InfoFrom(ctx)
+			start := time.Now()
+			err := next(ctx, request, response)
+			metrics.RecordHookCall(info.Controller, info.Hook, start, err)
+			return err
+		}
+	}
+}
+
+// Recovery converts a panic during an invocation into an error
+//
+// NOTE:
+//	This lets a buggy inline hook fail its invocation instead of
+// crashing metac
+func Recovery() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request, response interface{}) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					LoggerFrom(ctx).Errorf("Panicked: %v\n%s", r, debug.Stack())
+					err = errors.Errorf("%s: Panicked: %v", InfoFrom(ctx), r)
+				}
+			}()
+			return next(ctx, request, response)
+		}
+	}
+}
+
+// Validation verifies the requests that implement Validator before
+// invoking the hook
+func Validation() Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request, response interface{}) error {
+			if v, ok := request.(Validator); ok {
+				if err := v.Validate(); err != nil {
+					return errors.Wrapf(err, "%s: Invalid request", InfoFrom(ctx))
+				}
+			}
+			return next(ctx, request, response)
+		}
+	}
+}
+
+// Timeout sets the given deadline on the context of every
+// invocation. An invocation that returns after its deadline fails
+// even if the hook succeeded.
+//
+// NOTE:
+//	Webhook, gRPC, exec, jsonnet & starlark hooks are cancelled
+// at this deadline. Inline hooks are expected to honour the
+// context's deadline.
+func Timeout(timeout time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, request, response interface{}) error {
+			ctx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			err := next(ctx, request, response)
+			if err != nil {
+				return err
+			}
+			if ctx.Err() == context.DeadlineExceeded {
+				return errors.Errorf(
+					"%s: Exceeded timeout %s",
+					InfoFrom(ctx),
+					timeout,
+				)
+			}
+			return nil
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package hooks
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// validatedRequest is a request that implements Validator
+type validatedRequest struct {
+	valid bool
+}
+
+// Validate implements Validator interface
+func (r validatedRequest) Validate() error {
+	if !r.valid {
+		return errors.Errorf("Not valid")
+	}
+	return nil
+}
+
+func TestChain(t *testing.T) {
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, request, response interface{}) error {
+				calls = append(calls, name+":before")
+				err := next(ctx, request, response)
+				calls = append(calls, name+":after")
+				return err
+			}
+		}
+	}
+	handler := Chain(
+		func(ctx context.Context, request, response interface{}) error {
+			calls = append(calls, "hook")
+			return nil
+		},
+		trace("first"),
+		trace("second"),
+	)
+	err := handler(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	expect := []string{
+		"first:before",
+		"second:before",
+		"hook",
+		"second:after",
+		"first:after",
+	}
+	if !reflect.DeepEqual(calls, expect) {
+		t.Fatalf("Expected calls %v got %v", expect, calls)
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	ctx := WithInfo(context.Background(), Info{
+		Controller: "test-ctl",
+		Hook:       SyncHook,
+		Type:       "inline",
+	})
+
+	var tests = map[string]struct {
+		middleware   Middleware
+		handler      Handler
+		request      interface{}
+		isErr        bool
+		expectErrMsg string
+	}{
+		"recovery converts panic to error": {
+			middleware: Recovery(),
+			handler: func(_ context.Context, _, _ interface{}) error {
+				panic("boom")
+			},
+			isErr:        true,
+			expectErrMsg: "test-ctl: sync inline hook: Panicked: boom",
+		},
+		"recovery passes through": {
+			middleware: Recovery(),
+			handler: func(_ context.Context, _, _ interface{}) error {
+				return nil
+			},
+		},
+		"validation rejects invalid request": {
+			middleware: Validation(),
+			handler: func(_ context.Context, _, _ interface{}) error {
+				return errors.Errorf("Must not be invoked")
+			},
+			request:      validatedRequest{},
+			isErr:        true,
+			expectErrMsg: "Invalid request: Not valid",
+		},
+		"validation accepts valid request": {
+			middleware: Validation(),
+			handler: func(_ context.Context, _, _ interface{}) error {
+				return nil
+			},
+			request: validatedRequest{valid: true},
+		},
+		"validation ignores requests without validator": {
+			middleware: Validation(),
+			handler: func(_ context.Context, _, _ interface{}) error {
+				return nil
+			},
+			request: map[string]string{},
+		},
+		"timeout fails late invocation": {
+			middleware: Timeout(time.Millisecond),
+			handler: func(ctx context.Context, _, _ interface{}) error {
+				<-ctx.Done()
+				return nil
+			},
+			isErr:        true,
+			expectErrMsg: "Exceeded timeout 1ms",
+		},
+		"timeout sets deadline": {
+			middleware: Timeout(time.Minute),
+			handler: func(ctx context.Context, _, _ interface{}) error {
+				if _, found := ctx.Deadline(); !found {
+					return errors.Errorf("Deadline not set")
+				}
+				return nil
+			},
+		},
+		"logging passes through error": {
+			middleware: Logging(),
+			handler: func(_ context.Context, _, _ interface{}) error {
+				return errors.Errorf("Failed")
+			},
+			isErr:        true,
+			expectErrMsg: "Failed",
+		},
+		"handler sees info": {
+			middleware: Metrics(),
+			handler: func(ctx context.Context, _, _ interface{}) error {
+				if InfoFrom(ctx).Controller != "test-ctl" {
+					return errors.Errorf("Info not found")
+				}
+				return nil
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			err := Chain(mock.handler, mock.middleware)(ctx, mock.request, nil)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr && !strings.Contains(err.Error(), mock.expectErrMsg) {
+				t.Fatalf(
+					"Expected error with %q got %q",
+					mock.expectErrMsg,
+					err.Error(),
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package hooks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Registry holds the inline hooks by their function names
+//
+// NOTE:
+//	A registry is typically owned by a single metac server. This
+// lets multiple servers in the same binary e.g. tests run their own
+// set of inline hooks.
+type Registry struct {
+	mutex    sync.RWMutex
+	handlers map[string]Handler
+}
+
+// NewRegistry returns a new instance of Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		handlers: map[string]Handler{},
+	}
+}
+
+// Register adds the given handler against the given function name
+func (r *Registry) Register(funcName string, handler Handler) error {
+	if funcName == "" {
+		return errors.Errorf("Can't register inline hook: Empty function name")
+	}
+	if handler == nil {
+		return errors.Errorf(
+			"Can't register inline hook %q: Nil handler",
+			funcName,
+		)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, found := r.handlers[funcName]; found {
+		return errors.Errorf(
+			"Can't register inline hook %q: Already registered",
+			funcName,
+		)
+	}
+	r.handlers[funcName] = handler
+	return nil
+}
+
+// Unregister removes the handler of the given function name
+func (r *Registry) Unregister(funcName string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delete(r.handlers, funcName)
+}
+
+// Lookup returns the handler of the given function name
+func (r *Registry) Lookup(funcName string) (Handler, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	handler, found := r.handlers[funcName]
+	return handler, found
+}
+
+// FuncNames returns the sorted function names of the registered
+// handlers
+func (r *Registry) FuncNames() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var names []string
+	for name := range r.handlers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
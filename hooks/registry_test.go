@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package hooks
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	noop := func(_ context.Context, _, _ interface{}) error {
+		return nil
+	}
+	r := NewRegistry()
+
+	if err := r.Register("sync", noop); err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if err := r.Register("finalize", noop); err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if err := r.Register("sync", noop); err == nil {
+		t.Fatalf("Expected error for duplicate registration got none")
+	}
+	if err := r.Register("", noop); err == nil {
+		t.Fatalf("Expected error for empty name got none")
+	}
+	if err := r.Register("nil", nil); err == nil {
+		t.Fatalf("Expected error for nil handler got none")
+	}
+	names := r.FuncNames()
+	if !reflect.DeepEqual(names, []string{"finalize", "sync"}) {
+		t.Fatalf("Expected names [finalize sync] got %v", names)
+	}
+
+	if _, found := r.Lookup("sync"); !found {
+		t.Fatalf("Expected sync to be found")
+	}
+	r.Unregister("sync")
+	if _, found := r.Lookup("sync"); found {
+		t.Fatalf("Expected sync to be unregistered")
+	}
+	if err := r.Register("sync", noop); err != nil {
+		t.Fatalf("Expected no error post unregister got %+v", err)
+	}
+
+	var nilRegistry *Registry
+	if _, found := nilRegistry.Lookup("sync"); found {
+		t.Fatalf("Expected nil registry to find nothing")
+	}
+}
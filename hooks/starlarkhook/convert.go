@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package starlarkhook
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+)
+
+// toStarlark converts the given JSON compatible value into its
+// Starlark equivalent
+func toStarlark(v interface{}) (starlark.Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case string:
+		return starlark.String(v), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case float64:
+		return starlark.Float(v), nil
+	case []interface{}:
+		elems := make([]starlark.Value, 0, len(v))
+		for _, item := range v {
+			elem, err := toStarlark(item)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, elem)
+		}
+		return starlark.NewList(elems), nil
+	case map[string]interface{}:
+		// keys are sorted to let the scripts iterate the dicts in a
+		// deterministic order
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		dict := starlark.NewDict(len(v))
+		for _, key := range keys {
+			value, err := toStarlark(v[key])
+			if err != nil {
+				return nil, err
+			}
+			err = dict.SetKey(starlark.String(key), value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	}
+	return nil, errors.Errorf("Can't convert %T to starlark value", v)
+}
+
+// fromStarlark converts the given Starlark value into its JSON
+// compatible equivalent
+func fromStarlark(v starlark.Value) (interface{}, error) {
+	switch v := v.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, errors.Errorf("Can't convert %s: Int too large", v)
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case *starlark.List:
+		return fromStarlarkIterable(v)
+	case starlark.Tuple:
+		return fromStarlarkIterable(v)
+	case *starlark.Dict:
+		result := make(map[string]interface{}, v.Len())
+		for _, item := range v.Items() {
+			key, ok := item[0].(starlark.String)
+			if !ok {
+				return nil, errors.Errorf(
+					"Can't convert dict: Want string keys got %s",
+					item[0].Type(),
+				)
+			}
+			value, err := fromStarlark(item[1])
+			if err != nil {
+				return nil, err
+			}
+			result[string(key)] = value
+		}
+		return result, nil
+	}
+	return nil, errors.Errorf("Can't convert starlark %s", v.Type())
+}
+
+// fromStarlarkIterable converts the given list or tuple into its
+// JSON compatible equivalent
+func fromStarlarkIterable(v starlark.Iterable) ([]interface{}, error) {
+	result := []interface{}{}
+	iter := v.Iterate()
+	defer iter.Done()
+	var elem starlark.Value
+	for iter.Next(&elem) {
+		item, err := fromStarlark(elem)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
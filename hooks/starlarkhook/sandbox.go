@@ -0,0 +1,586 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package starlarkhook
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// Names of the builtins that are called by the instrumented script
+//
+// NOTE:
+//	These names can't be written in a script & hence can neither
+// be called nor be shadowed by the script
+const (
+	sizeFuncName   = "$size"
+	repeatFuncName = "$repeat"
+	methodFuncName = "$method"
+)
+
+// valueSize is the estimated size in bytes of a value held by a
+// collection
+const valueSize = 16
+
+// cappedBuiltins are the names of the universal builtins that build
+// a collection from an iterable. These are replaced by the sandbox
+// to verify the size of the collection before building it.
+var cappedBuiltins = []string{
+	"dict",
+	"enumerate",
+	"list",
+	"reversed",
+	"sorted",
+	"tuple",
+	"zip",
+}
+
+// cappedMethods are the names of the string methods whose results
+// are verified by the sandbox before being built
+var cappedMethods = map[string]bool{
+	"join":    true,
+	"replace": true,
+}
+
+// isSandboxBuiltin returns true if the given name refers to a
+// builtin of the sandbox
+func isSandboxBuiltin(name string) bool {
+	switch name {
+	case sizeFuncName, repeatFuncName, methodFuncName:
+		return true
+	}
+	for _, capped := range cappedBuiltins {
+		if name == capped {
+			return true
+		}
+	}
+	return false
+}
+
+// sandbox limits the memory of a single invocation of a script
+//
+// NOTE:
+//	Execution steps are limited by the Starlark thread that runs
+// the script. Memory is limited by instrumenting the script to call
+// the sandbox at the operations that build strings or collections.
+// Builtins & methods that build large values from their arguments
+// are verified before these values are built.
+//
+// NOTE:
+//	Memory is limited by the running total of the bytes allocated by
+// the invocation. Limiting the size of each value is not enough since
+// a script can build any number of values that are just below the
+// limit.
+type sandbox struct {
+	maxMemory int64
+
+	// estimated bytes allocated so far
+	allocated int64
+
+	// last seen sizes of the lists, dicts & sets
+	sizes map[starlark.Value]int64
+}
+
+// builtins returns the builtins that are called by the instrumented
+// script
+func (s *sandbox) builtins() starlark.StringDict {
+	builtins := starlark.StringDict{
+		sizeFuncName:   starlark.NewBuiltin(sizeFuncName, s.size),
+		repeatFuncName: starlark.NewBuiltin(repeatFuncName, s.repeat),
+		methodFuncName: starlark.NewBuiltin(methodFuncName, s.method),
+	}
+	for _, name := range cappedBuiltins {
+		builtins[name] = s.capBuiltin(starlark.Universe[name])
+	}
+	return builtins
+}
+
+// reserve errors out if the given bytes can't be allocated without
+// exceeding the limit
+//
+// NOTE:
+//	The bytes are not added to the allocated bytes since the value
+// built later is verified by the instrumented script
+func (s *sandbox) reserve(size int64) error {
+	if size > s.maxMemory-s.allocated {
+		return errors.Errorf(
+			"Exceeded max memory %d bytes: Can't allocate %d bytes",
+			s.maxMemory,
+			size,
+		)
+	}
+	return nil
+}
+
+// capBuiltin returns a builtin that verifies the size of the
+// collection built by the given builtin before calling it
+func (s *sandbox) capBuiltin(fn starlark.Value) *starlark.Builtin {
+	name := fn.(*starlark.Builtin).Name()
+	return starlark.NewBuiltin(
+		name,
+		func(
+			thread *starlark.Thread,
+			_ *starlark.Builtin,
+			args starlark.Tuple,
+			kwargs []starlark.Tuple,
+		) (starlark.Value, error) {
+			// the collection holds at least a value per item of
+			// its iterable arguments
+			var size int64
+			for _, arg := range args {
+				if n := starlark.Len(arg); n > 0 {
+					size += int64(n) * valueSize
+				}
+			}
+			err := s.reserve(size)
+			if err != nil {
+				return nil, err
+			}
+			return starlark.Call(thread, fn, args, kwargs)
+		},
+	)
+}
+
+// method returns the given method of the given receiver. A string
+// method that is capped is verified before it gets invoked.
+func (s *sandbox) method(
+	thread *starlark.Thread,
+	fn *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	recv, name := args[0], string(args[1].(starlark.String))
+	method, err := getAttr(recv, name)
+	if err != nil {
+		return nil, err
+	}
+	str, isString := recv.(starlark.String)
+	if !isString || !cappedMethods[name] {
+		return method, nil
+	}
+	return starlark.NewBuiltin(
+		name,
+		func(
+			thread *starlark.Thread,
+			_ *starlark.Builtin,
+			args starlark.Tuple,
+			kwargs []starlark.Tuple,
+		) (starlark.Value, error) {
+			err := s.reserve(stringMethodSize(str, name, args))
+			if err != nil {
+				return nil, err
+			}
+			return starlark.Call(thread, method, args, kwargs)
+		},
+	), nil
+}
+
+// getAttr returns the given attribute of the given value
+func getAttr(v starlark.Value, name string) (starlark.Value, error) {
+	hasAttrs, ok := v.(starlark.HasAttrs)
+	if !ok {
+		return nil, errors.Errorf("%s has no .%s field or method", v.Type(), name)
+	}
+	attr, err := hasAttrs.Attr(name)
+	if err != nil {
+		return nil, err
+	}
+	if attr == nil {
+		return nil, errors.Errorf("%s has no .%s field or method", v.Type(), name)
+	}
+	return attr, nil
+}
+
+// stringMethodSize returns the estimated size in bytes of the string
+// that is built by the given method of the given string
+func stringMethodSize(str starlark.String, name string, args starlark.Tuple) int64 {
+	switch name {
+	case "join":
+		if len(args) == 0 {
+			return 0
+		}
+		iter := starlark.Iterate(args[0])
+		if iter == nil {
+			return 0
+		}
+		defer iter.Done()
+		var size, count int64
+		var item starlark.Value
+		for iter.Next(&item) {
+			if itemStr, ok := item.(starlark.String); ok {
+				size += int64(len(itemStr))
+			}
+			count++
+		}
+		if count > 1 {
+			size += int64(len(str)) * (count - 1)
+		}
+		return size
+	case "replace":
+		if len(args) < 2 {
+			return 0
+		}
+		old, isOldString := args[0].(starlark.String)
+		new, isNewString := args[1].(starlark.String)
+		if !isOldString || !isNewString || len(new) <= len(old) {
+			return 0
+		}
+		count := int64(strings.Count(string(str), string(old)))
+		if len(args) > 2 {
+			if n, ok := args[2].(starlark.Int); ok {
+				if max, ok := n.Int64(); ok && max >= 0 && max < count {
+					count = max
+				}
+			}
+		}
+		return int64(len(str)) + count*int64(len(new)-len(old))
+	}
+	return 0
+}
+
+// size verifies the sizes of the given values & returns the first
+// one
+func (s *sandbox) size(
+	thread *starlark.Thread,
+	fn *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	for _, arg := range args {
+		err := s.allocate(arg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return args[0], nil
+}
+
+// repeat verifies the size of a repeated string or collection before
+// building it
+func (s *sandbox) repeat(
+	thread *starlark.Thread,
+	fn *starlark.Builtin,
+	args starlark.Tuple,
+	kwargs []starlark.Tuple,
+) (starlark.Value, error) {
+	x, y := args[0], args[1]
+	size, count := sizeOf(x), y
+	if _, isInt := x.(starlark.Int); isInt {
+		size, count = sizeOf(y), x
+	}
+	if n, ok := count.(starlark.Int); ok {
+		if times, ok := n.Int64(); ok && times > 0 {
+			if size > 0 && times > (s.maxMemory-s.allocated)/size {
+				return nil, errors.Errorf(
+					"Exceeded max memory %d bytes",
+					s.maxMemory,
+				)
+			}
+		}
+	}
+	result, err := starlark.Binary(syntax.STAR, x, y)
+	if err != nil {
+		return nil, err
+	}
+	err = s.allocate(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// allocate adds the estimated size of the given value to the bytes
+// allocated so far & errors out once these exceed the limit
+//
+// NOTE:
+//	Lists, dicts & sets grow in place & are verified every time they
+// grow. Hence only their growth since they were last seen is added.
+func (s *sandbox) allocate(v starlark.Value) error {
+	size := sizeOf(v)
+	switch v.(type) {
+	case *starlark.List, *starlark.Dict, *starlark.Set:
+		if s.sizes == nil {
+			s.sizes = make(map[starlark.Value]int64)
+		}
+		last, seen := s.sizes[v]
+		s.sizes[v] = size
+		if seen {
+			size -= last
+		}
+		if size < 0 {
+			size = 0
+		}
+	}
+	s.allocated += size
+	if s.allocated > s.maxMemory {
+		return errors.Errorf(
+			"Exceeded max memory %d bytes: Allocated %d bytes",
+			s.maxMemory,
+			s.allocated,
+		)
+	}
+	return nil
+}
+
+// sizeOf returns the estimated size in bytes of the given value
+// excluding the values it holds
+func sizeOf(v starlark.Value) int64 {
+	switch v := v.(type) {
+	case starlark.String:
+		return int64(len(v))
+	case starlark.Int:
+		if _, ok := v.Int64(); ok {
+			// small ints are not counted since arithmetic in loops
+			// would otherwise add up
+			return 0
+		}
+		return int64(v.BigInt().BitLen() / 8)
+	case starlark.Sequence:
+		if v.Type() == "range" {
+			// range does not hold its values
+			return 0
+		}
+		return int64(v.Len()) * valueSize
+	}
+	return 0
+}
+
+// instrument rewrites the given file to call the sandbox builtins
+func instrument(f *syntax.File) {
+	f.Stmts = instrumentStmts(f.Stmts)
+}
+
+// newSandboxCall returns an expression that calls the given
+// sandbox builtin with the given arguments
+func newSandboxCall(pos syntax.Position, name string, args ...syntax.Expr) *syntax.CallExpr {
+	return &syntax.CallExpr{
+		Fn:     &syntax.Ident{NamePos: pos, Name: name},
+		Lparen: pos,
+		Args:   args,
+		Rparen: pos,
+	}
+}
+
+// instrumentStmts instruments the given statements
+func instrumentStmts(stmts []syntax.Stmt) []syntax.Stmt {
+	var result []syntax.Stmt
+	for _, stmt := range stmts {
+		result = append(result, instrumentStmt(stmt)...)
+	}
+	return result
+}
+
+// instrumentStmt instruments the given statement & returns the
+// statements that replace it
+func instrumentStmt(stmt syntax.Stmt) []syntax.Stmt {
+	switch stmt := stmt.(type) {
+	case *syntax.AssignStmt:
+		stmt.RHS = instrumentExpr(stmt.RHS)
+		lhs, isIdent := stmt.LHS.(*syntax.Ident)
+		if !isIdent {
+			stmt.LHS = instrumentExpr(stmt.LHS)
+			return []syntax.Stmt{stmt}
+		}
+		switch stmt.Op {
+		case syntax.STAR_EQ:
+			// x *= n is evaluated as x = x * n to verify its size
+			// before building it
+			stmt.Op = syntax.EQ
+			stmt.RHS = newSandboxCall(
+				stmt.OpPos,
+				repeatFuncName,
+				&syntax.Ident{NamePos: lhs.NamePos, Name: lhs.Name},
+				stmt.RHS,
+			)
+		case syntax.PLUS_EQ, syntax.PERCENT_EQ:
+			// NOTE:
+			//	x += y extends a list in place & hence its size is
+			// verified after the assignment
+			return []syntax.Stmt{
+				stmt,
+				&syntax.ExprStmt{
+					X: newSandboxCall(
+						stmt.OpPos,
+						sizeFuncName,
+						&syntax.Ident{NamePos: lhs.NamePos, Name: lhs.Name},
+					),
+				},
+			}
+		}
+		return []syntax.Stmt{stmt}
+	case *syntax.DefStmt:
+		instrumentParams(stmt.Params)
+		stmt.Body = instrumentStmts(stmt.Body)
+	case *syntax.ExprStmt:
+		stmt.X = instrumentExpr(stmt.X)
+	case *syntax.ForStmt:
+		stmt.X = instrumentExpr(stmt.X)
+		stmt.Body = instrumentStmts(stmt.Body)
+	case *syntax.WhileStmt:
+		stmt.Cond = instrumentExpr(stmt.Cond)
+		stmt.Body = instrumentStmts(stmt.Body)
+	case *syntax.IfStmt:
+		stmt.Cond = instrumentExpr(stmt.Cond)
+		stmt.True = instrumentStmts(stmt.True)
+		stmt.False = instrumentStmts(stmt.False)
+	case *syntax.ReturnStmt:
+		if stmt.Result != nil {
+			stmt.Result = instrumentExpr(stmt.Result)
+		}
+	}
+	return []syntax.Stmt{stmt}
+}
+
+// instrumentParams instruments the default values of the given
+// function parameters
+func instrumentParams(params []syntax.Expr) {
+	for _, param := range params {
+		if binary, ok := param.(*syntax.BinaryExpr); ok && binary.Op == syntax.EQ {
+			binary.Y = instrumentExpr(binary.Y)
+		}
+	}
+}
+
+// instrumentExprs instruments the given expressions in place
+func instrumentExprs(exprs []syntax.Expr) {
+	for i := range exprs {
+		exprs[i] = instrumentExpr(exprs[i])
+	}
+}
+
+// instrumentExpr instruments the given expression & returns the
+// expression that replaces it
+func instrumentExpr(expr syntax.Expr) syntax.Expr {
+	switch expr := expr.(type) {
+	case *syntax.BinaryExpr:
+		expr.X = instrumentExpr(expr.X)
+		expr.Y = instrumentExpr(expr.Y)
+		switch expr.Op {
+		case syntax.STAR:
+			return newSandboxCall(expr.OpPos, repeatFuncName, expr.X, expr.Y)
+		case syntax.PLUS, syntax.PERCENT:
+			return newSandboxCall(expr.OpPos, sizeFuncName, expr)
+		}
+	case *syntax.CallExpr:
+		// NOTE:
+		//	The receiver of a method call is verified as well since
+		// methods like list.extend grow their receivers
+		var recv *syntax.Ident
+		if dot, ok := expr.Fn.(*syntax.DotExpr); ok {
+			recv, _ = dot.X.(*syntax.Ident)
+			// x.name(args) is evaluated as $method(x, "name")(args)
+			// to verify the string built by the method before
+			// building it
+			expr.Fn = newSandboxCall(
+				dot.Dot,
+				methodFuncName,
+				instrumentExpr(dot.X),
+				&syntax.Literal{
+					Token:    syntax.STRING,
+					TokenPos: dot.Name.NamePos,
+					Raw:      strconv.Quote(dot.Name.Name),
+					Value:    dot.Name.Name,
+				},
+			)
+		} else {
+			expr.Fn = instrumentExpr(expr.Fn)
+		}
+		for i, arg := range expr.Args {
+			switch arg := arg.(type) {
+			case *syntax.BinaryExpr:
+				if arg.Op == syntax.EQ {
+					// keyword argument
+					arg.Y = instrumentExpr(arg.Y)
+					continue
+				}
+			case *syntax.UnaryExpr:
+				if arg.Op == syntax.STAR || arg.Op == syntax.STARSTAR {
+					// *args or **kwargs
+					arg.X = instrumentExpr(arg.X)
+					continue
+				}
+			}
+			expr.Args[i] = instrumentExpr(arg)
+		}
+		args := []syntax.Expr{expr}
+		if recv != nil {
+			args = append(
+				args,
+				&syntax.Ident{NamePos: recv.NamePos, Name: recv.Name},
+			)
+		}
+		return newSandboxCall(expr.Lparen, sizeFuncName, args...)
+	case *syntax.Comprehension:
+		for _, clause := range expr.Clauses {
+			switch clause := clause.(type) {
+			case *syntax.ForClause:
+				clause.X = instrumentExpr(clause.X)
+			case *syntax.IfClause:
+				clause.Cond = instrumentExpr(clause.Cond)
+			}
+		}
+		expr.Body = instrumentExpr(expr.Body)
+		// NOTE:
+		//	The built collection is verified once its items are
+		// added. Its growth till then is limited by the execution
+		// steps.
+		return newSandboxCall(expr.Lbrack, sizeFuncName, expr)
+	case *syntax.CondExpr:
+		expr.Cond = instrumentExpr(expr.Cond)
+		expr.True = instrumentExpr(expr.True)
+		expr.False = instrumentExpr(expr.False)
+	case *syntax.DictEntry:
+		expr.Key = instrumentExpr(expr.Key)
+		expr.Value = instrumentExpr(expr.Value)
+	case *syntax.DictExpr:
+		instrumentExprs(expr.List)
+	case *syntax.DotExpr:
+		expr.X = instrumentExpr(expr.X)
+	case *syntax.IndexExpr:
+		expr.X = instrumentExpr(expr.X)
+		expr.Y = instrumentExpr(expr.Y)
+	case *syntax.LambdaExpr:
+		instrumentParams(expr.Params)
+		expr.Body = instrumentExpr(expr.Body)
+	case *syntax.ListExpr:
+		instrumentExprs(expr.List)
+	case *syntax.ParenExpr:
+		expr.X = instrumentExpr(expr.X)
+	case *syntax.SliceExpr:
+		expr.X = instrumentExpr(expr.X)
+		if expr.Lo != nil {
+			expr.Lo = instrumentExpr(expr.Lo)
+		}
+		if expr.Hi != nil {
+			expr.Hi = instrumentExpr(expr.Hi)
+		}
+		if expr.Step != nil {
+			expr.Step = instrumentExpr(expr.Step)
+		}
+	case *syntax.TupleExpr:
+		instrumentExprs(expr.List)
+	case *syntax.UnaryExpr:
+		if expr.X != nil {
+			expr.X = instrumentExpr(expr.X)
+		}
+	}
+	return expr
+}
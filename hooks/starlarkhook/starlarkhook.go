@@ -0,0 +1,298 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package starlarkhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+	"k8s.io/apimachinery/pkg/util/json"
+
+	"openebs.io/metac/hooks/grpchook"
+	"openebs.io/metac/metrics"
+)
+
+const (
+	// DefaultFunction is the function that gets invoked if the
+	// request does not match any specific hook
+	DefaultFunction = "sync"
+
+	// DefaultMaxExecutionSteps is the default maximum number of
+	// Starlark computation steps of a single invocation
+	DefaultMaxExecutionSteps int64 = 10000000
+
+	// DefaultMaxMemory is the default maximum estimated bytes that
+	// are allocated by the strings & collections built by a single
+	// invocation
+	DefaultMaxMemory int64 = 64 * 1024 * 1024
+
+	// DefaultTimeout is the default time after which a single
+	// invocation is cancelled
+	DefaultTimeout = 10 * time.Second
+)
+
+// Invoker manages evaluation of a Starlark script as a hook
+type Invoker struct {
+	// name of the script used in logs & errors
+	Filename string
+
+	// SourceFn returns the Starlark script
+	SourceFn func() (string, error)
+
+	// Function is the script's function that gets invoked. This
+	// defaults to the function that matches the request.
+	Function string
+
+	// limits of a single invocation
+	MaxExecutionSteps int64
+	MaxMemory         int64
+	Timeout           time.Duration
+
+	// mutex guards the compiled program
+	mutex sync.Mutex
+
+	// source of the compiled program
+	source string
+
+	// program compiled from source
+	program *starlark.Program
+}
+
+// InvokerOption is a typed function that is used
+// to build *Invoker instance
+//
+// NOTE:
+//	This follows "functional options" pattern
+type InvokerOption func(*Invoker) error
+
+// NewInvoker returns a new instance of Invoker
+// based on an optional list of InvokerOptions
+func NewInvoker(opts ...InvokerOption) (*Invoker, error) {
+	i := &Invoker{
+		MaxExecutionSteps: DefaultMaxExecutionSteps,
+		MaxMemory:         DefaultMaxMemory,
+		Timeout:           DefaultTimeout,
+	}
+	for _, o := range opts {
+		err := o(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if i.Filename == "" {
+		return nil, errors.Errorf("%s: Filename can't be empty", i)
+	}
+	if i.SourceFn == nil {
+		return nil, errors.Errorf("%s: SourceFn can't be nil", i)
+	}
+	if i.MaxExecutionSteps <= 0 || i.MaxMemory <= 0 || i.Timeout <= 0 {
+		return nil, errors.Errorf("%s: Limits must be > 0", i)
+	}
+	return i, nil
+}
+
+// String implements Stringer interface
+func (i *Invoker) String() string {
+	return fmt.Sprintf("Starlark Invoker: Filename=%s", i.Filename)
+}
+
+// functionOf returns the name of the script's function that gets
+// invoked for the given request
+//
+// NOTE:
+//	Function is derived from the gRPC method of the request e.g.
+// '/metac.hooks.v1.GenericHook/Finalize' results in 'finalize'
+func (i *Invoker) functionOf(request interface{}) string {
+	if i.Function != "" {
+		return i.Function
+	}
+	getter, ok := request.(grpchook.MethodGetter)
+	if !ok {
+		return DefaultFunction
+	}
+	method := getter.GRPCMethod()
+	name := method[strings.LastIndex(method, "/")+1:]
+	if name == "" {
+		return DefaultFunction
+	}
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// Invoke this script's function with the given request & fill up
+// the given response from the function's result
+func (i *Invoker) Invoke(request, response interface{}) error {
+	return i.InvokeContext(context.Background(), request, response)
+}
+
+// InvokeContext invokes this script's function with the given
+// request & fills up the given response from the function's result.
+// The script is cancelled once the given context is done.
+func (i *Invoker) InvokeContext(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	start := time.Now()
+	err := i.invoke(ctx, request, response)
+	metrics.RecordHook(i.Filename, start, err)
+	return err
+}
+
+// invoke this script's function with the given request & fill up
+// the given response from the function's result
+func (i *Invoker) invoke(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	program, err := i.getProgram()
+	if err != nil {
+		return err
+	}
+	reqBody, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to marshal", i)
+	}
+	glog.V(8).Infof("%s: Will invoke %s", i, reqBody)
+
+	var reqObj interface{}
+	err = json.Unmarshal(reqBody, &reqObj)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to unmarshal request", i)
+	}
+	reqValue, err := toStarlark(reqObj)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to convert request", i)
+	}
+
+	sb := &sandbox{
+		maxMemory: i.MaxMemory,
+	}
+	thread := &starlark.Thread{
+		Name: i.Filename,
+		Print: func(_ *starlark.Thread, msg string) {
+			glog.V(4).Infof("%s: %s", i, msg)
+		},
+		OnMaxSteps: func(thread *starlark.Thread) {
+			thread.Cancel(
+				fmt.Sprintf("Exceeded max execution steps %d", i.MaxExecutionSteps),
+			)
+		},
+	}
+	thread.SetMaxExecutionSteps(uint64(i.MaxExecutionSteps))
+	// NOTE:
+	//	A cancelled thread fails at its next computation step. A
+	// builtin that is running by then is not interrupted.
+	timer := time.AfterFunc(i.Timeout, func() {
+		thread.Cancel(fmt.Sprintf("Timed out after %s", i.Timeout))
+	})
+	defer timer.Stop()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(fmt.Sprintf("Cancelled: %v", ctx.Err()))
+		case <-stop:
+		}
+	}()
+
+	globals, err := program.Init(thread, sb.builtins())
+	if err != nil {
+		return errors.Errorf("%s: Failed to init: %s", i, errorOf(err))
+	}
+	function := i.functionOf(request)
+	fn, ok := globals[function].(starlark.Callable)
+	if !ok {
+		return errors.Errorf("%s: Function %q is not defined", i, function)
+	}
+	result, err := starlark.Call(
+		thread,
+		fn,
+		starlark.Tuple{reqValue},
+		nil,
+	)
+	if err != nil {
+		return errors.Errorf(
+			"%s: Failed to invoke %q: %s",
+			i,
+			function,
+			errorOf(err),
+		)
+	}
+	respObj, err := fromStarlark(result)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Invalid result of %q", i, function)
+	}
+	respBody, err := json.Marshal(respObj)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to marshal response", i)
+	}
+	glog.V(8).Infof("%s: Got response %s", i, respBody)
+
+	err = json.Unmarshal(respBody, response)
+	if err != nil {
+		return errors.Wrapf(err, "%s: Failed to unmarshal response", i)
+	}
+	glog.V(8).Infof("%s: Invoked successfully", i)
+	return nil
+}
+
+// getProgram returns the compiled program. Program is compiled
+// only when its source has changed since the last invocation.
+func (i *Invoker) getProgram() (*starlark.Program, error) {
+	source, err := i.SourceFn()
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Failed to get source", i)
+	}
+
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.program != nil && i.source == source {
+		return i.program, nil
+	}
+	// NOTE:
+	//	Default options disallow 'while' statements & recursion
+	f, err := (&syntax.FileOptions{}).Parse(i.Filename, source, 0)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Failed to parse", i)
+	}
+	instrument(f)
+	program, err := starlark.FileProgram(f, isSandboxBuiltin)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Failed to compile", i)
+	}
+	glog.V(4).Infof("%s: Compiled program", i)
+	i.source = source
+	i.program = program
+	return program, nil
+}
+
+// errorOf returns the message of the given error including the
+// Starlark backtrace if available
+func errorOf(err error) string {
+	if evalErr, ok := err.(*starlark.EvalError); ok {
+		return evalErr.Backtrace()
+	}
+	return err.Error()
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package starlarkhook
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"openebs.io/metac/hooks/grpchook"
+)
+
+// finalizeRequest is a request that is meant for the finalize hook
+type finalizeRequest struct {
+	Name string `json:"name"`
+}
+
+// GRPCMethod implements grpchook.MethodGetter interface
+func (r finalizeRequest) GRPCMethod() string {
+	return grpchook.GenericFinalizeMethod
+}
+
+func TestInvoke(t *testing.T) {
+	var tests = map[string]struct {
+		source       string
+		function     string
+		request      interface{}
+		maxSteps     int64
+		maxMemory    int64
+		timeout      time.Duration
+		expect       map[string]interface{}
+		isErr        bool
+		expectErrMsg string
+	}{
+		"sync is invoked by default": {
+			source: `
+def sync(request):
+    return {"name": request["name"] + "-synced"}
+`,
+			expect: map[string]interface{}{"name": "test-synced"},
+		},
+		"finalize is invoked for finalize request": {
+			source: `
+def sync(request):
+    return {"name": "synced"}
+
+def finalize(request):
+    return {"name": request["name"] + "-finalized"}
+`,
+			request: finalizeRequest{Name: "test"},
+			expect:  map[string]interface{}{"name": "test-finalized"},
+		},
+		"function is overridden": {
+			source: `
+def reconcile(request):
+    return {"name": "reconciled"}
+`,
+			function: "reconcile",
+			expect:   map[string]interface{}{"name": "reconciled"},
+		},
+		"values are converted": {
+			source: `
+def sync(request):
+    return {
+        "int": 1,
+        "bool": True,
+        "none": None,
+        "list": [request["name"], 2],
+        "tuple": (3,),
+    }
+`,
+			expect: map[string]interface{}{
+				"int":   int64(1),
+				"bool":  true,
+				"none":  nil,
+				"list":  []interface{}{"test", int64(2)},
+				"tuple": []interface{}{int64(3)},
+			},
+		},
+		"function is not defined": {
+			source: `
+def finalize(request):
+    return {}
+`,
+			isErr:        true,
+			expectErrMsg: `Function "sync" is not defined`,
+		},
+		"script fails": {
+			source: `
+def sync(request):
+    fail("bad request")
+`,
+			isErr:        true,
+			expectErrMsg: "bad request",
+		},
+		"invalid result": {
+			source: `
+def sync(request):
+    return {1: "one"}
+`,
+			isErr: true,
+		},
+		"load is not supported": {
+			source: `
+load("lib.star", "helper")
+
+def sync(request):
+    return {}
+`,
+			isErr:        true,
+			expectErrMsg: "load not implemented",
+		},
+		"steps within limit": {
+			source: `
+def sync(request):
+    count = 0
+    for i in range(10):
+        count += 1
+    return {"count": count}
+`,
+			maxSteps: 1000,
+			expect:   map[string]interface{}{"count": int64(10)},
+		},
+		"nested loops exceed steps": {
+			source: `
+def sync(request):
+    count = 0
+    for i in range(1000):
+        for j in range(1000):
+            count += 1
+    return {"count": count}
+`,
+			maxSteps:     1000,
+			isErr:        true,
+			expectErrMsg: "Exceeded max execution steps 1000",
+		},
+		"comprehension exceeds steps": {
+			source: `
+def sync(request):
+    return {"items": [i for i in range(1000)]}
+`,
+			maxSteps:     100,
+			isErr:        true,
+			expectErrMsg: "Exceeded max execution steps 100",
+		},
+		"repeated string exceeds memory": {
+			source: `
+def sync(request):
+    return {"name": "x" * 1000000000}
+`,
+			maxMemory:    1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1024 bytes",
+		},
+		"repeat assignment exceeds memory": {
+			source: `
+def sync(request):
+    items = [1]
+    items *= 1000000000
+    return {}
+`,
+			maxMemory:    1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1024 bytes",
+		},
+		"doubling string exceeds memory": {
+			source: `
+def sync(request):
+    name = "x"
+    for i in range(100):
+        name += name
+    return {"name": name}
+`,
+			maxMemory:    1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1024 bytes",
+		},
+		"many values below limit exceed memory": {
+			source: `
+def sync(request):
+    return {"items": [("a" * 60000000) for _ in range(100)]}
+`,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 67108864 bytes",
+		},
+		"builtin exceeds memory before allocating": {
+			source: `
+def sync(request):
+    x = list(range(20000000))
+    return {}
+`,
+			maxMemory:    1024 * 1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1048576 bytes: Can't allocate 320000000 bytes",
+		},
+		"sorted exceeds memory before allocating": {
+			source: `
+def sync(request):
+    return {"items": sorted(range(20000000))}
+`,
+			maxMemory:    1024 * 1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1048576 bytes",
+		},
+		"join exceeds memory before allocating": {
+			source: `
+def sync(request):
+    sep = "x" * 1000
+    return {"name": sep.join(["a"] * 2000)}
+`,
+			maxMemory:    1024 * 1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1048576 bytes",
+		},
+		"replace exceeds memory before allocating": {
+			source: `
+def sync(request):
+    name = "a" * 1000
+    return {"name": name.replace("a", "b" * 10000)}
+`,
+			maxMemory:    1024 * 1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1048576 bytes",
+		},
+		"methods within limit": {
+			source: `
+def sync(request):
+    names = ",".join(["a", "b"]).replace(",", "-").split("-")
+    return {"names": names}
+`,
+			maxMemory: 1024,
+			expect: map[string]interface{}{
+				"names": []interface{}{"a", "b"},
+			},
+		},
+		"timeout cancels invocation": {
+			source: `
+def sync(request):
+    count = 0
+    for i in range(1000000000):
+        count += 1
+    return {"count": count}
+`,
+			maxSteps:     1 << 40,
+			timeout:      10 * time.Millisecond,
+			isErr:        true,
+			expectErrMsg: "Timed out after 10ms",
+		},
+		"appends to a list are counted once": {
+			source: `
+def sync(request):
+    items = []
+    for i in range(1000):
+        items.append(i)
+    return {"count": len(items)}
+`,
+			maxMemory: 32 * 1024,
+			expect:    map[string]interface{}{"count": int64(1000)},
+		},
+		"doubling list exceeds memory": {
+			source: `
+def sync(request):
+    items = [1]
+    for i in range(100):
+        items.extend(items)
+    return {}
+`,
+			maxMemory:    1024,
+			isErr:        true,
+			expectErrMsg: "Exceeded max memory 1024 bytes",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			i, err := NewInvoker(func(i *Invoker) error {
+				i.Filename = "test.star"
+				i.SourceFn = func() (string, error) {
+					return mock.source, nil
+				}
+				i.Function = mock.function
+				if mock.maxSteps != 0 {
+					i.MaxExecutionSteps = mock.maxSteps
+				}
+				if mock.maxMemory != 0 {
+					i.MaxMemory = mock.maxMemory
+				}
+				if mock.timeout != 0 {
+					i.Timeout = mock.timeout
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			request := mock.request
+			if request == nil {
+				request = map[string]string{"name": "test"}
+			}
+			var resp map[string]interface{}
+			err = i.Invoke(request, &resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				if !strings.Contains(err.Error(), mock.expectErrMsg) {
+					t.Fatalf(
+						"Expected error with %q got %q",
+						mock.expectErrMsg,
+						err.Error(),
+					)
+				}
+				return
+			}
+			if !reflect.DeepEqual(resp, mock.expect) {
+				t.Fatalf("Expected response %#v got %#v", mock.expect, resp)
+			}
+		})
+	}
+}
+
+func TestInvokeRecompilesChangedSource(t *testing.T) {
+	source := `
+def sync(request):
+    return {"name": "v1"}
+`
+	i, err := NewInvoker(func(i *Invoker) error {
+		i.Filename = "test.star"
+		i.SourceFn = func() (string, error) {
+			return source, nil
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	var resp map[string]string
+	err = i.Invoke(map[string]string{}, &resp)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if resp["name"] != "v1" {
+		t.Fatalf("Expected name v1 got %q", resp["name"])
+	}
+	compiled := i.program
+
+	// unchanged source reuses the compiled program
+	err = i.Invoke(map[string]string{}, &resp)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if i.program != compiled {
+		t.Fatalf("Expected compiled program to be reused")
+	}
+
+	source = `
+def sync(request):
+    return {"name": "v2"}
+`
+	err = i.Invoke(map[string]string{}, &resp)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if resp["name"] != "v2" {
+		t.Fatalf("Expected name v2 got %q", resp["name"])
+	}
+}
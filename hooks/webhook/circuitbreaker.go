@@ -0,0 +1,242 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"openebs.io/metac/metrics"
+)
+
+// Defaults used by the circuit breaker
+const (
+	// DefaultCircuitFailureThreshold is the number of consecutive
+	// failures after which the circuit opens
+	DefaultCircuitFailureThreshold = 5
+
+	// DefaultCircuitOpenDuration is the time for which the circuit
+	// stays open before a trial invocation is allowed
+	DefaultCircuitOpenDuration = 30 * time.Second
+)
+
+// circuitState is the state of a circuit breaker
+type circuitState int
+
+const (
+	// circuitClosed allows all the invocations
+	circuitClosed circuitState = iota
+
+	// circuitOpen short-circuits all the invocations
+	circuitOpen
+
+	// circuitHalfOpen allows a single trial invocation whose
+	// result either closes or re-opens the circuit
+	circuitHalfOpen
+)
+
+// CircuitOpenError is returned when an invocation is
+// short-circuited
+type CircuitOpenError struct {
+	// hook whose circuit is open
+	Hook string
+
+	// time after which a trial invocation is allowed
+	RetryAfter time.Duration
+}
+
+// Error implements error interface
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf(
+		"Circuit is open for hook %s: Retry after %s",
+		e.Hook,
+		e.RetryAfter,
+	)
+}
+
+// IsCircuitOpenError returns true if the provided error or its
+// cause is a CircuitOpenError
+func IsCircuitOpenError(err error) bool {
+	_, ok := errors.Cause(err).(*CircuitOpenError)
+	return ok
+}
+
+// CircuitBreaker short-circuits the invocations of a hook after
+// it fails consecutively
+type CircuitBreaker struct {
+	// hook url
+	Hook string
+
+	// consecutive failures after which the circuit opens
+	FailureThreshold int
+
+	// time for which the circuit stays open
+	OpenDuration time.Duration
+
+	mutex               sync.Mutex
+	state               circuitState
+	consecutiveFailures int
+	openedAt            time.Time
+
+	// true if the trial invocation is in progress
+	isTrialInProgress bool
+
+	// returns the current time; useful for testing
+	now func() time.Time
+}
+
+var (
+	circuitBreakersMutex sync.Mutex
+
+	// circuit breakers anchored by hook definition
+	circuitBreakers = map[string]*CircuitBreaker{}
+)
+
+// GetOrCreateCircuitBreaker returns the circuit breaker of the
+// given hook definition. A new circuit breaker is created if none
+// exists. Provided settings are applied to the existing circuit
+// breaker since these might have changed.
+//
+// NOTE:
+//	Hook is the url of the hook that is used in logs & metrics.
+// Hooks with the same url but different definitions get their own
+// circuit breakers.
+func GetOrCreateCircuitBreaker(
+	definition string,
+	hook string,
+	failureThreshold int,
+	openDuration time.Duration,
+) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		failureThreshold = DefaultCircuitFailureThreshold
+	}
+	if openDuration <= 0 {
+		openDuration = DefaultCircuitOpenDuration
+	}
+	circuitBreakersMutex.Lock()
+	defer circuitBreakersMutex.Unlock()
+
+	cb, found := circuitBreakers[definition]
+	if !found {
+		cb = &CircuitBreaker{
+			Hook: hook,
+			now:  time.Now,
+		}
+		circuitBreakers[definition] = cb
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	cb.FailureThreshold = failureThreshold
+	cb.OpenDuration = openDuration
+	return cb
+}
+
+// Allow returns a CircuitOpenError if the invocation needs to be
+// short-circuited
+//
+// NOTE:
+//	This is safe to be invoked on a nil circuit breaker
+func (cb *CircuitBreaker) Allow() error {
+	if cb == nil {
+		return nil
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	switch cb.state {
+	case circuitOpen:
+		elapsed := cb.now().Sub(cb.openedAt)
+		if elapsed < cb.OpenDuration {
+			return &CircuitOpenError{
+				Hook:       cb.Hook,
+				RetryAfter: cb.OpenDuration - elapsed,
+			}
+		}
+		glog.V(4).Infof("Circuit is half open for hook %s", cb.Hook)
+		cb.state = circuitHalfOpen
+		cb.isTrialInProgress = true
+		return nil
+	case circuitHalfOpen:
+		if cb.isTrialInProgress {
+			return &CircuitOpenError{
+				Hook:       cb.Hook,
+				RetryAfter: cb.OpenDuration,
+			}
+		}
+		cb.isTrialInProgress = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// RecordResult updates the circuit based on the result of an
+// invocation that was allowed
+//
+// NOTE:
+//	This is safe to be invoked on a nil circuit breaker
+func (cb *CircuitBreaker) RecordResult(err error) {
+	if cb == nil {
+		return
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	if cb.state == circuitHalfOpen {
+		cb.isTrialInProgress = false
+	}
+	if err == nil {
+		if cb.state != circuitClosed {
+			glog.Infof("Circuit is closed for hook %s", cb.Hook)
+			metrics.RecordHookCircuitOpen(cb.Hook, false)
+		}
+		cb.state = circuitClosed
+		cb.consecutiveFailures = 0
+		return
+	}
+	cb.consecutiveFailures++
+	if cb.state == circuitHalfOpen ||
+		cb.consecutiveFailures >= cb.FailureThreshold {
+		if cb.state == circuitClosed {
+			glog.Infof(
+				"Circuit is open for hook %s: %d consecutive failures: %v",
+				cb.Hook,
+				cb.consecutiveFailures,
+				err,
+			)
+			metrics.RecordHookCircuitOpen(cb.Hook, true)
+		}
+		cb.state = circuitOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// IsOpen returns true if the invocations are being short-circuited
+func (cb *CircuitBreaker) IsOpen() bool {
+	if cb == nil {
+		return false
+	}
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	return cb.state != circuitClosed
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Now()
+	cb := &CircuitBreaker{
+		Hook:             "test-hook",
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+		now: func() time.Time {
+			return now
+		},
+	}
+	failure := errors.Errorf("failed")
+
+	// consecutive failures below threshold keep the circuit closed
+	for i := 0; i < 2; i++ {
+		if err := cb.Allow(); err != nil {
+			t.Fatalf("Expected closed circuit at failure %d got %v", i, err)
+		}
+		cb.RecordResult(failure)
+	}
+	if !cb.IsOpen() {
+		t.Fatalf("Expected open circuit after threshold failures")
+	}
+
+	// open circuit short-circuits
+	err := cb.Allow()
+	if !IsCircuitOpenError(errors.Wrapf(err, "wrapped")) {
+		t.Fatalf("Expected circuit open error got %v", err)
+	}
+
+	// half open circuit allows a single trial
+	now = now.Add(time.Minute)
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("Expected trial invocation got %v", err)
+	}
+	if err := cb.Allow(); !IsCircuitOpenError(err) {
+		t.Fatalf("Expected circuit open error during trial got %v", err)
+	}
+
+	// failed trial re-opens the circuit
+	cb.RecordResult(failure)
+	if err := cb.Allow(); !IsCircuitOpenError(err) {
+		t.Fatalf("Expected circuit open error after failed trial got %v", err)
+	}
+
+	// successful trial closes the circuit
+	now = now.Add(time.Minute)
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("Expected trial invocation got %v", err)
+	}
+	cb.RecordResult(nil)
+	if cb.IsOpen() {
+		t.Fatalf("Expected closed circuit after successful trial")
+	}
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("Expected closed circuit got %v", err)
+	}
+}
+
+func TestNilCircuitBreaker(t *testing.T) {
+	var cb *CircuitBreaker
+	if err := cb.Allow(); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	cb.RecordResult(errors.Errorf("failed"))
+	if cb.IsOpen() {
+		t.Fatalf("Expected nil circuit breaker to be closed")
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Defaults used by the retry policy
+const (
+	// DefaultRetryBackoff is the time to wait before the first
+	// retry
+	DefaultRetryBackoff = 500 * time.Millisecond
+
+	// DefaultRetryMaxBackoff is the maximum time to wait between
+	// retries
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// DefaultRetryableStatusCodes are the response status codes that
+// are retried if the retry policy does not specify any
+var DefaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// StatusError is returned when the webhook responds with a status
+// other than OK
+type StatusError struct {
+	// response status code
+	StatusCode int
+
+	// response body
+	Body []byte
+}
+
+// Error implements error interface
+func (e *StatusError) Error() string {
+	return fmt.Sprintf(
+		"Response status is not OK: Got %d: Response %q",
+		e.StatusCode,
+		e.Body,
+	)
+}
+
+// RetryPolicy decides if & how a failed invocation is retried
+type RetryPolicy struct {
+	// maximum number of invocations including the first one
+	MaxAttempts int
+
+	// time to wait before the first retry; this doubles with
+	// every subsequent retry
+	Backoff time.Duration
+
+	// maximum time to wait between retries
+	MaxBackoff time.Duration
+
+	// response status codes that are retried
+	RetryableStatusCodes []int
+}
+
+// ShouldRetry returns true if the invocation that failed with the
+// provided error at the given attempt needs to be retried
+//
+// NOTE:
+//	This is safe to be invoked on a nil policy
+func (p *RetryPolicy) ShouldRetry(attempt int, err error) bool {
+	if p == nil || err == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+	switch cause := errors.Cause(err).(type) {
+	case *StatusError:
+		codes := p.RetryableStatusCodes
+		if len(codes) == 0 {
+			codes = DefaultRetryableStatusCodes
+		}
+		for _, code := range codes {
+			if cause.StatusCode == code {
+				return true
+			}
+		}
+		return false
+	case *url.Error, net.Error:
+		// invocation failed without any response
+		return true
+	default:
+		return false
+	}
+}
+
+// BackoffFor returns the time to wait before retrying the given
+// failed attempt
+func (p *RetryPolicy) BackoffFor(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	maxBackoff := p.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultRetryMaxBackoff
+	}
+	for i := 1; i < attempt; i++ {
+		backoff *= 2
+		if backoff >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	if backoff > maxBackoff {
+		return maxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Defaults used to tune the shared transport
+const (
+	// DefaultMaxIdleConns is the maximum number of idle
+	// connections across all the webhook servers
+	DefaultMaxIdleConns = 100
+
+	// DefaultMaxIdleConnsPerHost is the maximum number of idle
+	// connections per webhook server
+	DefaultMaxIdleConnsPerHost = 20
+
+	// DefaultIdleConnTimeout is the time after which an idle
+	// connection is closed
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// TransportConfig tunes the transport shared by the webhook
+// invocations
+type TransportConfig struct {
+	// maximum number of idle connections across all the
+	// webhook servers
+	MaxIdleConns int
+
+	// maximum number of idle connections per webhook server
+	MaxIdleConnsPerHost int
+
+	// time after which an idle connection is closed
+	IdleConnTimeout time.Duration
+
+	// when true HTTP/2 is not attempted for TLS connections
+	DisableHTTP2 bool
+}
+
+// DefaultTransportConfig returns the default transport config
+func DefaultTransportConfig() TransportConfig {
+	return TransportConfig{
+		MaxIdleConns:        DefaultMaxIdleConns,
+		MaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+		IdleConnTimeout:     DefaultIdleConnTimeout,
+	}
+}
+
+var (
+	sharedTransportMutex sync.Mutex
+
+	// config used to build the shared transport
+	sharedTransportConfig = DefaultTransportConfig()
+
+	// transport shared by all the webhooks that do not need
+	// any TLS settings; this is built lazily
+	sharedTransport *http.Transport
+)
+
+// SetTransportConfig sets the config used to build the shared
+// transport
+//
+// NOTE:
+//	This should be invoked before invoking any webhook since
+// the existing transports are not rebuilt
+func SetTransportConfig(config TransportConfig) {
+	sharedTransportMutex.Lock()
+	defer sharedTransportMutex.Unlock()
+
+	sharedTransportConfig = config
+	sharedTransport = nil
+}
+
+// getSharedTransport returns the transport shared by all the
+// webhooks that do not need any TLS settings
+func getSharedTransport() *http.Transport {
+	sharedTransportMutex.Lock()
+	defer sharedTransportMutex.Unlock()
+
+	if sharedTransport == nil {
+		sharedTransport = newTransport(sharedTransportConfig)
+	}
+	return sharedTransport
+}
+
+// newTransportFromSharedConfig returns a new transport that is
+// tuned as per the shared config. This is meant for webhooks
+// that need their own TLS settings.
+func newTransportFromSharedConfig() *http.Transport {
+	sharedTransportMutex.Lock()
+	defer sharedTransportMutex.Unlock()
+
+	return newTransport(sharedTransportConfig)
+}
+
+// newTransport returns a new transport tuned as per the
+// provided config
+//
+// NOTE:
+//	Default transport is cloned to retain its proxy & dial
+// settings
+func newTransport(config TransportConfig) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.MaxIdleConns > 0 {
+		transport.MaxIdleConns = config.MaxIdleConns
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	transport.ForceAttemptHTTP2 = !config.DisableHTTP2
+	if config.DisableHTTP2 {
+		// a non nil map disables HTTP/2
+		transport.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return transport
+}
@@ -19,15 +19,21 @@ package webhook
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	gojson "encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/util/json"
+
+	"openebs.io/metac/metrics"
 )
 
 // Invoker manages invocation of webhook
@@ -37,6 +43,58 @@ type Invoker struct {
 
 	// webhook invocation timeout
 	Timeout time.Duration
+
+	// PEM encoded CA bundle to verify the webhook server's
+	// certificate
+	CABundle []byte
+
+	// host name used to verify the webhook server's certificate
+	ServerName string
+
+	// PEM encoded client certificate & key used to authenticate
+	// with the webhook server
+	ClientCert []byte
+	ClientKey  []byte
+
+	// bearer token used to authenticate with the webhook server
+	BearerToken string
+
+	// retries the failed invocations if set
+	RetryPolicy *RetryPolicy
+
+	// short-circuits the invocations if set
+	CircuitBreaker *CircuitBreaker
+
+	// CredentialsFn returns the latest client credentials before
+	// every invocation. This overrides ClientCert, ClientKey &
+	// BearerToken if set.
+	CredentialsFn func() (*Credentials, error)
+
+	// guards the lazily built http client & the current client
+	// certificate
+	mutex sync.Mutex
+
+	// http client reused across invocations
+	client *http.Client
+
+	// transport of this webhook if it does not use the shared one
+	transport *http.Transport
+
+	// current client certificate along with its PEM encoded
+	// certificate & key
+	clientCert    *tls.Certificate
+	clientCertPEM []byte
+	clientKeyPEM  []byte
+}
+
+// Credentials are used to authenticate with the webhook server
+type Credentials struct {
+	// PEM encoded client certificate & key
+	ClientCert []byte
+	ClientKey  []byte
+
+	// bearer token
+	BearerToken string
 }
 
 // InvokerOption is a typed function that is used
@@ -71,6 +129,85 @@ func (i *Invoker) String() string {
 // Invoke this webhook by passing the given request
 // and fill up the given response with the webhook response
 func (i *Invoker) Invoke(request, response interface{}) error {
+	return i.InvokeContext(context.Background(), request, response)
+}
+
+// InvokeContext invokes this webhook by passing the given request
+// and fills up the given response with the webhook response. The
+// invocation is cancelled once the given context is done.
+func (i *Invoker) InvokeContext(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	err := i.CircuitBreaker.Allow()
+	if err != nil {
+		return err
+	}
+	err = i.invokeWithRetries(ctx, request, response)
+	i.CircuitBreaker.RecordResult(err)
+	return err
+}
+
+// invokeWithRetries invokes this webhook & retries the failed
+// invocations as per the retry policy
+//
+// NOTE:
+//	All the attempts along with the backoffs between them are bounded
+// by this webhook's timeout. A failed attempt is not retried if its
+// backoff ends after this timeout. This bounds the time for which the
+// caller's worker is blocked by the retries. The given context
+// may end these earlier.
+func (i *Invoker) invokeWithRetries(
+	ctx context.Context,
+	request, response interface{},
+) error {
+	if i.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, i.Timeout)
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := i.invoke(ctx, request, response)
+		metrics.RecordHook(i.URL, start, err)
+		if ctx.Err() != nil || !i.RetryPolicy.ShouldRetry(attempt, err) {
+			return err
+		}
+		backoff := i.RetryPolicy.BackoffFor(attempt)
+		if !deadline.IsZero() && time.Now().Add(backoff).After(deadline) {
+			glog.V(4).Infof(
+				"%s: Won't retry: Backoff %s exceeds timeout: Attempt %d failed: %v",
+				i,
+				backoff,
+				attempt,
+				err,
+			)
+			return err
+		}
+		glog.V(4).Infof(
+			"%s: Will retry after %s: Attempt %d failed: %v",
+			i,
+			backoff,
+			attempt,
+			err,
+		)
+		metrics.RecordHookRetry(i.URL)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// invoke this webhook by passing the given request and
+// fill up the given response with the webhook response. The
+// invocation is cancelled once the given context is done.
+func (i *Invoker) invoke(
+	ctx context.Context,
+	request, response interface{},
+) error {
 	// Encode request.
 	reqBody, err := json.Marshal(request)
 	if err != nil {
@@ -90,12 +227,36 @@ func (i *Invoker) Invoke(request, response interface{}) error {
 	}
 
 	// Send request.
-	client := &http.Client{Timeout: i.Timeout}
-	resp, err := client.Post(
+	creds, err := i.getCredentials()
+	if err != nil {
+		return err
+	}
+	err = i.setClientCertificate(creds)
+	if err != nil {
+		return err
+	}
+	client, err := i.getHTTPClient()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
 		i.URL,
-		"application/json",
 		bytes.NewReader(reqBody),
 	)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"%s: Failed to build request",
+			i,
+		)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if creds.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+creds.BearerToken)
+	}
+	resp, err := client.Do(req)
 	if err != nil {
 		return errors.Wrapf(
 			err,
@@ -118,11 +279,13 @@ func (i *Invoker) Invoke(request, response interface{}) error {
 
 	// Check status code.
 	if resp.StatusCode != http.StatusOK {
-		return errors.Errorf(
-			"%s: Response status is not OK: Got %d: Response %q",
+		return errors.Wrapf(
+			&StatusError{
+				StatusCode: resp.StatusCode,
+				Body:       respBody,
+			},
+			"%s",
 			i,
-			resp.StatusCode,
-			respBody,
 		)
 	}
 
@@ -134,3 +297,150 @@ func (i *Invoker) Invoke(request, response interface{}) error {
 	glog.V(8).Infof("%s: Invoked successfully", i)
 	return nil
 }
+
+// getCredentials returns the credentials used to authenticate
+// with the webhook server
+func (i *Invoker) getCredentials() (*Credentials, error) {
+	if i.CredentialsFn == nil {
+		return &Credentials{
+			ClientCert:  i.ClientCert,
+			ClientKey:   i.ClientKey,
+			BearerToken: i.BearerToken,
+		}, nil
+	}
+	creds, err := i.CredentialsFn()
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s: Can't get credentials", i)
+	}
+	if creds == nil {
+		return &Credentials{}, nil
+	}
+	return creds, nil
+}
+
+// setClientCertificate sets the client certificate presented
+// during TLS handshakes with the webhook server
+//
+// NOTE:
+//	Certificate is parsed only when it differs from the current
+// one. Connections that are already established continue to use
+// the certificate they were established with.
+func (i *Invoker) setClientCertificate(creds *Credentials) error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if len(creds.ClientCert) == 0 && len(creds.ClientKey) == 0 {
+		i.clientCert = nil
+		i.clientCertPEM = nil
+		i.clientKeyPEM = nil
+		return nil
+	}
+	if i.clientCert != nil &&
+		bytes.Equal(i.clientCertPEM, creds.ClientCert) &&
+		bytes.Equal(i.clientKeyPEM, creds.ClientKey) {
+		return nil
+	}
+	cert, err := tls.X509KeyPair(creds.ClientCert, creds.ClientKey)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"%s: Invalid client certificate & key",
+			i,
+		)
+	}
+	i.clientCert = &cert
+	i.clientCertPEM = creds.ClientCert
+	i.clientKeyPEM = creds.ClientKey
+	return nil
+}
+
+// getClientCertificate returns the current client certificate
+// during a TLS handshake with the webhook server
+func (i *Invoker) getClientCertificate(
+	*tls.CertificateRequestInfo,
+) (*tls.Certificate, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.clientCert == nil {
+		// no certificate is sent
+		return &tls.Certificate{}, nil
+	}
+	return i.clientCert, nil
+}
+
+// isTLSConfigured returns true if any of the TLS settings are
+// provided
+func (i *Invoker) isTLSConfigured() bool {
+	return len(i.CABundle) != 0 ||
+		i.ServerName != "" ||
+		len(i.ClientCert) != 0 ||
+		len(i.ClientKey) != 0 ||
+		i.CredentialsFn != nil
+}
+
+// newTLSConfig returns the TLS config built from the TLS
+// settings of this invoker
+func (i *Invoker) newTLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:           i.ServerName,
+		GetClientCertificate: i.getClientCertificate,
+	}
+	if len(i.CABundle) != 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(i.CABundle) {
+			return nil, errors.Errorf(
+				"%s: Invalid CA bundle: No PEM encoded certificates found",
+				i,
+			)
+		}
+		config.RootCAs = pool
+	}
+	return config, nil
+}
+
+// getHTTPClient returns the http client used to invoke this
+// webhook. Client is built once & is reused across invocations
+// to reuse the connections to the webhook server.
+func (i *Invoker) getHTTPClient() (*http.Client, error) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.client != nil {
+		return i.client, nil
+	}
+	if !i.isTLSConfigured() {
+		i.client = &http.Client{
+			Timeout:   i.Timeout,
+			Transport: getSharedTransport(),
+		}
+		return i.client, nil
+	}
+	tlsConfig, err := i.newTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport := newTransportFromSharedConfig()
+	transport.TLSClientConfig = tlsConfig
+	i.transport = transport
+	i.client = &http.Client{
+		Timeout:   i.Timeout,
+		Transport: transport,
+	}
+	return i.client, nil
+}
+
+// Close closes the idle connections of this webhook's own
+// transport
+//
+// NOTE:
+//	The shared transport is left as is since other webhooks use it
+func (i *Invoker) Close() error {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.transport != nil {
+		i.transport.CloseIdleConnections()
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newBenchClientCert returns a PEM encoded self signed client
+// certificate & its key
+func newBenchClientCert(b *testing.B) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		b.Fatalf("Can't generate key: %+v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "metac"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		b.Fatalf("Can't create certificate: %+v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		b.Fatalf("Can't marshal key: %+v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM
+}
+
+// BenchmarkInvoke compares an invoker that is reused across
+// invocations against a new invoker per invocation. The latter
+// was the behaviour before invokers were cached & results in a
+// new connection i.e. a new TLS handshake per invocation.
+//
+// Invokers with credentials get their client certificate & bearer
+// token before every invocation similar to the webhooks that refer
+// to a Secret.
+//
+// Run this via:
+//	go test ./hooks/webhook/ -run xxx -bench BenchmarkInvoke
+func BenchmarkInvoke(b *testing.B) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok"}`))
+	})
+	plainServer := httptest.NewServer(handler)
+	defer plainServer.Close()
+	tlsServer := httptest.NewTLSServer(handler)
+	defer tlsServer.Close()
+
+	credsServer := httptest.NewUnstartedServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.TLS.PeerCertificates) == 0 ||
+				r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			handler(w, r)
+		}),
+	)
+	credsServer.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	credsServer.StartTLS()
+	defer credsServer.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: tlsServer.Certificate().Raw,
+	})
+	credsCABundle := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: credsServer.Certificate().Raw,
+	})
+	clientCert, clientKey := newBenchClientCert(b)
+	newPlainInvoker := func() *Invoker {
+		return &Invoker{
+			URL:     plainServer.URL,
+			Timeout: 5 * time.Second,
+		}
+	}
+	newTLSInvoker := func() *Invoker {
+		return &Invoker{
+			URL:      tlsServer.URL,
+			Timeout:  5 * time.Second,
+			CABundle: caBundle,
+		}
+	}
+	newCredsInvoker := func() *Invoker {
+		return &Invoker{
+			URL:      credsServer.URL,
+			Timeout:  5 * time.Second,
+			CABundle: credsCABundle,
+			CredentialsFn: func() (*Credentials, error) {
+				return &Credentials{
+					ClientCert:  clientCert,
+					ClientKey:   clientKey,
+					BearerToken: "secret",
+				}, nil
+			},
+		}
+	}
+	invoke := func(b *testing.B, invoker *Invoker) {
+		var resp map[string]string
+		if err := invoker.Invoke(map[string]string{}, &resp); err != nil {
+			b.Fatalf("Invoke failed: %+v", err)
+		}
+	}
+
+	var benchmarks = map[string]struct {
+		newInvoker func() *Invoker
+		isReused   bool
+	}{
+		"http reused invoker": {
+			newInvoker: newPlainInvoker,
+			isReused:   true,
+		},
+		"http new invoker per call": {
+			newInvoker: newPlainInvoker,
+		},
+		"https reused invoker": {
+			newInvoker: newTLSInvoker,
+			isReused:   true,
+		},
+		"https new invoker per call": {
+			newInvoker: newTLSInvoker,
+		},
+		"https with credentials reused invoker": {
+			newInvoker: newCredsInvoker,
+			isReused:   true,
+		},
+		"https with credentials new invoker per call": {
+			newInvoker: newCredsInvoker,
+		},
+	}
+	for name, bm := range benchmarks {
+		bm := bm
+		b.Run(name, func(b *testing.B) {
+			reused := bm.newInvoker()
+			b.ResetTimer()
+			for n := 0; n < b.N; n++ {
+				if bm.isReused {
+					invoke(b, reused)
+					continue
+				}
+				invoker := bm.newInvoker()
+				invoke(b, invoker)
+				if !invoker.isTLSConfigured() {
+					continue
+				}
+				// release the connection of the discarded
+				// transport
+				invoker.client.CloseIdleConnections()
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestInvokeWithTLS(t *testing.T) {
+	server := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"status":"ok"}`))
+		}),
+	)
+	defer server.Close()
+
+	caBundle := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: server.Certificate().Raw,
+	})
+
+	var tests = map[string]struct {
+		invoker *Invoker
+		isErr   bool
+	}{
+		"no CA bundle": {
+			invoker: &Invoker{
+				BearerToken: "secret",
+			},
+			isErr: true,
+		},
+		"invalid CA bundle": {
+			invoker: &Invoker{
+				CABundle:    []byte("junk"),
+				BearerToken: "secret",
+			},
+			isErr: true,
+		},
+		"CA bundle without token": {
+			invoker: &Invoker{
+				CABundle: caBundle,
+			},
+			isErr: true,
+		},
+		"CA bundle with token": {
+			invoker: &Invoker{
+				CABundle:    caBundle,
+				BearerToken: "secret",
+			},
+		},
+		"CA bundle with token & server name": {
+			invoker: &Invoker{
+				CABundle:    caBundle,
+				ServerName:  "example.com",
+				BearerToken: "secret",
+			},
+		},
+		"CA bundle with token & wrong server name": {
+			invoker: &Invoker{
+				CABundle:    caBundle,
+				ServerName:  "junk.com",
+				BearerToken: "secret",
+			},
+			isErr: true,
+		},
+		"invalid client certificate": {
+			invoker: &Invoker{
+				CABundle:    caBundle,
+				ClientCert:  []byte("junk"),
+				ClientKey:   []byte("junk"),
+				BearerToken: "secret",
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			mock.invoker.URL = server.URL
+			mock.invoker.Timeout = 5 * time.Second
+
+			var resp map[string]string
+			err := mock.invoker.Invoke(map[string]string{}, &resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if !mock.isErr && resp["status"] != "ok" {
+				t.Fatalf("Expected status ok got %v", resp)
+			}
+		})
+	}
+}
+
+func TestInvokeWithRetries(t *testing.T) {
+	var tests = map[string]struct {
+		failures      int32
+		failureStatus int
+		policy        *RetryPolicy
+		expectCalls   int32
+		isErr         bool
+	}{
+		"no retry policy": {
+			failures:      1,
+			failureStatus: http.StatusServiceUnavailable,
+			expectCalls:   1,
+			isErr:         true,
+		},
+		"retry till success": {
+			failures:      2,
+			failureStatus: http.StatusServiceUnavailable,
+			policy: &RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+			},
+			expectCalls: 3,
+		},
+		"retry till max attempts": {
+			failures:      5,
+			failureStatus: http.StatusServiceUnavailable,
+			policy: &RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+			},
+			expectCalls: 3,
+			isErr:       true,
+		},
+		"non retryable status": {
+			failures:      1,
+			failureStatus: http.StatusBadRequest,
+			policy: &RetryPolicy{
+				MaxAttempts: 3,
+				Backoff:     time.Millisecond,
+			},
+			expectCalls: 1,
+			isErr:       true,
+		},
+		"custom retryable status": {
+			failures:      1,
+			failureStatus: http.StatusBadRequest,
+			policy: &RetryPolicy{
+				MaxAttempts:          3,
+				Backoff:              time.Millisecond,
+				RetryableStatusCodes: []int{http.StatusBadRequest},
+			},
+			expectCalls: 2,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					if atomic.AddInt32(&calls, 1) <= mock.failures {
+						w.WriteHeader(mock.failureStatus)
+						return
+					}
+					w.Write([]byte(`{}`))
+				}),
+			)
+			defer server.Close()
+
+			invoker := &Invoker{
+				URL:         server.URL,
+				Timeout:     5 * time.Second,
+				RetryPolicy: mock.policy,
+			}
+			var resp map[string]string
+			err := invoker.Invoke(map[string]string{}, &resp)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if calls != mock.expectCalls {
+				t.Fatalf("Expected calls %d got %d", mock.expectCalls, calls)
+			}
+		})
+	}
+}
+
+func TestInvokeWithRetriesWithinTimeout(t *testing.T) {
+	var tests = map[string]struct {
+		delay       time.Duration
+		backoff     time.Duration
+		expectCalls int32
+	}{
+		"backoff exceeds timeout": {
+			backoff:     time.Second,
+			expectCalls: 1,
+		},
+		"retries till timeout": {
+			backoff:     100 * time.Millisecond,
+			expectCalls: 3,
+		},
+		"slow webhook is cancelled at timeout": {
+			delay:       500 * time.Millisecond,
+			backoff:     time.Millisecond,
+			expectCalls: 1,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var calls int32
+			server := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					atomic.AddInt32(&calls, 1)
+					select {
+					case <-time.After(mock.delay):
+					case <-r.Context().Done():
+					}
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}),
+			)
+			defer server.Close()
+
+			invoker := &Invoker{
+				URL:     server.URL,
+				Timeout: 250 * time.Millisecond,
+				RetryPolicy: &RetryPolicy{
+					MaxAttempts: 10,
+					Backoff:     mock.backoff,
+					MaxBackoff:  mock.backoff,
+				},
+			}
+			start := time.Now()
+			var resp map[string]string
+			err := invoker.Invoke(map[string]string{}, &resp)
+			if err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Fatalf("Expected invocation to end within timeout got %s", elapsed)
+			}
+			if got := atomic.LoadInt32(&calls); got != mock.expectCalls {
+				t.Fatalf("Expected calls %d got %d", mock.expectCalls, got)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyBackoffFor(t *testing.T) {
+	policy := &RetryPolicy{
+		Backoff:    time.Second,
+		MaxBackoff: 5 * time.Second,
+	}
+	var tests = map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		3:  4 * time.Second,
+		4:  5 * time.Second,
+		10: 5 * time.Second,
+	}
+	for attempt, expect := range tests {
+		if got := policy.BackoffFor(attempt); got != expect {
+			t.Fatalf("Expected backoff %s for attempt %d got %s", expect, attempt, got)
+		}
+	}
+}
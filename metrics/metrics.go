@@ -0,0 +1,429 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// ResultSuccess is the result tag value of a successful
+	// operation
+	ResultSuccess = "success"
+
+	// ResultError is the result tag value of a failed operation
+	ResultError = "error"
+)
+
+const (
+	// OperationCreate is the operation tag value of a create
+	OperationCreate = "create"
+
+	// OperationUpdate is the operation tag value of an update
+	OperationUpdate = "update"
+
+	// OperationDelete is the operation tag value of a delete
+	OperationDelete = "delete"
+)
+
+// Tag keys used to label the metrics
+var (
+	// KeyController is the tag key with controller name as value
+	KeyController = mustNewKey("controller")
+
+	// KeyResult is the tag key with operation result as value
+	KeyResult = mustNewKey("result")
+
+	// KeyHook is the tag key with hook url or inline function
+	// name as value
+	KeyHook = mustNewKey("hook")
+
+	// KeyHookType is the tag key with the kind of hook as value
+	// e.g. sync, finalize, preUpdateChild or postUpdateChild
+	KeyHookType = mustNewKey("hook_type")
+
+	// KeyQueue is the tag key with workqueue name as value
+	KeyQueue = mustNewKey("queue")
+
+	// KeyOperation is the tag key with resource operation as
+	// value e.g. create, update or delete
+	KeyOperation = mustNewKey("operation")
+
+	// KeyKind is the tag key with resource kind as value
+	KeyKind = mustNewKey("kind")
+)
+
+// Measures recorded by metac
+var (
+	// SyncDuration measures the time taken to reconcile a
+	// single key by a controller
+	SyncDuration = stats.Float64(
+		"metac/sync_duration",
+		"Time taken to reconcile a key",
+		stats.UnitMilliseconds,
+	)
+
+	// HookLatency measures the time taken to invoke a hook
+	HookLatency = stats.Float64(
+		"metac/hook_latency",
+		"Time taken to invoke a hook",
+		stats.UnitMilliseconds,
+	)
+
+	// ResourceOperations counts the create, update & delete
+	// operations executed against the kubernetes cluster
+	ResourceOperations = stats.Int64(
+		"metac/resource_operations",
+		"Number of create, update & delete operations against resources",
+		stats.UnitDimensionless,
+	)
+
+	// DryRunOperations counts the create, update & delete
+	// operations that were computed but not executed due to
+	// dry run
+	DryRunOperations = stats.Int64(
+		"metac/dry_run_operations",
+		"Number of create, update & delete operations skipped due to dry run",
+		stats.UnitDimensionless,
+	)
+
+	// HookCallLatency measures the time taken by a controller to
+	// invoke its hook including the hook middlewares
+	HookCallLatency = stats.Float64(
+		"metac/hook_call_latency",
+		"Time taken by a controller to invoke its hook",
+		stats.UnitMilliseconds,
+	)
+
+	// HookRetries counts the retries of failed hook invocations
+	HookRetries = stats.Int64(
+		"metac/hook_retries",
+		"Number of retries of failed hook invocations",
+		stats.UnitDimensionless,
+	)
+
+	// HookCircuitOpen is 1 if the circuit of a hook is open
+	// i.e. its invocations are short-circuited, 0 otherwise
+	HookCircuitOpen = stats.Int64(
+		"metac/hook_circuit_open",
+		"Whether the invocations of a hook are short-circuited",
+		stats.UnitDimensionless,
+	)
+
+	// WorkqueueDepth measures the current depth of a workqueue
+	WorkqueueDepth = stats.Int64(
+		"metac/workqueue_depth",
+		"Current depth of workqueue",
+		stats.UnitDimensionless,
+	)
+
+	// WorkqueueAdds counts the items added to a workqueue
+	WorkqueueAdds = stats.Int64(
+		"metac/workqueue_adds",
+		"Number of items added to workqueue",
+		stats.UnitDimensionless,
+	)
+
+	// WorkqueueRetries counts the items re-added to a workqueue
+	// due to failures
+	WorkqueueRetries = stats.Int64(
+		"metac/workqueue_retries",
+		"Number of retries handled by workqueue",
+		stats.UnitDimensionless,
+	)
+
+	// WorkqueueLatency measures the time an item stays in a
+	// workqueue before being processed
+	WorkqueueLatency = stats.Float64(
+		"metac/workqueue_latency",
+		"Time an item stays in workqueue before being processed",
+		stats.UnitMilliseconds,
+	)
+
+	// WorkqueueWorkDuration measures the time taken to process
+	// an item from a workqueue
+	WorkqueueWorkDuration = stats.Float64(
+		"metac/workqueue_work_duration",
+		"Time taken to process an item from workqueue",
+		stats.UnitMilliseconds,
+	)
+)
+
+// latencyDistribution is the histogram buckets in milliseconds
+var latencyDistribution = view.Distribution(
+	1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000,
+)
+
+// Views exported by metac
+var Views = []*view.View{
+	{
+		Name:        "metac/sync_duration",
+		Description: "Time taken to reconcile a key",
+		Measure:     SyncDuration,
+		TagKeys:     []tag.Key{KeyController, KeyResult},
+		Aggregation: latencyDistribution,
+	},
+	{
+		Name:        "metac/sync_total",
+		Description: "Number of keys reconciled",
+		Measure:     SyncDuration,
+		TagKeys:     []tag.Key{KeyController, KeyResult},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "metac/hook_latency",
+		Description: "Time taken to invoke a hook",
+		Measure:     HookLatency,
+		TagKeys:     []tag.Key{KeyHook, KeyResult},
+		Aggregation: latencyDistribution,
+	},
+	{
+		Name:        "metac/hook_total",
+		Description: "Number of hook invocations",
+		Measure:     HookLatency,
+		TagKeys:     []tag.Key{KeyHook, KeyResult},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "metac/hook_call_latency",
+		Description: "Time taken by a controller to invoke its hook",
+		Measure:     HookCallLatency,
+		TagKeys:     []tag.Key{KeyController, KeyHookType, KeyResult},
+		Aggregation: latencyDistribution,
+	},
+	{
+		Name:        "metac/hook_call_total",
+		Description: "Number of hook invocations by a controller",
+		Measure:     HookCallLatency,
+		TagKeys:     []tag.Key{KeyController, KeyHookType, KeyResult},
+		Aggregation: view.Count(),
+	},
+	{
+		Name:        "metac/hook_retries_total",
+		Description: "Number of retries of failed hook invocations",
+		Measure:     HookRetries,
+		TagKeys:     []tag.Key{KeyHook},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        "metac/hook_circuit_open",
+		Description: "Whether the invocations of a hook are short-circuited",
+		Measure:     HookCircuitOpen,
+		TagKeys:     []tag.Key{KeyHook},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        "metac/resource_operations_total",
+		Description: "Number of create, update & delete operations against resources",
+		Measure:     ResourceOperations,
+		TagKeys:     []tag.Key{KeyOperation, KeyKind, KeyResult},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        "metac/dry_run_operations_total",
+		Description: "Number of create, update & delete operations skipped due to dry run",
+		Measure:     DryRunOperations,
+		TagKeys:     []tag.Key{KeyOperation, KeyKind},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        "metac/workqueue_depth",
+		Description: "Current depth of workqueue",
+		Measure:     WorkqueueDepth,
+		TagKeys:     []tag.Key{KeyQueue},
+		Aggregation: view.LastValue(),
+	},
+	{
+		Name:        "metac/workqueue_adds_total",
+		Description: "Number of items added to workqueue",
+		Measure:     WorkqueueAdds,
+		TagKeys:     []tag.Key{KeyQueue},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        "metac/workqueue_retries_total",
+		Description: "Number of retries handled by workqueue",
+		Measure:     WorkqueueRetries,
+		TagKeys:     []tag.Key{KeyQueue},
+		Aggregation: view.Sum(),
+	},
+	{
+		Name:        "metac/workqueue_latency",
+		Description: "Time an item stays in workqueue before being processed",
+		Measure:     WorkqueueLatency,
+		TagKeys:     []tag.Key{KeyQueue},
+		Aggregation: latencyDistribution,
+	},
+	{
+		Name:        "metac/workqueue_work_duration",
+		Description: "Time taken to process an item from workqueue",
+		Measure:     WorkqueueWorkDuration,
+		TagKeys:     []tag.Key{KeyQueue},
+		Aggregation: latencyDistribution,
+	},
+}
+
+// Register registers metac views with OpenCensus & sets the
+// workqueue metrics provider. Metrics are exported by the
+// registered exporter(s).
+//
+// NOTE:
+//	This should be invoked before starting metac controllers
+// since workqueue metrics provider can be set only once & is
+// applicable to workqueues created after setting it.
+func Register() error {
+	workqueue.SetProvider(workqueueMetricsProvider{})
+	err := view.Register(Views...)
+	if err != nil {
+		return errors.Wrapf(err, "Can't register metac views")
+	}
+	return nil
+}
+
+// mustNewKey returns a new tag key or panics
+func mustNewKey(name string) tag.Key {
+	key, err := tag.NewKey(name)
+	if err != nil {
+		panic(errors.Wrapf(err, "Can't create tag key %q", name))
+	}
+	return key
+}
+
+// record records the given measurements along with the
+// provided tags
+func record(mutators []tag.Mutator, ms ...stats.Measurement) {
+	ctx, err := tag.New(context.Background(), mutators...)
+	if err != nil {
+		glog.V(4).Infof("Can't record metrics: %v", err)
+		return
+	}
+	stats.Record(ctx, ms...)
+}
+
+// resultOf returns the result tag value based on the
+// provided error
+func resultOf(err error) string {
+	if err != nil {
+		return ResultError
+	}
+	return ResultSuccess
+}
+
+// sinceInMillis returns the milliseconds elapsed since the
+// provided time
+func sinceInMillis(start time.Time) float64 {
+	return float64(time.Since(start)) / float64(time.Millisecond)
+}
+
+// RecordSync records the time taken to reconcile a key by
+// the given controller along with the result of this sync
+func RecordSync(controller string, start time.Time, err error) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(KeyController, controller),
+			tag.Upsert(KeyResult, resultOf(err)),
+		},
+		SyncDuration.M(sinceInMillis(start)),
+	)
+}
+
+// RecordHook records the time taken to invoke the given hook
+// along with the result of this invocation. Hook is identified
+// by its url or inline function name.
+func RecordHook(hook string, start time.Time, err error) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(KeyHook, hook),
+			tag.Upsert(KeyResult, resultOf(err)),
+		},
+		HookLatency.M(sinceInMillis(start)),
+	)
+}
+
+// RecordHookCall records the time taken by the given controller
+// to invoke its hook of the given type along with the result of
+// this invocation
+func RecordHookCall(controller, hookType string, start time.Time, err error) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(KeyController, controller),
+			tag.Upsert(KeyHookType, hookType),
+			tag.Upsert(KeyResult, resultOf(err)),
+		},
+		HookCallLatency.M(sinceInMillis(start)),
+	)
+}
+
+// RecordHookRetry records a retry of a failed invocation of the
+// given hook
+func RecordHookRetry(hook string) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(KeyHook, hook),
+		},
+		HookRetries.M(1),
+	)
+}
+
+// RecordHookCircuitOpen records whether the invocations of the
+// given hook are short-circuited
+func RecordHookCircuitOpen(hook string, isOpen bool) {
+	var value int64
+	if isOpen {
+		value = 1
+	}
+	record(
+		[]tag.Mutator{
+			tag.Upsert(KeyHook, hook),
+		},
+		HookCircuitOpen.M(value),
+	)
+}
+
+// RecordResourceOperation records a create, update or delete
+// operation against a resource of the given kind
+func RecordResourceOperation(operation, kind string, err error) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(KeyOperation, operation),
+			tag.Upsert(KeyKind, kind),
+			tag.Upsert(KeyResult, resultOf(err)),
+		},
+		ResourceOperations.M(1),
+	)
+}
+
+// RecordDryRunOperation records a create, update or delete
+// operation against a resource of the given kind that was not
+// executed due to dry run
+func RecordDryRunOperation(operation, kind string) {
+	record(
+		[]tag.Mutator{
+			tag.Upsert(KeyOperation, operation),
+			tag.Upsert(KeyKind, kind),
+		},
+		DryRunOperations.M(1),
+	)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/stats/view"
+	"go.opencensus.io/tag"
+)
+
+// countOf returns the count aggregated by the given view
+// for the row that has all the provided tags
+func countOf(t *testing.T, viewName string, tags ...tag.Tag) int64 {
+	rows, err := view.RetrieveData(viewName)
+	if err != nil {
+		t.Fatalf("Can't retrieve data for view %q: %v", viewName, err)
+	}
+	for _, row := range rows {
+		found := 0
+		for _, want := range tags {
+			for _, got := range row.Tags {
+				if got == want {
+					found++
+				}
+			}
+		}
+		if found != len(tags) {
+			continue
+		}
+		switch data := row.Data.(type) {
+		case *view.CountData:
+			return data.Value
+		case *view.SumData:
+			return int64(data.Value)
+		}
+	}
+	return 0
+}
+
+func TestRecord(t *testing.T) {
+	if err := view.Register(Views...); err != nil {
+		t.Fatalf("Can't register views: %v", err)
+	}
+	defer view.Unregister(Views...)
+
+	RecordSync("test-ctl", time.Now(), nil)
+	RecordSync("test-ctl", time.Now(), errors.Errorf("sync failed"))
+	RecordSync("test-ctl", time.Now(), errors.Errorf("sync failed"))
+	RecordHook("test-hook", time.Now(), nil)
+	RecordHookRetry("test-hook")
+	RecordHookRetry("test-hook")
+	RecordHookCall("test-ctl", "sync", time.Now(), errors.Errorf("hook failed"))
+	RecordResourceOperation(OperationCreate, "Pod", nil)
+	RecordResourceOperation(OperationCreate, "Pod", nil)
+	RecordDryRunOperation(OperationDelete, "Pod")
+
+	var tests = map[string]struct {
+		view  string
+		tags  []tag.Tag
+		count int64
+	}{
+		"sync success": {
+			view: "metac/sync_total",
+			tags: []tag.Tag{
+				{Key: KeyController, Value: "test-ctl"},
+				{Key: KeyResult, Value: ResultSuccess},
+			},
+			count: 1,
+		},
+		"sync error": {
+			view: "metac/sync_total",
+			tags: []tag.Tag{
+				{Key: KeyController, Value: "test-ctl"},
+				{Key: KeyResult, Value: ResultError},
+			},
+			count: 2,
+		},
+		"hook success": {
+			view: "metac/hook_total",
+			tags: []tag.Tag{
+				{Key: KeyHook, Value: "test-hook"},
+				{Key: KeyResult, Value: ResultSuccess},
+			},
+			count: 1,
+		},
+		"hook error": {
+			view: "metac/hook_total",
+			tags: []tag.Tag{
+				{Key: KeyHook, Value: "test-hook"},
+				{Key: KeyResult, Value: ResultError},
+			},
+			count: 0,
+		},
+		"hook retries": {
+			view: "metac/hook_retries_total",
+			tags: []tag.Tag{
+				{Key: KeyHook, Value: "test-hook"},
+			},
+			count: 2,
+		},
+		"hook call error": {
+			view: "metac/hook_call_total",
+			tags: []tag.Tag{
+				{Key: KeyController, Value: "test-ctl"},
+				{Key: KeyHookType, Value: "sync"},
+				{Key: KeyResult, Value: ResultError},
+			},
+			count: 1,
+		},
+		"create pod": {
+			view: "metac/resource_operations_total",
+			tags: []tag.Tag{
+				{Key: KeyOperation, Value: OperationCreate},
+				{Key: KeyKind, Value: "Pod"},
+			},
+			count: 2,
+		},
+		"dry run delete pod": {
+			view: "metac/dry_run_operations_total",
+			tags: []tag.Tag{
+				{Key: KeyOperation, Value: OperationDelete},
+				{Key: KeyKind, Value: "Pod"},
+			},
+			count: 1,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := countOf(t, mock.view, mock.tags...)
+			if got != mock.count {
+				t.Fatalf("Want count %d got %d", mock.count, got)
+			}
+		})
+	}
+}
+
+func TestWorkqueueMetricsProvider(t *testing.T) {
+	if err := view.Register(Views...); err != nil {
+		t.Fatalf("Can't register views: %v", err)
+	}
+	defer view.Unregister(Views...)
+
+	provider := workqueueMetricsProvider{}
+	adds := provider.NewAddsMetric("test-queue")
+	retries := provider.NewRetriesMetric("test-queue")
+
+	adds.Inc()
+	adds.Inc()
+	adds.Inc()
+	retries.Inc()
+
+	queueTag := tag.Tag{Key: KeyQueue, Value: "test-queue"}
+	if got := countOf(t, "metac/workqueue_adds_total", queueTag); got != 3 {
+		t.Fatalf("Want adds 3 got %d", got)
+	}
+	if got := countOf(t, "metac/workqueue_retries_total", queueTag); got != 1 {
+		t.Fatalf("Want retries 1 got %d", got)
+	}
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync/atomic"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// workqueueMetricsProvider records workqueue metrics via
+// OpenCensus. This implements workqueue.MetricsProvider.
+type workqueueMetricsProvider struct{}
+
+// queueGauge records the current value of a gauge
+type queueGauge struct {
+	queue   string
+	measure *stats.Int64Measure
+	value   int64
+}
+
+// Inc increments the gauge by one
+func (g *queueGauge) Inc() {
+	g.record(atomic.AddInt64(&g.value, 1))
+}
+
+// Dec decrements the gauge by one
+func (g *queueGauge) Dec() {
+	g.record(atomic.AddInt64(&g.value, -1))
+}
+
+func (g *queueGauge) record(value int64) {
+	record(
+		[]tag.Mutator{tag.Upsert(KeyQueue, g.queue)},
+		g.measure.M(value),
+	)
+}
+
+// queueCounter records increments of a counter
+type queueCounter struct {
+	queue   string
+	measure *stats.Int64Measure
+}
+
+// Inc increments the counter by one
+func (c *queueCounter) Inc() {
+	record(
+		[]tag.Mutator{tag.Upsert(KeyQueue, c.queue)},
+		c.measure.M(1),
+	)
+}
+
+// queueHistogram records observations of durations
+type queueHistogram struct {
+	queue   string
+	measure *stats.Float64Measure
+}
+
+// Observe records the provided duration in seconds
+func (h *queueHistogram) Observe(seconds float64) {
+	record(
+		[]tag.Mutator{tag.Upsert(KeyQueue, h.queue)},
+		// seconds are converted to milliseconds
+		h.measure.M(seconds*1000),
+	)
+}
+
+// noopSettableGauge ignores the values that get set
+type noopSettableGauge struct{}
+
+// Set is a no-op
+func (noopSettableGauge) Set(float64) {}
+
+// NewDepthMetric returns the gauge to record queue depth
+func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return &queueGauge{queue: name, measure: WorkqueueDepth}
+}
+
+// NewAddsMetric returns the counter to record queue adds
+func (workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return &queueCounter{queue: name, measure: WorkqueueAdds}
+}
+
+// NewLatencyMetric returns the histogram to record the time
+// an item stays in queue
+func (workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return &queueHistogram{queue: name, measure: WorkqueueLatency}
+}
+
+// NewWorkDurationMetric returns the histogram to record the
+// time taken to process an item
+func (workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return &queueHistogram{queue: name, measure: WorkqueueWorkDuration}
+}
+
+// NewUnfinishedWorkSecondsMetric is not recorded by metac
+func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopSettableGauge{}
+}
+
+// NewLongestRunningProcessorSecondsMetric is not recorded by metac
+func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopSettableGauge{}
+}
+
+// NewRetriesMetric returns the counter to record queue retries
+func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return &queueCounter{queue: name, measure: WorkqueueRetries}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"openebs.io/metac/debug"
+)
+
+// DebugControllers returns the runtime state of the controllers
+// run by the meta controllers of this server
+func (s *Server) DebugControllers() []debug.ControllerInfo {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	infos := []debug.ControllerInfo{}
+	for _, c := range s.controllers {
+		if reporter, ok := c.(debug.Reporter); ok {
+			infos = append(infos, reporter.DebugInfo()...)
+		}
+	}
+	return infos
+}
+
+// DebugInformers returns the number of subscribers of every shared
+// informer anchored by its resource & api version
+func (s *Server) DebugInformers() map[string]int {
+	if s.informerFactory == nil {
+		return map[string]int{}
+	}
+	return s.informerFactory.SubscriberCounts()
+}
+
+// DebugShard returns the membership of this server in its shard
+// group
+func (s *Server) DebugShard() debug.ShardInfo {
+	return debug.ShardInfo{
+		Enabled:  s.isShardingEnabled(),
+		Identity: s.shard.Identity(),
+		Members:  s.shard.Members(),
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"openebs.io/metac/health"
+)
+
+// HealthzChecks returns the liveness checks of this server
+func (s *Server) HealthzChecks() []health.Check {
+	return []health.Check{
+		{
+			Name:  "ping",
+			Check: func() error { return nil },
+		},
+	}
+}
+
+// ReadyzChecks returns the readiness checks of this server. The
+// server is ready once api discovery has synced & every running
+// meta controller reports itself as ready.
+//
+// NOTE:
+//	A server that is not the leader does not run any controllers.
+// Such a server is considered ready since it is a standby.
+func (s *Server) ReadyzChecks() []health.Check {
+	checks := []health.Check{
+		health.NewCheck(
+			"discovery",
+			func() bool {
+				return s.apiDiscovery != nil && s.apiDiscovery.HasSynced()
+			},
+			"API discovery has not synced",
+		),
+	}
+	if s.LeaderElection != nil && s.LeaderElection.Enabled && !s.IsLeading() {
+		checks = append(
+			checks,
+			health.NewCheck(
+				"leaderelection",
+				func() bool { return true },
+				"",
+			),
+		)
+		return checks
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	for _, c := range s.controllers {
+		if reporter, ok := c.(health.ReadinessReporter); ok {
+			checks = append(checks, reporter.ReadinessChecks()...)
+		}
+	}
+	return checks
+}
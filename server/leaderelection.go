@@ -0,0 +1,343 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// DefaultLeaseDuration is the default duration that non-leader
+	// candidates will wait before attempting to acquire leadership
+	DefaultLeaseDuration = 15 * time.Second
+
+	// DefaultRenewDeadline is the default duration that the acting
+	// leader will retry refreshing leadership before giving up
+	DefaultRenewDeadline = 10 * time.Second
+
+	// DefaultRetryPeriod is the default duration the leader election
+	// clients should wait between tries of actions
+	DefaultRetryPeriod = 2 * time.Second
+
+	// DefaultLeaseLockNamespace is the default namespace of the
+	// Lease resource used as the leader election lock
+	DefaultLeaseLockNamespace = "metac"
+
+	// DefaultLeaseLockName is the default name of the Lease
+	// resource used as the leader election lock
+	DefaultLeaseLockName = "metac"
+)
+
+// LeaderElectionConfig has the tunables that let multiple
+// replicas of metac elect a single leader. Only the leader
+// runs metac's meta controllers.
+type LeaderElectionConfig struct {
+	// Enabled when set to true lets metac participate in a
+	// Lease based leader election before starting its meta
+	// controllers
+	Enabled bool
+
+	// LeaseDuration is the duration that non-leader candidates
+	// will wait to force acquire leadership
+	LeaseDuration time.Duration
+
+	// RenewDeadline is the duration that the acting leader will
+	// retry refreshing leadership before giving up
+	RenewDeadline time.Duration
+
+	// RetryPeriod is the duration the candidates should wait
+	// between tries of actions
+	RetryPeriod time.Duration
+
+	// LockNamespace is the namespace of the Lease resource
+	LockNamespace string
+
+	// LockName is the name of the Lease resource
+	LockName string
+
+	// Identity uniquely identifies this candidate. It defaults
+	// to hostname suffixed with a random string.
+	Identity string
+}
+
+// setDefaultsIfNotSet sets default values against the fields
+// that were not set
+func (c *LeaderElectionConfig) setDefaultsIfNotSet() error {
+	if c.LeaseDuration == 0 {
+		c.LeaseDuration = DefaultLeaseDuration
+	}
+	if c.RenewDeadline == 0 {
+		c.RenewDeadline = DefaultRenewDeadline
+	}
+	if c.RetryPeriod == 0 {
+		c.RetryPeriod = DefaultRetryPeriod
+	}
+	if c.LockNamespace == "" {
+		c.LockNamespace = DefaultLeaseLockNamespace
+	}
+	if c.LockName == "" {
+		c.LockName = DefaultLeaseLockName
+	}
+	if c.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrapf(err, "Can't build leader election identity")
+		}
+		// random suffix ensures uniqueness when more than one
+		// candidate runs on the same host
+		c.Identity = hostname + "_" + rand.String(8)
+	}
+	return nil
+}
+
+// leaderElectedRunner starts the provided controllers only
+// after this metac instance has been elected as the leader.
+// It stops these controllers once leadership is lost.
+type leaderElectedRunner struct {
+	// name of the metac server that is being run
+	name string
+
+	config      LeaderElectionConfig
+	controllers []controller
+	lock        resourcelock.Interface
+
+	// cancel stops the leader election loop
+	cancel context.CancelFunc
+
+	// closed when leader election loop has exited
+	doneCh chan struct{}
+
+	// mutex guards isLeading & isStopped
+	mutex     sync.Mutex
+	isLeading bool
+	isStopped bool
+}
+
+// newLeaderElectedRunner returns a new instance of
+// leaderElectedRunner
+func newLeaderElectedRunner(
+	s *Server,
+	name string,
+	controllers []controller,
+) (*leaderElectedRunner, error) {
+	config := *s.LeaderElection
+	err := config.setDefaultsIfNotSet()
+	if err != nil {
+		return nil, err
+	}
+	client, err := coordinationv1.NewForConfig(s.Config)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't create coordination client for leader election: %s",
+			name,
+		)
+	}
+	return &leaderElectedRunner{
+		name:        name,
+		config:      config,
+		controllers: controllers,
+		lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Namespace: config.LockNamespace,
+				Name:      config.LockName,
+			},
+			Client: client,
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: config.Identity,
+			},
+		},
+		doneCh: make(chan struct{}),
+	}, nil
+}
+
+// IsLeading returns true if this instance is the current leader
+func (r *leaderElectedRunner) IsLeading() bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.isLeading
+}
+
+// Start runs the leader election loop in the background.
+// Controllers are started when leadership is acquired.
+func (r *leaderElectedRunner) Start() error {
+	elector, err := leaderelection.NewLeaderElector(
+		leaderelection.LeaderElectionConfig{
+			Lock:            r.lock,
+			LeaseDuration:   r.config.LeaseDuration,
+			RenewDeadline:   r.config.RenewDeadline,
+			RetryPeriod:     r.config.RetryPeriod,
+			ReleaseOnCancel: true,
+			Name:            r.name,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: r.onStartedLeading,
+				OnStoppedLeading: r.onStoppedLeading,
+				OnNewLeader: func(identity string) {
+					glog.Infof(
+						"%s: Observed leader %q: Lease %s/%s",
+						r.name,
+						identity,
+						r.config.LockNamespace,
+						r.config.LockName,
+					)
+				},
+			},
+		},
+	)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Can't create leader elector: %s",
+			r.name,
+		)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+
+	glog.Infof(
+		"%s: Will try to acquire leadership as %q: Lease %s/%s",
+		r.name,
+		r.config.Identity,
+		r.config.LockNamespace,
+		r.config.LockName,
+	)
+	go func() {
+		defer close(r.doneCh)
+		elector.Run(ctx)
+	}()
+	return nil
+}
+
+// onStartedLeading starts all the controllers
+//
+// NOTE:
+//	Controllers are not started if this runner was stopped or its
+// leadership was lost while this callback was being scheduled.
+// Stopped controllers can't be started again.
+func (r *leaderElectedRunner) onStartedLeading(ctx context.Context) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.isStopped || ctx.Err() != nil {
+		glog.Infof(
+			"%s: Won't start controllers: Leading as %q is cancelled",
+			r.name,
+			r.config.Identity,
+		)
+		return
+	}
+	glog.Infof("%s: Started leading as %q", r.name, r.config.Identity)
+	for _, c := range r.controllers {
+		c.Start()
+	}
+	r.isLeading = true
+}
+
+// onStoppedLeading stops all the controllers if they were
+// started earlier.
+//
+// NOTE:
+//	Meta controllers can't be restarted once they are stopped.
+// Hence leadership that is lost due to reasons other than an
+// explicit stop results in exiting the process. This lets the
+// replica get restarted & participate in a fresh election.
+func (r *leaderElectedRunner) onStoppedLeading() {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.isLeading {
+		stopControllers(r.controllers)
+		r.isLeading = false
+	}
+	if r.isStopped {
+		glog.Infof("%s: Stopped leading as %q", r.name, r.config.Identity)
+		return
+	}
+	glog.Fatalf("%s: Lost leadership as %q", r.name, r.config.Identity)
+}
+
+// Stop stops the leader election loop. Leadership if held
+// is released after stopping the controllers.
+func (r *leaderElectedRunner) Stop() {
+	r.mutex.Lock()
+	r.isStopped = true
+	r.mutex.Unlock()
+
+	r.cancel()
+	<-r.doneCh
+}
+
+// runControllers starts the provided controllers. Controllers
+// are started only after acquiring leadership if leader election
+// is enabled.
+//
+// The returned function stops these controllers.
+func (s *Server) runControllers(
+	name string,
+	controllers []controller,
+) (stop func(), err error) {
+	s.mutex.Lock()
+	s.controllers = append(s.controllers, controllers...)
+	s.mutex.Unlock()
+
+	if s.LeaderElection == nil || !s.LeaderElection.Enabled {
+		for _, c := range controllers {
+			c.Start()
+		}
+		return func() {
+			stopControllers(controllers)
+		}, nil
+	}
+
+	runner, err := newLeaderElectedRunner(s, name, controllers)
+	if err != nil {
+		return nil, err
+	}
+	err = runner.Start()
+	if err != nil {
+		return nil, err
+	}
+	s.leaderElectedRunner = runner
+	return runner.Stop, nil
+}
+
+// stopControllers stops all the provided controllers in
+// parallel & waits till all of them are stopped
+func stopControllers(controllers []controller) {
+	var wg sync.WaitGroup
+	for _, mctl := range controllers {
+		wg.Add(1)
+		go func(mctl controller) {
+			defer wg.Done()
+			mctl.Stop()
+		}(mctl)
+	}
+	// wait till all meta controllers are stopped
+	wg.Wait()
+}
@@ -21,19 +21,27 @@ import (
 	"sync"
 	"time"
 
+	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/kubernetes"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 
 	"openebs.io/metac/apis/metacontroller/v1alpha1"
 	metaclientset "openebs.io/metac/client/generated/clientset/versioned"
 	metainformers "openebs.io/metac/client/generated/informers/externalversions"
+	"openebs.io/metac/controller/common"
 	"openebs.io/metac/controller/composite"
 	"openebs.io/metac/controller/decorator"
 	"openebs.io/metac/controller/generic"
 	dynamicclientset "openebs.io/metac/dynamic/clientset"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 	dynamicinformer "openebs.io/metac/dynamic/informer"
+	"openebs.io/metac/events"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/sharding"
 
 	_ "k8s.io/client-go/plugin/pkg/client/auth/oidc"
 )
@@ -71,8 +79,85 @@ type Server struct {
 	// objects from the API server
 	InformerRelist time.Duration
 
+	// Leader election settings. Meta controllers are started
+	// only after acquiring leadership if this is enabled.
+	LeaderElection *LeaderElectionConfig
+
+	// Namespaces restrict the informers of namespaced resources
+	// to these namespaces. Informers are cluster wide if this is
+	// empty.
+	//
+	// NOTE:
+	//	This lets metac run with namespaced Roles instead of a
+	// ClusterRole. However, cluster scoped resources e.g.
+	// CompositeController, DecoratorController, etc. still need
+	// to be readable cluster wide.
+	Namespaces []string
+
+	// Sharding settings. Watches of GenericControllers are split
+	// amongst the replicas if this is enabled.
+	//
+	// NOTE:
+	//	This is supported by ConfigServer only
+	Sharding *sharding.Config
+
+	// WebhookSecretNamespaces are the namespaces of the Secrets
+	// that can be referred to by the webhooks. Webhooks can't refer
+	// to any Secret if this is empty.
+	WebhookSecretNamespaces []string
+
+	// DryRun if true lets GenericControllers compute the changes
+	// to their watches & attachments without applying them
+	//
+	// NOTE:
+	//	CompositeControllers & DecoratorControllers are not
+	// affected by this
+	DryRun bool
+
+	// InlineHooks resolves the inline hooks of this server's meta
+	// controllers. Inline hooks that are not found here are looked
+	// up in the package level registries e.g. the ones populated via
+	// generic.AddToInlineRegistry.
+	InlineHooks *hooks.Registry
+
+	// HookMiddlewares wrap every hook invocation of this server's
+	// meta controllers irrespective of the type of hook. First
+	// middleware is the outermost one.
+	HookMiddlewares []hooks.Middleware
+
 	// api discovery instance used by metac's metacontrollers
 	apiDiscovery *dynamicdiscovery.APIResourceDiscovery
+
+	// informer factory shared by metac's metacontrollers
+	informerFactory *dynamicinformer.SharedInformerFactory
+
+	// runs meta controllers when leader election is enabled
+	leaderElectedRunner *leaderElectedRunner
+
+	// decides the watches reconciled by this server when
+	// sharding is enabled
+	shard *sharding.Shard
+
+	// meta controllers run by this server; these report the
+	// readiness of this server
+	controllers []controller
+
+	// guards controllers against concurrent readers e.g.
+	// readiness checks
+	mutex sync.RWMutex
+}
+
+// IsLeading returns true if this server is running its meta
+// controllers. This is always true once the server is started
+// if leader election is disabled.
+func (s *Server) IsLeading() bool {
+	if s.LeaderElection == nil || !s.LeaderElection.Enabled {
+		return true
+	}
+	if s.leaderElectedRunner == nil {
+		return false
+	}
+	return s.leaderElectedRunner.IsLeading()
 }
 
 // GetKubeDetails returns information about the connected
@@ -109,6 +194,68 @@ func (s *Server) GetKubeDetails() *KubeDetails {
 	}
 }
 
+// hookDispatcher returns the dispatcher that resolves the inline
+// hooks & applies the middlewares of this server
+func (s *Server) hookDispatcher() *common.HookDispatcher {
+	return &common.HookDispatcher{
+		InlineHooks: s.InlineHooks,
+		Middlewares: s.HookMiddlewares,
+	}
+}
+
+// newEventRecorder returns a new recorder that emits kubernetes
+// events along with the function to stop this recorder
+func (s *Server) newEventRecorder() (record.EventRecorder, func(), error) {
+	client, err := corev1.NewForConfig(s.Config)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "Can't create events client")
+	}
+	recorder, stop := events.NewRecorder(client)
+	return recorder, stop, nil
+}
+
+// setWebhookSecretsGetter sets the client used by webhooks to
+// watch the Secrets holding their client credentials
+func (s *Server) setWebhookSecretsGetter() error {
+	client, err := kubernetes.NewForConfig(s.Config)
+	if err != nil {
+		return errors.Wrapf(err, "Can't create secrets client")
+	}
+	common.SetWebhookSecretsClient(client)
+	common.SetWebhookSecretNamespaces(s.WebhookSecretNamespaces)
+	return nil
+}
+
+// setHookConfigMapsGetter sets the client used by hooks to watch
+// the ConfigMaps holding their programs
+func (s *Server) setHookConfigMapsGetter() error {
+	client, err := kubernetes.NewForConfig(s.Config)
+	if err != nil {
+		return errors.Wrapf(err, "Can't create configmaps client")
+	}
+	common.SetHookConfigMapsClient(client)
+	return nil
+}
+
+// runControllersWithCleanup runs the provided controllers & invokes
+// the provided cleanup function when these controllers are stopped
+// e.g. to stop the event recorder
+func (s *Server) runControllersWithCleanup(
+	name string,
+	controllers []controller,
+	cleanup func(),
+) (stop func(), err error) {
+	stopControllers, err := s.runControllers(name, controllers)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	return func() {
+		stopControllers()
+		cleanup()
+	}, nil
+}
+
 // CRDServer represents metac server based on metac's CRDs.
 // In other words, this is about running Kubernetes controllers
 // against various MetaControllers. MetaControllers
@@ -123,6 +270,17 @@ func (s *CRDServer) String() string {
 
 // Start metac server
 func (s *CRDServer) Start(workerCount int) (stop func(), err error) {
+	// NOTE:
+	//	Only the watches of GenericControllers are sharded.
+	// CompositeControllers & DecoratorControllers are reconciled
+	// by every replica.
+	if s.isShardingEnabled() {
+		glog.Warningf(
+			"CompositeControllers & DecoratorControllers are not sharded: %s",
+			s,
+		)
+	}
+
 	// refresh discovery cache to pick up newly-installed resources.
 	discoveryClient :=
 		discovery.NewDiscoveryClientForConfigOrDie(s.Config)
@@ -144,9 +302,21 @@ func (s *CRDServer) Start(workerCount int) (stop func(), err error) {
 	}
 
 	// informer factory for metacontroller objects.
-	metaInformerFactory := metainformers.NewSharedInformerFactory(
+	//
+	// NOTE:
+	//	Generated informers can be restricted to a single
+	// namespace only
+	var metaInformerOpts []metainformers.SharedInformerOption
+	if len(s.Namespaces) == 1 {
+		metaInformerOpts = append(
+			metaInformerOpts,
+			metainformers.WithNamespace(s.Namespaces[0]),
+		)
+	}
+	metaInformerFactory := metainformers.NewSharedInformerFactoryWithOptions(
 		metaClientset,
 		s.InformerRelist,
+		metaInformerOpts...,
 	)
 
 	// Create dynamic clientset (factory for dynamic clients).
@@ -164,59 +334,97 @@ func (s *CRDServer) Start(workerCount int) (stop func(), err error) {
 	dynamicInformerFactory := dynamicinformer.NewSharedInformerFactory(
 		dynamicClientset,
 		s.InformerRelist,
+		s.Namespaces...,
 	)
+	s.informerFactory = dynamicInformerFactory
+
+	// recorder to emit events against the watched resources
+	recorder, stopRecorder, err := s.newEventRecorder()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+
+	// webhooks may refer to secrets holding their credentials
+	err = s.setWebhookSecretsGetter()
+	if err != nil {
+		stopRecorder()
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+
+	// jsonnet & starlark hooks may refer to configmaps holding
+	// their programs
+	err = s.setHookConfigMapsGetter()
+	if err != nil {
+		stopRecorder()
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+
+	// members of the shard group are known before the controllers
+	// are started
+	shard, err := s.startShard()
+	if err != nil {
+		stopRecorder()
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+	cleanup := func() {
+		shard.Stop()
+		stopRecorder()
+	}
+
+	genericMetac := generic.NewCRDMetaController(
+		s.apiDiscovery,
+		dynamicClientset,
+		dynamicInformerFactory,
+		metaInformerFactory,
+		metaClientset,
+		recorder,
+		shard,
+		workerCount,
+	)
+	genericMetac.DryRun = s.DryRun
+	genericMetac.HookDispatcher = s.hookDispatcher()
+
+	compositeMetac := composite.NewMetacontroller(
+		s.apiDiscovery,
+		dynamicClientset,
+		dynamicInformerFactory,
+		metaInformerFactory,
+		metaClientset,
+		recorder,
+		workerCount,
+	)
+	compositeMetac.HookDispatcher = s.hookDispatcher()
+
+	decoratorMetac := decorator.NewMetacontroller(
+		s.apiDiscovery,
+		dynamicClientset,
+		dynamicInformerFactory,
+		metaInformerFactory,
+		recorder,
+		workerCount,
+	)
+	decoratorMetac.HookDispatcher = s.hookDispatcher()
 
 	// Start various metacontrollers (controllers that spawn controllers).
 	// Each one requests the informers it needs from the factory.
 	metaControllers := []controller{
-		composite.NewMetacontroller(
-			s.apiDiscovery,
-			dynamicClientset,
-			dynamicInformerFactory,
-			metaInformerFactory,
-			metaClientset,
-			workerCount,
-		),
-		decorator.NewMetacontroller(
-			s.apiDiscovery,
-			dynamicClientset,
-			dynamicInformerFactory,
-			metaInformerFactory,
-			workerCount,
-		),
-		generic.NewCRDMetaController(
-			s.apiDiscovery,
-			dynamicClientset,
-			dynamicInformerFactory,
-			metaInformerFactory,
-			workerCount,
-		),
+		compositeMetac,
+		decoratorMetac,
+		genericMetac,
 	}
 
 	// Start all requested informers.
 	// We don't care about stopping this cleanly since it has no external effects.
 	metaInformerFactory.Start(nil)
 
-	// Start all controllers.
-	for _, c := range metaControllers {
-		c.Start()
-	}
-
-	// Return stop function that can be used by the clients
-	// of this method to stop all meta controllers that were
-	// started here
-	return func() {
-		var wg sync.WaitGroup
-		for _, mctl := range metaControllers {
-			wg.Add(1)
-			go func(mctl controller) {
-				defer wg.Done()
-				mctl.Stop()
-			}(mctl)
-		}
-		// wait till all meta controllers are stopped
-		wg.Wait()
-	}, nil
+	// Start all controllers & return the stop function that
+	// can be used by the clients of this method to stop all
+	// meta controllers that were started here
+	return s.runControllersWithCleanup(
+		s.String(),
+		metaControllers,
+		cleanup,
+	)
 }
 
 // ConfigServer represents metac server based on metac
@@ -244,6 +452,11 @@ type ConfigServer struct {
 	// indefinitely till all the watch controllers are started
 	RetryIndefinitelyForStart *bool
 
+	// Interval at which the configs are reloaded to start, stop
+	// or restart the watch controllers. Configs are not reloaded
+	// if this is zero.
+	ConfigReloadInterval time.Duration
+
 	// Number of workers per watch controller
 	workerCount int
 }
@@ -273,13 +486,57 @@ func (s *ConfigServer) Start(workerCount int) (stop func(), err error) {
 	dynamicInformerFactory := dynamicinformer.NewSharedInformerFactory(
 		dynamicClientset,
 		s.InformerRelist,
+		s.Namespaces...,
 	)
+	s.informerFactory = dynamicInformerFactory
+
+	// recorder to emit events against the watched resources
+	recorder, stopRecorder, err := s.newEventRecorder()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+
+	// webhooks may refer to secrets holding their credentials
+	err = s.setWebhookSecretsGetter()
+	if err != nil {
+		stopRecorder()
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+
+	// jsonnet & starlark hooks may refer to configmaps holding
+	// their programs
+	err = s.setHookConfigMapsGetter()
+	if err != nil {
+		stopRecorder()
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+
+	// hooks may run local commands since these are defined via
+	// config files by the ones who run metac
+	common.SetExecHooksEnabled(true)
+
+	// members of the shard group are known before the controllers
+	// are started
+	shard, err := s.startShard()
+	if err != nil {
+		stopRecorder()
+		return nil, errors.Wrapf(err, "Failed to start %s", s)
+	}
+	cleanup := func() {
+		shard.Stop()
+		stopRecorder()
+	}
 
 	// various generic meta controller options to setup meta controller
 	configOpts := []generic.ConfigMetaControllerOption{
 		generic.SetMetacConfigLoadFn(s.GenericControllerConfigLoadFn),
 		generic.SetMetacConfigPath(s.ConfigPath),
 		generic.SetMetacConfigToRetryIndefinitelyForStart(s.RetryIndefinitelyForStart),
+		generic.SetMetacConfigEventRecorder(recorder),
+		generic.SetMetacConfigReloadInterval(s.ConfigReloadInterval),
+		generic.SetMetacConfigShard(shard),
+		generic.SetMetacConfigDryRun(s.DryRun),
+		generic.SetMetacConfigHookDispatcher(s.hookDispatcher()),
 	}
 
 	genericMetac, err := generic.NewConfigMetaController(
@@ -290,6 +547,7 @@ func (s *ConfigServer) Start(workerCount int) (stop func(), err error) {
 		configOpts...,
 	)
 	if err != nil {
+		cleanup()
 		return nil, err
 	}
 
@@ -304,22 +562,11 @@ func (s *ConfigServer) Start(workerCount int) (stop func(), err error) {
 		genericMetac,
 	}
 
-	// Start all controllers.
-	for _, c := range metaControllers {
-		c.Start()
-	}
-
-	// Return a function that will stop all controllers.
-	return func() {
-		var wg sync.WaitGroup
-		for _, mctl := range metaControllers {
-			wg.Add(1)
-			go func(mctl controller) {
-				defer wg.Done()
-				mctl.Stop()
-			}(mctl)
-		}
-		// wait till all meta controllers are stopped
-		wg.Wait()
-	}, nil
+	// Start all controllers & return a function that will
+	// stop all these controllers.
+	return s.runControllersWithCleanup(
+		s.String(),
+		metaControllers,
+		cleanup,
+	)
 }
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+
+	"openebs.io/metac/sharding"
+)
+
+// isShardingEnabled returns true if this server reconciles only
+// the watches owned by its shard
+func (s *Server) isShardingEnabled() bool {
+	return s.Sharding != nil && s.Sharding.Enabled
+}
+
+// startShard joins this server to its shard group. It returns a
+// nil shard if sharding is not enabled.
+//
+// NOTE:
+//	Sharding & leader election are mutually exclusive since only
+// the leader runs the controllers with the latter
+func (s *Server) startShard() (*sharding.Shard, error) {
+	if !s.isShardingEnabled() {
+		return nil, nil
+	}
+	if s.LeaderElection != nil && s.LeaderElection.Enabled {
+		return nil, errors.Errorf(
+			"Sharding can't be enabled along with leader election",
+		)
+	}
+	client, err := coordinationv1.NewForConfig(s.Config)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't create coordination client for sharding",
+		)
+	}
+	shard, err := sharding.New(*s.Sharding, client)
+	if err != nil {
+		return nil, err
+	}
+	err = shard.Start()
+	if err != nil {
+		return nil, err
+	}
+	s.shard = shard
+	return shard, nil
+}
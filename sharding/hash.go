@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"hash/fnv"
+)
+
+// Owner returns the member that owns the provided key. An empty
+// string is returned if there are no members.
+//
+// NOTE:
+//	Keys are assigned via rendezvous i.e. highest random weight
+// hashing. Each member scores the key & the member with the
+// highest score owns it. Hence, when a member joins or leaves,
+// only the keys owned by this member move.
+func Owner(members []string, key string) string {
+	var owner string
+	var maxScore uint64
+	for _, member := range members {
+		s := score(member, key)
+		if owner == "" || s > maxScore || (s == maxScore && member < owner) {
+			owner = member
+			maxScore = s
+		}
+	}
+	return owner
+}
+
+// score returns the weight of the provided key against the
+// provided member
+func score(member, key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(member))
+	// separator avoids collisions between member & key pairs
+	// that concatenate to the same string
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return mix(h.Sum64())
+}
+
+// mix spreads the bits of the provided hash. This evens out the
+// scores of members whose names differ only slightly e.g. the
+// ordinals of a StatefulSet's pods.
+//
+// NOTE:
+//	This is the finalizer of splitmix64
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	h ^= h >> 31
+	return h
+}
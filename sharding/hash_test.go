@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestOwner(t *testing.T) {
+	var tests = map[string]struct {
+		members []string
+		key     string
+		expect  string
+	}{
+		"no members": {
+			key:    "v1:Pod:default:test",
+			expect: "",
+		},
+		"single member": {
+			members: []string{"metac-0"},
+			key:     "v1:Pod:default:test",
+			expect:  "metac-0",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := Owner(mock.members, mock.key)
+			if got != mock.expect {
+				t.Fatalf("Expected owner %q got %q", mock.expect, got)
+			}
+		})
+	}
+}
+
+func TestOwnerIsIndependentOfMemberOrder(t *testing.T) {
+	members := []string{"metac-0", "metac-1", "metac-2"}
+	reversed := []string{"metac-2", "metac-1", "metac-0"}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("v1:Pod:default:test-%d", i)
+		if Owner(members, key) != Owner(reversed, key) {
+			t.Fatalf("Expected same owner for key %q", key)
+		}
+	}
+}
+
+func TestOwnerDistribution(t *testing.T) {
+	members := []string{"metac-0", "metac-1", "metac-2"}
+	total := 3000
+	counts := map[string]int{}
+	for i := 0; i < total; i++ {
+		counts[Owner(members, fmt.Sprintf("v1:Pod:default:test-%d", i))]++
+	}
+	for _, member := range members {
+		// each member is expected to own about a third of the
+		// keys; allow generous skew to keep this test stable
+		if counts[member] < total/6 {
+			t.Fatalf(
+				"Expected member %q to own at least %d keys got %d",
+				member,
+				total/6,
+				counts[member],
+			)
+		}
+	}
+}
+
+func TestOwnerOnlyMovesKeysOfLeavingMember(t *testing.T) {
+	members := []string{"metac-0", "metac-1", "metac-2"}
+	remaining := []string{"metac-0", "metac-2"}
+	for i := 0; i < 1000; i++ {
+		key := fmt.Sprintf("v1:Pod:default:test-%d", i)
+		old := Owner(members, key)
+		new := Owner(remaining, key)
+		if old != "metac-1" && old != new {
+			t.Fatalf(
+				"Expected key %q to stay with %q got %q",
+				key,
+				old,
+				new,
+			)
+		}
+	}
+}
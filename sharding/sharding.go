@@ -0,0 +1,513 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationclientv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+)
+
+const (
+	// DefaultLeaseNamespace is the default namespace of the Lease
+	// resources that track the members of a shard group
+	DefaultLeaseNamespace = "metac"
+
+	// DefaultGroupName is the default name of the shard group
+	DefaultGroupName = "metac"
+
+	// DefaultLeaseDuration is the default duration after which a
+	// member that has not renewed its Lease is considered gone
+	DefaultLeaseDuration = 30 * time.Second
+
+	// DefaultRenewInterval is the default interval at which a
+	// member renews its Lease & refreshes the members of its group
+	DefaultRenewInterval = 10 * time.Second
+
+	// GroupLabelKey is the label set against the Lease of each
+	// member. Its value is the name of the shard group.
+	GroupLabelKey = "sharding.metac.openebs.io/group"
+
+	// staleLeaseMultiplier decides when an expired Lease is
+	// deleted. Leases that were not renewed for these many lease
+	// durations are deleted by any of the live members.
+	staleLeaseMultiplier = 10
+)
+
+// Config has the tunables that let multiple replicas of metac
+// split the watches amongst themselves
+type Config struct {
+	// Enabled when set to true lets this replica reconcile only
+	// the watches that are owned by its shard
+	Enabled bool
+
+	// LeaseNamespace is the namespace of the Lease resources
+	LeaseNamespace string
+
+	// GroupName is the name of the shard group. Replicas with
+	// the same group name split the watches amongst themselves.
+	GroupName string
+
+	// LeaseDuration is the duration after which a member that
+	// has not renewed its Lease is considered gone
+	LeaseDuration time.Duration
+
+	// RenewInterval is the interval at which the Lease of this
+	// member is renewed & the members of the group are refreshed
+	RenewInterval time.Duration
+
+	// Identity uniquely identifies this member in its group. It
+	// defaults to the hostname which is stable for the pods of a
+	// StatefulSet.
+	Identity string
+}
+
+// setDefaultsIfNotSet sets default values against the fields
+// that were not set
+func (c *Config) setDefaultsIfNotSet() error {
+	if c.LeaseNamespace == "" {
+		c.LeaseNamespace = DefaultLeaseNamespace
+	}
+	if c.GroupName == "" {
+		c.GroupName = DefaultGroupName
+	}
+	if c.LeaseDuration == 0 {
+		c.LeaseDuration = DefaultLeaseDuration
+	}
+	if c.RenewInterval == 0 {
+		c.RenewInterval = DefaultRenewInterval
+	}
+	if c.RenewInterval >= c.LeaseDuration {
+		return errors.Errorf(
+			"Invalid sharding config: Renew interval %s must be less than lease duration %s",
+			c.RenewInterval,
+			c.LeaseDuration,
+		)
+	}
+	if c.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return errors.Wrapf(err, "Can't build sharding identity")
+		}
+		c.Identity = strings.ToLower(hostname)
+	}
+	return nil
+}
+
+// Shard tracks the live members of a shard group via Leases &
+// decides the keys owned by this member
+//
+// NOTE:
+//	All the methods are safe to be invoked on a nil shard. A nil
+// shard owns all the keys.
+//
+// NOTE:
+//	A member whose Lease was not renewed within the lease duration
+// owns no keys. Other members consider this member gone by then &
+// take over its keys. This fences this member from reconciling the
+// same keys as other members e.g. when the API server is not
+// reachable from this member only.
+type Shard struct {
+	config    Config
+	client    coordinationclientv1.LeasesGetter
+	leaseName string
+
+	// mutex guards members, renewedAt & listeners
+	mutex          sync.RWMutex
+	members        []string
+	listeners      map[int]func()
+	nextListenerID int
+
+	// time at which the Lease of this member was last renewed
+	renewedAt time.Time
+
+	stopCh, doneCh chan struct{}
+}
+
+// New returns a new instance of Shard
+func New(
+	config Config,
+	client coordinationclientv1.LeasesGetter,
+) (*Shard, error) {
+	err := config.setDefaultsIfNotSet()
+	if err != nil {
+		return nil, err
+	}
+	return &Shard{
+		config:    config,
+		client:    client,
+		leaseName: config.GroupName + "-" + config.Identity,
+		members:   []string{config.Identity},
+		listeners: map[int]func(){},
+		stopCh:    make(chan struct{}),
+		doneCh:    make(chan struct{}),
+	}, nil
+}
+
+// String implements Stringer interface
+func (s *Shard) String() string {
+	if s == nil {
+		return "Shard"
+	}
+	return fmt.Sprintf(
+		"Shard %q of group %q",
+		s.config.Identity,
+		s.config.GroupName,
+	)
+}
+
+// Start registers this member with its group & keeps renewing
+// its Lease in the background.
+//
+// NOTE:
+//	Members of the group are known by the time this returns.
+// Hence controllers should be started after this.
+func (s *Shard) Start() error {
+	err := s.sync()
+	if err != nil {
+		return errors.Wrapf(err, "Failed to start %s", s)
+	}
+	glog.Infof("Started %s: Members %q", s, s.Members())
+	go func() {
+		defer close(s.doneCh)
+		wait.Until(
+			func() {
+				err := s.sync()
+				if err != nil {
+					glog.Errorf("Failed to sync %s: %v", s, err)
+				}
+			},
+			s.config.RenewInterval,
+			s.stopCh,
+		)
+	}()
+	return nil
+}
+
+// Stop stops renewing the Lease of this member & deletes this
+// Lease. This lets the remaining members take over the keys of
+// this member without waiting for this Lease to expire.
+//
+// NOTE:
+//	This must be invoked only after a successful Start
+func (s *Shard) Stop() {
+	if s == nil {
+		return
+	}
+	close(s.stopCh)
+	<-s.doneCh
+
+	err := s.client.Leases(s.config.LeaseNamespace).Delete(
+		s.leaseName,
+		&metav1.DeleteOptions{},
+	)
+	if err != nil && !apierrors.IsNotFound(err) {
+		glog.Warningf("Failed to delete lease of %s: %v", s, err)
+		return
+	}
+	glog.Infof("Stopped %s", s)
+}
+
+// Identity returns the identity of this member
+func (s *Shard) Identity() string {
+	if s == nil {
+		return ""
+	}
+	return s.config.Identity
+}
+
+// Owns returns true if the provided key is owned by this member
+func (s *Shard) Owns(key string) bool {
+	if s == nil {
+		return true
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if s.isFencedLocked() {
+		return false
+	}
+	return Owner(s.members, key) == s.config.Identity
+}
+
+// isFencedLocked returns true if the Lease of this member was not
+// renewed within the lease duration
+//
+// NOTE:
+//	This must be invoked while holding the lock
+func (s *Shard) isFencedLocked() bool {
+	return time.Since(s.renewedAt) >= s.config.LeaseDuration
+}
+
+// Members returns the sorted identities of the live members of
+// this group
+func (s *Shard) Members() []string {
+	if s == nil {
+		return nil
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return append([]string(nil), s.members...)
+}
+
+// AddListener registers the provided function to be invoked
+// whenever the members of this group change. The returned
+// function removes this listener.
+func (s *Shard) AddListener(fn func()) (remove func()) {
+	if s == nil {
+		return func() {}
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id := s.nextListenerID
+	s.nextListenerID++
+	s.listeners[id] = fn
+	return func() {
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.listeners, id)
+	}
+}
+
+// sync renews the Lease of this member & refreshes the members
+// of this group
+func (s *Shard) sync() error {
+	// Lease expires a lease duration after the renew time set by
+	// renewLease. This time is not later than that.
+	renewedAt := time.Now()
+	err := s.renewLease()
+	if err != nil {
+		s.mutex.RLock()
+		isFenced := s.isFencedLocked()
+		s.mutex.RUnlock()
+		if isFenced {
+			glog.Warningf(
+				"%s owns no keys: Lease not renewed since %s",
+				s,
+				s.getRenewedAt().Format(time.RFC3339),
+			)
+		}
+		return err
+	}
+	s.setRenewedAt(renewedAt)
+	members, err := s.listMembers()
+	if err != nil {
+		return err
+	}
+	s.setMembers(members)
+	return nil
+}
+
+// renewLease creates or renews the Lease of this member
+func (s *Shard) renewLease() error {
+	leases := s.client.Leases(s.config.LeaseNamespace)
+	now := metav1.NewMicroTime(time.Now())
+	durationSeconds := int32(s.config.LeaseDuration / time.Second)
+
+	lease, err := leases.Get(s.leaseName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = leases.Create(&coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: s.config.LeaseNamespace,
+				Name:      s.leaseName,
+				Labels: map[string]string{
+					GroupLabelKey: s.config.GroupName,
+				},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &s.config.Identity,
+				LeaseDurationSeconds: &durationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		})
+		if err != nil {
+			return errors.Wrapf(err, "Can't create lease %q", s.leaseName)
+		}
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "Can't get lease %q", s.leaseName)
+	}
+	lease.Spec.HolderIdentity = &s.config.Identity
+	lease.Spec.LeaseDurationSeconds = &durationSeconds
+	lease.Spec.RenewTime = &now
+	_, err = leases.Update(lease)
+	if err != nil {
+		return errors.Wrapf(err, "Can't renew lease %q", s.leaseName)
+	}
+	return nil
+}
+
+// listMembers returns the sorted identities of the members whose
+// Leases have not expired. Leases that expired long back are
+// deleted.
+func (s *Shard) listMembers() ([]string, error) {
+	leases := s.client.Leases(s.config.LeaseNamespace)
+	list, err := leases.List(metav1.ListOptions{
+		LabelSelector: GroupLabelKey + "=" + s.config.GroupName,
+	})
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't list leases of group %q",
+			s.config.GroupName,
+		)
+	}
+	now := time.Now()
+	// this member is always live from its own point of view
+	members := []string{s.config.Identity}
+	for i := range list.Items {
+		lease := &list.Items[i]
+		holder := lease.Spec.HolderIdentity
+		if holder == nil || *holder == "" || *holder == s.config.Identity {
+			continue
+		}
+		expiry := leaseExpiry(lease)
+		if now.Before(expiry) {
+			members = append(members, *holder)
+			continue
+		}
+		if now.Sub(expiry) > staleLeaseMultiplier*s.config.LeaseDuration {
+			s.deleteStaleLease(lease)
+		}
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// deleteStaleLease deletes the provided Lease unless it was
+// renewed after it was listed
+func (s *Shard) deleteStaleLease(lease *coordinationv1.Lease) {
+	resourceVersion := lease.ResourceVersion
+	err := s.client.Leases(lease.Namespace).Delete(
+		lease.Name,
+		&metav1.DeleteOptions{
+			Preconditions: &metav1.Preconditions{
+				ResourceVersion: &resourceVersion,
+			},
+		},
+	)
+	if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+		glog.Warningf(
+			"Failed to delete stale lease %q: %s: %v",
+			lease.Name,
+			s,
+			err,
+		)
+		return
+	}
+	glog.V(4).Infof("Deleted stale lease %q: %s", lease.Name, s)
+}
+
+// leaseExpiry returns the time at which the provided Lease
+// expires. A zero time is returned if this can't be determined.
+func leaseExpiry(lease *coordinationv1.Lease) time.Time {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return time.Time{}
+	}
+	return lease.Spec.RenewTime.Add(
+		time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second,
+	)
+}
+
+// getRenewedAt returns the time at which the Lease of this member
+// was last renewed
+func (s *Shard) getRenewedAt() time.Time {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return s.renewedAt
+}
+
+// setRenewedAt sets the time at which the Lease of this member was
+// renewed & notifies the listeners if this member was fenced
+// earlier. This member owns its keys again.
+func (s *Shard) setRenewedAt(renewedAt time.Time) {
+	s.mutex.Lock()
+	wasFenced := !s.renewedAt.IsZero() && s.isFencedLocked()
+	s.renewedAt = renewedAt
+	if !wasFenced {
+		s.mutex.Unlock()
+		return
+	}
+	listeners := s.listListenersLocked()
+	s.mutex.Unlock()
+
+	glog.Infof("Renewed lease: %s owns keys again", s)
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// listListenersLocked returns the registered listeners
+//
+// NOTE:
+//	This must be invoked while holding the lock
+func (s *Shard) listListenersLocked() []func() {
+	var listeners []func()
+	for _, fn := range s.listeners {
+		listeners = append(listeners, fn)
+	}
+	return listeners
+}
+
+// setMembers sets the provided members & notifies the listeners
+// if the members changed
+func (s *Shard) setMembers(members []string) {
+	s.mutex.Lock()
+	if isEqual(s.members, members) {
+		s.mutex.Unlock()
+		return
+	}
+	s.members = members
+	listeners := s.listListenersLocked()
+	s.mutex.Unlock()
+
+	glog.Infof("Members changed: %s: Members %q", s, members)
+	// listeners are invoked without holding the lock since they
+	// are expected to check the ownership of their keys
+	for _, fn := range listeners {
+		fn()
+	}
+}
+
+// isEqual returns true if the provided sorted lists are same
+func isEqual(old, new []string) bool {
+	if len(old) != len(new) {
+		return false
+	}
+	for i := range old {
+		if old[i] != new[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestNilShard(t *testing.T) {
+	var s *Shard
+	if !s.Owns("v1:Pod:default:test") {
+		t.Fatalf("Expected nil shard to own all the keys")
+	}
+	if len(s.Members()) != 0 {
+		t.Fatalf("Expected no members got %q", s.Members())
+	}
+	// must not panic
+	s.AddListener(func() {})()
+	s.Stop()
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(
+		Config{
+			Identity:      "metac-0",
+			LeaseDuration: time.Second,
+			RenewInterval: 2 * time.Second,
+		},
+		fake.NewSimpleClientset().CoordinationV1(),
+	)
+	if err == nil {
+		t.Fatalf("Expected error when renew interval exceeds lease duration")
+	}
+}
+
+func TestShardMembership(t *testing.T) {
+	client := fake.NewSimpleClientset().CoordinationV1()
+	newShard := func(identity string) *Shard {
+		s, err := New(Config{Identity: identity}, client)
+		if err != nil {
+			t.Fatalf("Expected no error got %v", err)
+		}
+		return s
+	}
+	first := newShard("metac-0")
+	second := newShard("metac-1")
+
+	var notified int
+	first.AddListener(func() { notified++ })
+
+	if err := first.sync(); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if err := second.sync(); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if err := first.sync(); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(first.Members()) != 2 || len(second.Members()) != 2 {
+		t.Fatalf(
+			"Expected 2 members got %q & %q",
+			first.Members(),
+			second.Members(),
+		)
+	}
+	if notified != 1 {
+		t.Fatalf("Expected 1 notification got %d", notified)
+	}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("v1:Pod:default:test-%d", i)
+		if first.Owns(key) == second.Owns(key) {
+			t.Fatalf("Expected key %q to be owned by exactly one member", key)
+		}
+	}
+
+	// remove the lease of the second member
+	err := client.Leases(DefaultLeaseNamespace).Delete(second.leaseName, nil)
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if err := first.sync(); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if len(first.Members()) != 1 {
+		t.Fatalf("Expected 1 member got %q", first.Members())
+	}
+	if notified != 2 {
+		t.Fatalf("Expected 2 notifications got %d", notified)
+	}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("v1:Pod:default:test-%d", i)
+		if !first.Owns(key) {
+			t.Fatalf("Expected key %q to be owned by the only member", key)
+		}
+	}
+}
+
+func TestShardFencing(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	s, err := New(Config{Identity: "metac-0"}, client.CoordinationV1())
+	if err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	key := "v1:Pod:default:test"
+	if s.Owns(key) {
+		t.Fatalf("Expected no keys to be owned before the lease is renewed")
+	}
+
+	var notified int
+	s.AddListener(func() { notified++ })
+	if err := s.sync(); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if !s.Owns(key) {
+		t.Fatalf("Expected key %q to be owned by the only member", key)
+	}
+
+	// lease was not renewed within the lease duration
+	s.mutex.Lock()
+	s.renewedAt = time.Now().Add(-s.config.LeaseDuration)
+	s.mutex.Unlock()
+	if s.Owns(key) {
+		t.Fatalf("Expected no keys to be owned by a fenced member")
+	}
+
+	client.PrependReactor(
+		"get",
+		"leases",
+		func(action clienttesting.Action) (bool, runtime.Object, error) {
+			return true, nil, errors.New("unreachable")
+		},
+	)
+	if err := s.sync(); err == nil {
+		t.Fatalf("Expected error got none")
+	}
+	if s.Owns(key) {
+		t.Fatalf("Expected no keys to be owned after a failed renewal")
+	}
+	if notified != 0 {
+		t.Fatalf("Expected no notifications got %d", notified)
+	}
+
+	client.ReactionChain = client.ReactionChain[1:]
+	if err := s.sync(); err != nil {
+		t.Fatalf("Expected no error got %v", err)
+	}
+	if !s.Owns(key) {
+		t.Fatalf("Expected key %q to be owned after the lease is renewed", key)
+	}
+	if notified != 1 {
+		t.Fatalf("Expected 1 notification got %d", notified)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
@@ -32,7 +33,13 @@ import (
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
+	"openebs.io/metac/debug"
+	"openebs.io/metac/health"
+	"openebs.io/metac/hooks"
+	"openebs.io/metac/hooks/webhook"
+	"openebs.io/metac/metrics"
 	"openebs.io/metac/server"
+	"openebs.io/metac/sharding"
 )
 
 var (
@@ -51,6 +58,11 @@ var (
 		":9999",
 		"The address to bind the debug http endpoints",
 	)
+	enablePprof = flag.Bool(
+		"enable-pprof",
+		false,
+		"When true pprof endpoints are served at /debug/pprof/ of the debug address",
+	)
 	kubeAPIServerURL = flag.String(
 		"kube-apiserver-url",
 		"",
@@ -96,6 +108,118 @@ var (
 		`When true will let metac to retry continuously till all its controllers are started.
 		 Applicable if run-as-local is set to true`,
 	)
+	metacConfigReloadInterval = flag.Duration(
+		"metac-config-reload-interval",
+		0,
+		`How often to reload metac config files to start, stop or restart the
+		 corresponding controllers. Configs are not reloaded if this is zero.
+		 Applicable if run-as-local is set to true`,
+	)
+	namespaces = flag.String(
+		"namespaces",
+		"",
+		`Comma separated namespaces to restrict the watches, attachments,
+		 parents & children to. Resources are watched cluster wide if this
+		 is empty`,
+	)
+	leaderElect = flag.Bool(
+		"leader-elect",
+		false,
+		`When true will let metac to elect a leader amongst its replicas.
+		 Only the leader runs the controllers`,
+	)
+	leaderElectLeaseDuration = flag.Duration(
+		"leader-elect-lease-duration",
+		server.DefaultLeaseDuration,
+		`Duration that non-leader candidates will wait before attempting to acquire leadership.
+		 Applicable if leader-elect is set to true`,
+	)
+	leaderElectRenewDeadline = flag.Duration(
+		"leader-elect-renew-deadline",
+		server.DefaultRenewDeadline,
+		`Duration that the leader will retry refreshing leadership before giving up.
+		 Applicable if leader-elect is set to true`,
+	)
+	leaderElectRetryPeriod = flag.Duration(
+		"leader-elect-retry-period",
+		server.DefaultRetryPeriod,
+		`Duration the candidates should wait between attempts to acquire or renew leadership.
+		 Applicable if leader-elect is set to true`,
+	)
+	leaderElectLockNamespace = flag.String(
+		"leader-elect-lock-namespace",
+		server.DefaultLeaseLockNamespace,
+		`Namespace of the Lease resource used for leader election.
+		 Applicable if leader-elect is set to true`,
+	)
+	leaderElectLockName = flag.String(
+		"leader-elect-lock-name",
+		server.DefaultLeaseLockName,
+		`Name of the Lease resource used for leader election.
+		 Applicable if leader-elect is set to true`,
+	)
+	shard = flag.Bool(
+		"shard",
+		false,
+		`When true will let metac replicas split the watches of GenericControllers
+		 amongst themselves`,
+	)
+	shardLeaseNamespace = flag.String(
+		"shard-lease-namespace",
+		sharding.DefaultLeaseNamespace,
+		`Namespace of the Lease resources used to track the replicas.
+		 Applicable if shard is set to true`,
+	)
+	shardGroupName = flag.String(
+		"shard-group-name",
+		sharding.DefaultGroupName,
+		`Name of the group of replicas that split the watches.
+		 Applicable if shard is set to true`,
+	)
+	shardLeaseDuration = flag.Duration(
+		"shard-lease-duration",
+		sharding.DefaultLeaseDuration,
+		`Duration after which a replica that has not renewed its Lease is considered gone.
+		 Applicable if shard is set to true`,
+	)
+	shardRenewInterval = flag.Duration(
+		"shard-renew-interval",
+		sharding.DefaultRenewInterval,
+		`How often a replica renews its Lease & refreshes the replicas of its group.
+		 Applicable if shard is set to true`,
+	)
+	dryRun = flag.Bool(
+		"dry-run",
+		false,
+		`When true will let GenericControllers compute & report the changes to
+		 their watches & attachments without applying them`,
+	)
+	webhookSecretNamespaces = flag.String(
+		"webhook-secret-namespaces",
+		"",
+		`Comma separated namespaces of the Secrets that can be referred to by
+		 the webhooks. Webhooks can't refer to any Secret if this is empty`,
+	)
+	webhookMaxIdleConns = flag.Int(
+		"webhook-max-idle-conns",
+		webhook.DefaultMaxIdleConns,
+		"Maximum number of idle connections across all the webhook servers",
+	)
+	webhookMaxIdleConnsPerHost = flag.Int(
+		"webhook-max-idle-conns-per-host",
+		webhook.DefaultMaxIdleConnsPerHost,
+		"Maximum number of idle connections per webhook server",
+	)
+	webhookIdleConnTimeout = flag.Duration(
+		"webhook-idle-conn-timeout",
+		webhook.DefaultIdleConnTimeout,
+		"Duration after which an idle connection to a webhook server is closed",
+	)
+	webhookDisableHTTP2 = flag.Bool(
+		"webhook-disable-http2",
+		false,
+		"When true HTTP/2 is not attempted for TLS connections to webhook servers",
+	)
 )
 
 // KubeDetails provides kubernetes config & api discovery instance
@@ -110,14 +234,48 @@ var (
 // discovery utility.
 var KubeDetails *server.KubeDetails
 
+// Option customises the metac server started by this binary
+//
+// NOTE:
+//	This follows "functional options" pattern
+type Option func(*server.Server)
+
+// WithInlineHooks sets the registry that resolves the inline hooks
+// of the meta controllers
+func WithInlineHooks(registry *hooks.Registry) Option {
+	return func(s *server.Server) {
+		s.InlineHooks = registry
+	}
+}
+
+// WithHookMiddlewares sets the middlewares that wrap every hook
+// invocation of the meta controllers. First middleware is the
+// outermost one.
+func WithHookMiddlewares(middlewares ...hooks.Middleware) Option {
+	return func(s *server.Server) {
+		s.HookMiddlewares = middlewares
+	}
+}
+
 // Start starts this binary
 func Start() {
+	StartWith()
+}
+
+// StartWith starts this binary with the metac server customised
+// by the given options
+func StartWith(opts ...Option) {
 	flag.Parse()
 
 	glog.Infof("Discovery cache refresh interval: %v", *discoveryInterval)
 	glog.Infof("API server relist interval i.e. cache flush interval: %v", *informerRelist)
 	glog.Infof("Debug http server address: %v", *debugAddr)
 	glog.Infof("Run metac locally: %t", *runAsLocal)
+	glog.Infof("Leader election: %t", *leaderElect)
+	glog.Infof("Sharding: %t", *shard)
+	glog.Infof("Dry run: %t", *dryRun)
+	glog.Infof("Namespaces: %q", *namespaces)
+	glog.Infof("Webhook secret namespaces: %q", *webhookSecretNamespaces)
 
 	var config *rest.Config
 	var err error
@@ -137,6 +295,23 @@ func Start() {
 	config.QPS = float32(*clientGoQPS)
 	config.Burst = *clientGoBurst
 
+	// Metrics need to be registered before starting the
+	// controllers since workqueue metrics get applied only
+	// to the workqueues created after this registration
+	err = metrics.Register()
+	if err != nil {
+		glog.Fatalf("Can't register metrics: %v", err)
+	}
+
+	// connections to webhook servers are pooled via this
+	// transport config
+	webhook.SetTransportConfig(webhook.TransportConfig{
+		MaxIdleConns:        *webhookMaxIdleConns,
+		MaxIdleConnsPerHost: *webhookMaxIdleConnsPerHost,
+		IdleConnTimeout:     *webhookIdleConnTimeout,
+		DisableHTTP2:        *webhookDisableHTTP2,
+	})
+
 	// declare the stop server function
 	var stopServer func()
 	// common server values
@@ -144,6 +319,27 @@ func Start() {
 		Config:            config,
 		DiscoveryInterval: *discoveryInterval,
 		InformerRelist:    *informerRelist,
+		LeaderElection: &server.LeaderElectionConfig{
+			Enabled:       *leaderElect,
+			LeaseDuration: *leaderElectLeaseDuration,
+			RenewDeadline: *leaderElectRenewDeadline,
+			RetryPeriod:   *leaderElectRetryPeriod,
+			LockNamespace: *leaderElectLockNamespace,
+			LockName:      *leaderElectLockName,
+		},
+		Namespaces: splitNamespaces(*namespaces),
+		Sharding: &sharding.Config{
+			Enabled:        *shard,
+			LeaseNamespace: *shardLeaseNamespace,
+			GroupName:      *shardGroupName,
+			LeaseDuration:  *shardLeaseDuration,
+			RenewInterval:  *shardRenewInterval,
+		},
+		WebhookSecretNamespaces: splitNamespaces(*webhookSecretNamespaces),
+		DryRun:                  *dryRun,
+	}
+	for _, o := range opts {
+		o(mserver)
 	}
 	// start metac either as config based or CRD based
 	if *runAsLocal {
@@ -154,6 +350,7 @@ func Start() {
 			Server:                    mserver,
 			ConfigPath:                *metacConfigPath,
 			RetryIndefinitelyForStart: retryIndefinitelyToStart,
+			ConfigReloadInterval:      *metacConfigReloadInterval,
 		}
 		stopServer, err = configServer.Start(*workerCount)
 	} else {
@@ -179,6 +376,29 @@ func Start() {
 
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", exporter)
+	mux.Handle("/healthz", health.NewHandler("healthz", mserver.HealthzChecks))
+	mux.Handle("/readyz", health.NewHandler("readyz", mserver.ReadyzChecks))
+	mux.Handle(
+		"/debug/controllers",
+		debug.NewJSONHandler(func() interface{} {
+			return mserver.DebugControllers()
+		}),
+	)
+	mux.Handle(
+		"/debug/informers",
+		debug.NewJSONHandler(func() interface{} {
+			return mserver.DebugInformers()
+		}),
+	)
+	mux.Handle(
+		"/debug/shard",
+		debug.NewJSONHandler(func() interface{} {
+			return mserver.DebugShard()
+		}),
+	)
+	if *enablePprof {
+		debug.RegisterPprof(mux)
+	}
 	httpServer := &http.Server{
 		Addr:    *debugAddr,
 		Handler: mux,
@@ -199,3 +419,16 @@ func Start() {
 	stopServer()
 	httpServer.Shutdown(context.Background())
 }
+
+// splitNamespaces returns the namespaces from the provided comma
+// separated namespaces
+func splitNamespaces(namespaces string) []string {
+	var nsList []string
+	for _, ns := range strings.Split(namespaces, ",") {
+		ns = strings.TrimSpace(ns)
+		if ns != "" {
+			nsList = append(nsList, ns)
+		}
+	}
+	return nsList
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package configmode
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog"
+
+	"openebs.io/metac/apis/metacontroller/v1alpha1"
+	"openebs.io/metac/controller/generic"
+	"openebs.io/metac/test/integration/framework"
+	k8s "openebs.io/metac/third_party/kubernetes"
+)
+
+// TestLocalLeaderElection will verify if only the elected
+// leader amongst multiple metac servers reconciles the watch.
+// It also verifies if leadership moves to another metac server
+// once the current leader is stopped.
+func TestLocalLeaderElection(t *testing.T) {
+
+	// name of the GenericController
+	ctlName := "leader-election-localgctrl"
+
+	// name of the target namespace that gets watched
+	targetNamespaceName := "leader-election-ns"
+
+	// name of the lease used as the leader election lock
+	lockName := "leader-election-local-lock"
+
+	f := framework.NewFixture(t)
+	defer f.TearDown()
+
+	// number of times each metac server reconciled the watch
+	var syncCounts [2]int32
+
+	// start two metac servers that compete for leadership
+	type metac struct {
+		isLeading func() bool
+		stop      func()
+	}
+	var metacs [2]*metac
+
+	for i := range metacs {
+		// index is captured by the hook
+		index := i
+
+		// ------------------------------------------------------------
+		// Define the "reconcile logic" for sync i.e. create/update event
+		// ------------------------------------------------------------
+		sHook := func(req *generic.SyncHookRequest, resp *generic.SyncHookResponse) error {
+			atomic.AddInt32(&syncCounts[index], 1)
+			// there are no attachments to be reconciled
+			resp.SkipReconcile = true
+			return nil
+		}
+
+		// Add this sync hook implementation to inline hook registry
+		inlineHookName := []string{
+			"test/gctl-local-leader-election-0",
+			"test/gctl-local-leader-election-1",
+		}[index]
+		generic.AddToInlineRegistry(inlineHookName, sHook)
+
+		gctlConfig := f.CreateGenericControllerAsMetacConfig(
+			ctlName,
+
+			// set sync hook
+			generic.WithInlinehookSyncFunc(k8s.StringPtr(inlineHookName)),
+
+			// We want Namespace as our watched resource
+			generic.WithWatch(
+				&v1alpha1.GenericControllerResource{
+					ResourceRule: v1alpha1.ResourceRule{
+						APIVersion: "v1",
+						Resource:   "namespaces",
+					},
+					// We are interested only for our namespace
+					NameSelector: []string{targetNamespaceName},
+				},
+			),
+		)
+
+		server, stop := f.StartMetacWithLeaderElectionFromGenericControllerConfig(
+			func() ([]*v1alpha1.GenericController, error) {
+				return []*v1alpha1.GenericController{gctlConfig}, nil
+			},
+			"default",
+			lockName,
+		)
+		metacs[index] = &metac{
+			isLeading: server.IsLeading,
+			stop:      stop,
+		}
+	}
+	defer func() {
+		for _, m := range metacs {
+			if m != nil {
+				m.stop()
+			}
+		}
+	}()
+
+	// index of the metac server that is the leader
+	var leader int
+
+	klog.Infof("Will wait for a leader to be elected")
+	err := f.Wait(func() (bool, error) {
+		if metacs[0].isLeading() && metacs[1].isLeading() {
+			// this is a failure & must not be retried
+			return true, errors.Errorf("Both metac servers are leading")
+		}
+		if metacs[0].isLeading() {
+			leader = 0
+			return true, nil
+		}
+		if metacs[1].isLeading() {
+			leader = 1
+			return true, nil
+		}
+		return false, errors.Errorf("No metac server is leading")
+	})
+	if err != nil {
+		t.Fatalf("Failed to elect leader: %v", err)
+	}
+	follower := 1 - leader
+	klog.Infof("Metac server %d was elected as leader", leader)
+
+	// create the target namespace to trigger the watch
+	f.CreateNamespace(targetNamespaceName)
+
+	klog.Infof("Will wait for leader to reconcile the watch")
+	err = f.Wait(func() (bool, error) {
+		if atomic.LoadInt32(&syncCounts[leader]) == 0 {
+			return false, errors.Errorf("Leader did not reconcile")
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to reconcile by leader: %v", err)
+	}
+	if count := atomic.LoadInt32(&syncCounts[follower]); count != 0 {
+		t.Fatalf(
+			"Expected no reconciliation by follower: Got %d",
+			count,
+		)
+	}
+
+	// stop the leader so that leadership moves to the follower
+	metacs[leader].stop()
+	metacs[leader] = nil
+
+	klog.Infof("Will wait for follower to take over leadership")
+	err = f.Wait(func() (bool, error) {
+		if !metacs[follower].isLeading() {
+			return false, errors.Errorf("Follower is not leading")
+		}
+		if atomic.LoadInt32(&syncCounts[follower]) == 0 {
+			return false, errors.Errorf("Follower did not reconcile")
+		}
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to move leadership to follower: %v", err)
+	}
+	klog.Infof("Leadership moved to metac server %d", follower)
+}
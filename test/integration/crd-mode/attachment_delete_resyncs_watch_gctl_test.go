@@ -0,0 +1,267 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdmode
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/klog"
+
+	"openebs.io/metac/controller/generic"
+	"openebs.io/metac/test/integration/framework"
+	"openebs.io/metac/third_party/kubernetes"
+)
+
+// TestAttachmentDeleteResyncsWatchViaGctl verifies if deleting an
+// attachment resyncs its watch. GenericController is configured
+// without any resync period. Hence the attachment can only be
+// re-created if its delete event resyncs the watch.
+func TestAttachmentDeleteResyncsWatchViaGctl(t *testing.T) {
+	f := framework.NewIntegrationTester(t)
+	defer f.TearDown()
+
+	namespaceName := "ns-adrwvg"
+
+	// define "reconcile logic" in this hook
+	syncHook := f.ServeWebhook(func(body []byte) ([]byte, error) {
+		req := generic.SyncHookRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+
+		attachment := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "AttachmentResyncAttachment",
+				"apiVersion": "integration.test.io/v1",
+				"metadata": map[string]interface{}{
+					"name":      "my-attachment",
+					"namespace": namespaceName,
+					"labels": map[string]interface{}{
+						"app": "metac",
+					},
+				},
+			},
+		}
+		resp := generic.SyncHookResponse{
+			Attachments: []*unstructured.Unstructured{attachment},
+		}
+		return json.Marshal(resp)
+	})
+
+	// Run the testcase here
+	//
+	// NOTE:
+	// 	TestSteps are executed in their defined order
+	result, err := f.Test(
+		[]framework.TestStep{
+			framework.TestStep{
+				Name: "create-test-namespace",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "Namespace",
+							"apiVersion": "v1",
+							"metadata": map[string]interface{}{
+								"name": namespaceName,
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-watch-crd",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": "apiextensions.k8s.io/v1beta1",
+							"kind":       "CustomResourceDefinition",
+							"metadata": map[string]interface{}{
+								"name": "attachmentresyncwatches.integration.test.io",
+							},
+							"spec": map[string]interface{}{
+								"version": "v1",
+								"group":   "integration.test.io",
+								"scope":   "Namespaced",
+								"names": map[string]interface{}{
+									"kind":     "AttachmentResyncWatch",
+									"listKind": "AttachmentResyncWatchList",
+									"singular": "attachmentresyncwatch",
+									"plural":   "attachmentresyncwatches",
+									"shortNames": []interface{}{
+										"arwatch",
+									},
+								},
+								"versions": []interface{}{
+									map[string]interface{}{
+										"name":    "v1",
+										"served":  true,
+										"storage": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-attachment-crd",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": "apiextensions.k8s.io/v1beta1",
+							"kind":       "CustomResourceDefinition",
+							"metadata": map[string]interface{}{
+								"name": "attachmentresyncattachments.integration.test.io",
+							},
+							"spec": map[string]interface{}{
+								"version": "v1",
+								"group":   "integration.test.io",
+								"scope":   "Namespaced",
+								"names": map[string]interface{}{
+									"kind":     "AttachmentResyncAttachment",
+									"listKind": "AttachmentResyncAttachmentList",
+									"singular": "attachmentresyncattachment",
+									"plural":   "attachmentresyncattachments",
+									"shortNames": []interface{}{
+										"arattachment",
+									},
+								},
+								"versions": []interface{}{
+									map[string]interface{}{
+										"name":    "v1",
+										"served":  true,
+										"storage": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-watch-resource",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "AttachmentResyncWatch",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      "my-watch",
+								"namespace": namespaceName,
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-generic-controller",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "GenericController",
+							"apiVersion": "metac.openebs.io/v1alpha1",
+							"metadata": map[string]interface{}{
+								"name":      "attachment-delete-resyncs-watch",
+								"namespace": namespaceName,
+							},
+							"spec": map[string]interface{}{
+								"watch": map[string]interface{}{
+									"apiVersion": "integration.test.io/v1",
+									"resource":   "attachmentresyncwatches",
+								},
+								"attachments": []interface{}{
+									map[string]interface{}{
+										"apiVersion": "integration.test.io/v1",
+										"resource":   "attachmentresyncattachments",
+									},
+								},
+								"hooks": map[string]interface{}{
+									"sync": map[string]interface{}{
+										"webhook": map[string]interface{}{
+											"url": kubernetes.StringPtr(syncHook.URL),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "assert-presence-of-attachment",
+				Assert: &framework.Assert{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "AttachmentResyncAttachment",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      "my-attachment",
+								"namespace": namespaceName,
+								"labels": map[string]interface{}{
+									"app": "metac",
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "delete-attachment",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "AttachmentResyncAttachment",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      "my-attachment",
+								"namespace": namespaceName,
+							},
+							"spec": nil, // implies delete
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "assert-re-creation-of-attachment",
+				Assert: &framework.Assert{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "AttachmentResyncAttachment",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      "my-attachment",
+								"namespace": namespaceName,
+								"labels": map[string]interface{}{
+									"app": "metac",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Test failed: %+v", err)
+	}
+	if result.Phase == framework.TestStepResultFailed {
+		t.Fatalf("Test failed:\n%s", result)
+	}
+	klog.Infof("Test passed:\n%s", result)
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crdmode
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	"k8s.io/klog"
+
+	"openebs.io/metac/controller/composite"
+	"openebs.io/metac/test/integration/framework"
+	"openebs.io/metac/third_party/kubernetes"
+)
+
+// TestPrePostUpdateChildViaCctl verifies if preUpdateChild hook
+// can delay the update of a child & postUpdateChild hook gets
+// invoked once the child is updated
+func TestPrePostUpdateChildViaCctl(t *testing.T) {
+	f := framework.NewIntegrationTester(t)
+	defer f.TearDown()
+
+	watchName := "watch-ppucvcctl"
+	namespaceName := "ns-ppucvcctl"
+	attachmentName := "attachment-ppucvcctl"
+
+	// number of times update child hooks were invoked
+	var preUpdateCount, postUpdateCount int32
+
+	// define "reconcile logic" in this hook
+	syncHook := f.ServeWebhook(func(body []byte) ([]byte, error) {
+		req := composite.SyncHookRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+		version, _, err := unstructured.NestedString(
+			req.Parent.Object,
+			"spec",
+			"version",
+		)
+		if err != nil {
+			return nil, err
+		}
+		child := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "PrePostUpdateChildCCtlAttachment",
+				"apiVersion": "integration.test.io/v1",
+				"metadata": map[string]interface{}{
+					"name":      attachmentName,
+					"namespace": req.Parent.GetNamespace(),
+					"labels": map[string]interface{}{
+						"watch-name": req.Parent.GetName(),
+						"version":    version,
+					},
+				},
+			},
+		}
+		resp := composite.SyncHookResponse{
+			Children: []*unstructured.Unstructured{child},
+		}
+		return json.Marshal(resp)
+	})
+
+	// veto the very first update & let the subsequent
+	// updates to proceed
+	preUpdateChildHook := f.ServeWebhook(func(body []byte) ([]byte, error) {
+		req := composite.UpdateChildHookRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+		resp := composite.PreUpdateChildHookResponse{}
+		if atomic.AddInt32(&preUpdateCount, 1) == 1 {
+			resp.Veto = true
+			resp.Message = "first update is vetoed"
+			resp.ResyncAfterSeconds = 1
+		}
+		return json.Marshal(resp)
+	})
+
+	// report the updated child as ready
+	postUpdateChildHook := f.ServeWebhook(func(body []byte) ([]byte, error) {
+		req := composite.UpdateChildHookRequest{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+		atomic.AddInt32(&postUpdateCount, 1)
+		resp := composite.PostUpdateChildHookResponse{
+			Ready: true,
+		}
+		return json.Marshal(resp)
+	})
+
+	// Run the testcase here
+	//
+	// NOTE:
+	// 	TestSteps are executed in their defined order
+	result, err := f.Test(
+		[]framework.TestStep{
+			framework.TestStep{
+				Name: "create-test-namespace",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "Namespace",
+							"apiVersion": "v1",
+							"metadata": map[string]interface{}{
+								"name": namespaceName,
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-watch-crd",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": "apiextensions.k8s.io/v1beta1",
+							"kind":       "CustomResourceDefinition",
+							"metadata": map[string]interface{}{
+								"name": "prepostupdatechildcctlwatches.integration.test.io",
+							},
+							"spec": map[string]interface{}{
+								"version": "v1",
+								"group":   "integration.test.io",
+								"scope":   "Namespaced",
+								"names": map[string]interface{}{
+									"kind":     "PrePostUpdateChildCCtlWatch",
+									"listKind": "PrePostUpdateChildCCtlWatchList",
+									"singular": "prepostupdatechildcctlwatch",
+									"plural":   "prepostupdatechildcctlwatches",
+								},
+								"versions": []interface{}{
+									map[string]interface{}{
+										"name":    "v1",
+										"served":  true,
+										"storage": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-attachment-crd",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"apiVersion": "apiextensions.k8s.io/v1beta1",
+							"kind":       "CustomResourceDefinition",
+							"metadata": map[string]interface{}{
+								"name": "prepostupdatechildcctlattachments.integration.test.io",
+							},
+							"spec": map[string]interface{}{
+								"version": "v1",
+								"group":   "integration.test.io",
+								"scope":   "Namespaced",
+								"names": map[string]interface{}{
+									"kind":     "PrePostUpdateChildCCtlAttachment",
+									"listKind": "PrePostUpdateChildCCtlAttachmentList",
+									"singular": "prepostupdatechildcctlattachment",
+									"plural":   "prepostupdatechildcctlattachments",
+								},
+								"versions": []interface{}{
+									map[string]interface{}{
+										"name":    "v1",
+										"served":  true,
+										"storage": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-watch-resource",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "PrePostUpdateChildCCtlWatch",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      watchName,
+								"namespace": namespaceName,
+							},
+							"spec": map[string]interface{}{
+								"version": "v1",
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "create-composite-controller",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "CompositeController",
+							"apiVersion": "metac.openebs.io/v1alpha1",
+							"metadata": map[string]interface{}{
+								"name":      "pre-post-update-child-cctl",
+								"namespace": namespaceName,
+							},
+							"spec": map[string]interface{}{
+								"generateSelector": kubernetes.BoolPtr(true),
+								"parentResource": map[string]interface{}{
+									"apiVersion": "integration.test.io/v1",
+									"resource":   "prepostupdatechildcctlwatches",
+								},
+								"childResources": []interface{}{
+									map[string]interface{}{
+										"apiVersion": "integration.test.io/v1",
+										"resource":   "prepostupdatechildcctlattachments",
+										"updateStrategy": map[string]interface{}{
+											"method": "InPlace",
+										},
+									},
+								},
+								"hooks": map[string]interface{}{
+									"sync": map[string]interface{}{
+										"webhook": map[string]interface{}{
+											"url": kubernetes.StringPtr(syncHook.URL),
+										},
+									},
+									"preUpdateChild": map[string]interface{}{
+										"webhook": map[string]interface{}{
+											"url": kubernetes.StringPtr(preUpdateChildHook.URL),
+										},
+									},
+									"postUpdateChild": map[string]interface{}{
+										"webhook": map[string]interface{}{
+											"url": kubernetes.StringPtr(postUpdateChildHook.URL),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "assert-attachment-with-version-v1",
+				Assert: &framework.Assert{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "PrePostUpdateChildCCtlAttachment",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      attachmentName,
+								"namespace": namespaceName,
+								"labels": map[string]interface{}{
+									"version": "v1",
+								},
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "update-watch-to-version-v2",
+				Apply: framework.Apply{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "PrePostUpdateChildCCtlWatch",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      watchName,
+								"namespace": namespaceName,
+							},
+							"spec": map[string]interface{}{
+								"version": "v2",
+							},
+						},
+					},
+				},
+			},
+			framework.TestStep{
+				Name: "assert-attachment-with-version-v2",
+				Assert: &framework.Assert{
+					State: &unstructured.Unstructured{
+						Object: map[string]interface{}{
+							"kind":       "PrePostUpdateChildCCtlAttachment",
+							"apiVersion": "integration.test.io/v1",
+							"metadata": map[string]interface{}{
+								"name":      attachmentName,
+								"namespace": namespaceName,
+								"labels": map[string]interface{}{
+									"version": "v2",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Test failed: %+v", err)
+	}
+	if result.Phase == framework.TestStepResultFailed {
+		t.Fatalf("Test failed:\n%s", result)
+	}
+
+	// first update was vetoed & hence update should have
+	// been attempted at least twice
+	if count := atomic.LoadInt32(&preUpdateCount); count < 2 {
+		t.Fatalf(
+			"Test failed: Want preUpdateChild invocations >= 2: Got %d",
+			count,
+		)
+	}
+	if count := atomic.LoadInt32(&postUpdateCount); count < 1 {
+		t.Fatalf(
+			"Test failed: Want postUpdateChild invocations >= 1: Got %d",
+			count,
+		)
+	}
+	klog.Infof("Test passed:\n%s", result)
+}
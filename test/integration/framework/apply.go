@@ -174,6 +174,7 @@ func (a *Applyable) postCreateCRD(
 			}
 			return true, nil
 		},
+		"%s",
 		message,
 	)
 	return err
@@ -316,6 +317,7 @@ func (a *Applyable) applyCRD() (*ApplyResult, error) {
 			}
 			return true, nil
 		},
+		"%s",
 		message,
 	)
 	if err != nil {
@@ -431,6 +433,7 @@ func (a *Applyable) updateResource() (*ApplyResult, error) {
 			}
 			return true, nil
 		},
+		"%s",
 		message,
 	)
 	if err != nil {
@@ -475,6 +478,7 @@ func (a *Applyable) applyResource() (*ApplyResult, error) {
 			}
 			return true, nil
 		},
+		"%s",
 		message,
 	)
 	if err != nil {
@@ -71,7 +71,7 @@ func (c *Command) wrapErrorf(err error, format string, args ...interface{}) erro
 			details = append(details, outstr)
 		}
 	}
-	return errors.Wrapf(
+	return errors.Wrap(
 		err,
 		strings.Join(details, ": "),
 	)
@@ -154,7 +154,7 @@ func (ps *ProcessState) Start(stdout, stderr io.Writer) (err error) {
 		)
 	} else {
 		startDetectStream := gbytes.NewBuffer()
-		ready = startDetectStream.Detect(ps.StartMessage)
+		ready = startDetectStream.Detect("%s", ps.StartMessage)
 		stderr = safeMultiWriter(stderr, startDetectStream)
 	}
 
@@ -234,3 +234,39 @@ func (f *Fixture) StartMetacFromGenericControllerConfig(
 	}
 	return stopMetacServer
 }
+
+// StartMetacWithLeaderElectionFromGenericControllerConfig starts
+// Metac based on the given config. Metac participates in a leader
+// election that uses the provided lease lock. It returns the metac
+// server as well as the stop function that should be invoked by the
+// caller once caller's task is done.
+func (f *Fixture) StartMetacWithLeaderElectionFromGenericControllerConfig(
+	gctlAsConfigFn func() ([]*v1alpha1.GenericController, error),
+	lockNamespace string,
+	lockName string,
+) (metac *server.ConfigServer, stop func()) {
+	var mserver = &server.Server{
+		Config:            APIServerConfig,
+		DiscoveryInterval: 500 * time.Millisecond,
+		InformerRelist:    30 * time.Minute,
+		LeaderElection: &server.LeaderElectionConfig{
+			Enabled: true,
+			// Keep these durations small so that tests can
+			// verify change in leadership quickly
+			LeaseDuration: 4 * time.Second,
+			RenewDeadline: 3 * time.Second,
+			RetryPeriod:   500 * time.Millisecond,
+			LockNamespace: lockNamespace,
+			LockName:      lockName,
+		},
+	}
+	metacServer := &server.ConfigServer{
+		Server:                        mserver,
+		GenericControllerConfigLoadFn: gctlAsConfigFn,
+	}
+	stopMetacServer, err := metacServer.Start(5)
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return metacServer, stopMetacServer
+}
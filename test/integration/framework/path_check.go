@@ -390,6 +390,7 @@ func (pc *PathChecking) assertPathAndValue(context string) (bool, error) {
 			}
 			return pc.assertValue(observed)
 		},
+		"%s",
 		context,
 	)
 	return err == nil, err
@@ -231,6 +231,7 @@ func (sc *StateChecking) isMergeEqualsObserved(context string) (bool, error) {
 			}
 			return true, nil
 		},
+		"%s",
 		context,
 	)
 	klog.V(2).Infof(
@@ -340,6 +341,7 @@ func (sc *StateChecking) assertNotFound() {
 			// condition fails since resource still exists
 			return false, nil
 		},
+		"%s",
 		message,
 	)
 	if err != nil {
@@ -396,6 +398,7 @@ func (sc *StateChecking) assertListCountEquals() {
 			}
 			return false, nil
 		},
+		"%s",
 		message,
 	)
 	if err != nil {
@@ -433,6 +436,7 @@ func (sc *StateChecking) assertListCountNotEquals() {
 			}
 			return false, nil
 		},
+		"%s",
 		message,
 	)
 	if err != nil {